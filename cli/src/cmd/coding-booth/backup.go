@@ -0,0 +1,70 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+func runBackup(args []string, version string) {
+	flags := docker.DockerFlags{}
+	engine := ""
+	image := ""
+	output := ""
+	configFile := ""
+
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		switch arg {
+		case "--output", "-o":
+			output = needArgValue("backup", args, index)
+			index++
+		case "--config":
+			configFile = needArgValue("backup", args, index)
+			index++
+		case "--engine":
+			engine = needArgValue("backup", args, index)
+			index++
+		case "--verbose":
+			flags.Verbose = true
+		case "--dryrun":
+			flags.Dryrun = true
+		default:
+			if len(arg) == 0 || arg[0] == '-' || image != "" {
+				exitUnknownOption("backup", arg)
+			}
+			image = arg
+		}
+	}
+	if image == "" {
+		fmt.Fprintln(os.Stderr, "Error: backup requires an image (e.g. one created with 'commit')")
+		fmt.Fprintf(os.Stderr, "Usage: %s backup <IMAGE> [--output <FILE>]\n", scriptName())
+		os.Exit(1)
+	}
+	if output == "" {
+		output = booth.DefaultBackupFile(image)
+	}
+
+	manifest, err := booth.BackupImage(newDockerClient(engine), flags, image, output, configFile, version)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+	if flags.Dryrun {
+		return
+	}
+
+	fmt.Printf("💾 Backed up image '%s' to '%s'.\n", image, output)
+	if manifest.Variant != "" {
+		fmt.Printf("   Variant: %s  Version: %s\n", manifest.Variant, manifest.Version)
+	}
+	if manifest.HasConfig {
+		fmt.Printf("   Config:  %s\n", manifest.ConfigFile)
+	}
+}
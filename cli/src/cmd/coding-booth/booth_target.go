@@ -0,0 +1,140 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+// targetArgs holds the options shared by the commands that act on an existing booth.
+type targetArgs struct {
+	names  []string
+	code   string
+	engine string
+	flags  docker.DockerFlags
+	docker docker.DockerClient
+}
+
+// parseCommon consumes a shared option (--name, --code, --engine, --verbose, --dryrun or a positional booth name)
+// at args[index] and returns how many arguments were consumed; 0 means the option is not a shared one.
+func (target *targetArgs) parseCommon(command string, args []string, index int) int {
+	arg := args[index]
+	switch arg {
+	case "--name":
+		target.names = append(target.names, needArgValue(command, args, index))
+		return 2
+	case "--code":
+		target.code = needArgValue(command, args, index)
+		return 2
+	case "--engine":
+		target.engine = needArgValue(command, args, index)
+		return 2
+	case "--verbose":
+		target.flags.Verbose = true
+		return 1
+	case "--dryrun":
+		target.flags.Dryrun = true
+		return 1
+	}
+
+	if len(arg) > 0 && arg[0] != '-' {
+		target.names = append(target.names, arg)
+		return 1
+	}
+	return 0
+}
+
+// resolve finds the booths named on the command line, or the booth of the code path (default: current directory).
+// It exits with a helpful message when a booth cannot be found.
+func (target *targetArgs) resolve(command string, filter string) []booth.BoothTarget {
+	names := target.names
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	targets := make([]booth.BoothTarget, 0, len(names))
+	for _, name := range names {
+		found, err := booth.FindBooth(target.client(), target.flags, name, target.code, filter)
+		if err != nil {
+			exitWithBoothError(command, filter, err)
+		}
+		targets = append(targets, found)
+	}
+	return targets
+}
+
+// client returns the DockerClient of the engine given with --engine (see newDockerClient).
+func (target *targetArgs) client() docker.DockerClient {
+	if target.docker == nil {
+		target.docker = newDockerClient(target.engine)
+	}
+	return target.docker
+}
+
+// newDockerClient returns the DockerClient of an engine ("" means CB_ENGINE, or else the docker CLI).
+// It exits when the engine is unknown.
+func newDockerClient(engine string) docker.DockerClient {
+	if engine == "" {
+		engine = os.Getenv("CB_ENGINE")
+	}
+	client, err := docker.NewDockerClient(engine)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return client
+}
+
+// exitWithBoothError prints a lookup error with a hint and exits.
+func exitWithBoothError(command string, filter string, err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v.\n", err)
+
+	var notFound *booth.BoothNotFoundError
+	if errors.As(err, &notFound) {
+		listHint := "list"
+		switch filter {
+		case booth.ListRunning:
+			listHint = "list --running"
+		case booth.ListStopped:
+			listHint = "list --stopped"
+		}
+		fmt.Fprintf(os.Stderr, "Use '%s %s' to see available booths.\n", scriptName(), listHint)
+		if notFound.Name == "" {
+			fmt.Fprintf(os.Stderr, "Use '%s %s --name <NAME>' or '%s %s --code <PATH>' to pick a booth.\n",
+				scriptName(), command, scriptName(), command)
+		}
+	}
+	os.Exit(1)
+}
+
+// needArgValue returns the value following a flag or exits if it is missing.
+func needArgValue(command string, args []string, index int) string {
+	if index+1 >= len(args) || args[index+1] == "" {
+		fmt.Fprintf(os.Stderr, "Error: %s %s requires a value\n", command, args[index])
+		os.Exit(1)
+	}
+	return args[index+1]
+}
+
+// exitUnknownOption reports an unsupported option for a command and exits.
+func exitUnknownOption(command string, arg string) {
+	fmt.Fprintf(os.Stderr, "Error: unknown option for %s: %s\n", command, arg)
+	fmt.Fprintf(os.Stderr, "Use '%s help' for usage information\n", scriptName())
+	os.Exit(1)
+}
+
+// scriptName returns the name the CLI was invoked with.
+func scriptName() string {
+	if len(os.Args) > 0 && os.Args[0] != "" {
+		return filepath.Base(os.Args[0])
+	}
+	return "coding-booth"
+}
@@ -0,0 +1,57 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runCommit(args []string) {
+	target := targetArgs{}
+	tag := ""
+	message := ""
+
+	for index := 0; index < len(args); {
+		if consumed := target.parseCommon("commit", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		switch args[index] {
+		case "--tag", "-t":
+			tag = needArgValue("commit", args, index)
+			index++
+		case "--message", "-m":
+			message = needArgValue("commit", args, index)
+			index++
+		default:
+			exitUnknownOption("commit", args[index])
+		}
+		index++
+	}
+	if len(target.names) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: commit accepts only one booth")
+		os.Exit(1)
+	}
+
+	found := target.resolve("commit", booth.ListAll)[0]
+	if tag == "" {
+		tag = booth.DefaultCommitTag(found.Info.Name, time.Now())
+	}
+
+	if err := booth.CommitBooth(target.client(), target.flags, found, tag, message); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("📸 Committed booth '%s' as image '%s'.\n", found.Info.Name, tag)
+	runCommand := fmt.Sprintf("%s run --image %s", scriptName(), tag)
+	if found.Info.Variant != "" {
+		runCommand += " --variant " + found.Info.Variant
+	}
+	fmt.Printf("   Run it with: %s\n", runCommand)
+}
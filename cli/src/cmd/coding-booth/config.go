@@ -0,0 +1,146 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	boothinit "github.com/nawaman/codingbooth/src/pkg/booth/init"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// configBoundary initializes the app context with the run options given to a config subcommand.
+type configBoundary struct {
+	boothinit.DefaultInitializeAppContextBoundary
+	args []string
+}
+
+func (boundary configBoundary) ArgList() ilist.List[string] {
+	return ilist.NewListFromSlice(append([]string{os.Args[0]}, boundary.args...))
+}
+
+func runConfig(args []string, version string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: config requires a subcommand (explain, profiles, validate, schema, migrate)")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "explain":
+		runConfigExplain(args[1:], version)
+	case "profiles":
+		runConfigProfiles(args[1:])
+	case "validate":
+		runConfigValidate(args[1:], version)
+	case "schema":
+		runConfigSchema()
+	case "migrate":
+		runConfigMigrate(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown config subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// runConfigExplain prints every config value with its source, as the run options would resolve it.
+func runConfigExplain(args []string, version string) {
+	_, explanation, err := boothinit.ExplainAppContext(version, configBoundary{args: args})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	fmt.Print(explanation)
+}
+
+// runConfigProfiles lists the [profile.<name>] tables of the config files.
+func runConfigProfiles(args []string) {
+	profiles, err := boothinit.ConfigProfiles(configBoundary{args: args})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if len(profiles) == 0 {
+		fmt.Println("No profiles ([profile.<name>] tables) in the config files.")
+		return
+	}
+	fmt.Print(boothinit.FormatConfigProfiles(profiles))
+}
+
+// runConfigValidate checks the config files and the resolved config (for CI); it exits with 1 if there are problems.
+func runConfigValidate(args []string, version string) {
+	validation, err := boothinit.ValidateConfig(version, configBoundary{args: args})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if len(validation.Problems) > 0 {
+		for _, problem := range validation.Problems {
+			fmt.Fprintln(os.Stderr, "❌", problem)
+		}
+		os.Exit(1)
+	}
+	if len(validation.Files) == 0 {
+		fmt.Println("✅ Config is valid (no config files).")
+		return
+	}
+	fmt.Println("✅ Config is valid:")
+	for _, file := range validation.Files {
+		fmt.Println("   ", file)
+	}
+}
+
+// runConfigSchema prints the JSON Schema of config.toml.
+func runConfigSchema() {
+	schema, err := appctx.ConfigSchemaJSON()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(schema)
+}
+
+// runConfigMigrate shows the migration of the config files to the current keys and layout (as a diff);
+// with --write, it applies it.
+func runConfigMigrate(args []string) {
+	write := slices.Contains(args, "--write")
+	args = slices.DeleteFunc(slices.Clone(args), func(arg string) bool { return arg == "--write" })
+
+	migrations, err := boothinit.MigrateConfig(configBoundary{args: args})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if len(migrations) == 0 {
+		fmt.Println("✅ Config is up to date (nothing to migrate).")
+		return
+	}
+
+	for _, migration := range migrations {
+		diff := migration.Diff()
+		if migration.Moved() {
+			fmt.Printf("📦 %s -> %s\n", migration.From, migration.To)
+		} else if diff == "" {
+			fmt.Printf("📝 %s\n", migration.To)
+		}
+		fmt.Print(diff)
+		for _, change := range migration.Changes {
+			fmt.Println("   -", change)
+		}
+		fmt.Println()
+	}
+
+	if !write {
+		fmt.Println("Nothing written: run 'coding-booth config migrate --write' to apply.")
+		return
+	}
+	if err := boothinit.ApplyConfigMigrations(migrations); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Config migrated.")
+}
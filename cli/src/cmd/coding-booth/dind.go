@@ -0,0 +1,67 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+func runDind(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: dind requires a subcommand (prune)")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "prune":
+		runDindPrune(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown dind subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// runDindPrune removes the DinD cache volumes no container uses (all of them, or the ones of --project).
+func runDindPrune(args []string) {
+	flags := docker.DockerFlags{}
+	project := ""
+	engine := ""
+
+	for index := 0; index < len(args); index++ {
+		switch args[index] {
+		case "--project":
+			project = needArgValue("dind prune", args, index)
+			index++
+		case "--engine":
+			engine = needArgValue("dind prune", args, index)
+			index++
+		case "--verbose":
+			flags.Verbose = true
+		case "--dryrun":
+			flags.Dryrun = true
+		default:
+			exitUnknownOption("dind prune", args[index])
+		}
+	}
+
+	removed, inUse, err := booth.PruneDindCache(newDockerClient(engine), flags, project)
+	for _, volume := range removed {
+		fmt.Printf("🗑️  Removed DinD cache '%s'.\n", volume)
+	}
+	for _, volume := range inUse {
+		fmt.Printf("⏭️  Kept DinD cache '%s' (used by a container; stop or remove its booth first).\n", volume)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+	if len(removed) == 0 && len(inUse) == 0 && !flags.Dryrun {
+		fmt.Println("No DinD cache to prune.")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runExec(args []string) {
+	target := targetArgs{}
+	var cmds []string
+
+	for index := 0; index < len(args); {
+		if args[index] == "--" {
+			cmds = args[index+1:]
+			break
+		}
+		if consumed := target.parseCommon("exec", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		exitUnknownOption("exec", args[index])
+	}
+	if len(target.names) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: exec accepts only one booth; put the command after '--'")
+		os.Exit(1)
+	}
+
+	found := target.resolve("exec", booth.ListRunning)[0]
+	err := booth.ExecInBooth(target.client(), target.flags, found, cmds)
+	if err != nil {
+		if silentErr, ok := err.(*booth.SilentExitError); ok {
+			os.Exit(silentErr.ExitCode)
+		}
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+}
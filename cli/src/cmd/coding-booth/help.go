@@ -29,15 +29,18 @@ BOOTSTRAP OPTIONS (CLI or defaults; evaluated before environmental variable and
                          (default: current directory)
   --config <file>        Path to the config file to load
                          (default: <code>/.booth/config.toml)
+  --profile <name>       Apply the [profile.<name>] tables of the config files
+                         (also CB_PROFILE; exported to the booth as CB_PROFILE)
 
 CONFIG PRECEDENCE:
-  options (CLI) > config file (TOML) > environment (ENV) > defaults
+  options (CLI) > config file profile > config file (TOML) > environment (ENV) > defaults
   NOTE: --code and --config are bootstrap options and are taken only from
         CLI (first pass) or defaults.
 
 GENERAL RUN OPTIONS:
   --dryrun               Print docker commands without executing them
   --verbose              Print extra debugging information
+  --explain              Print every config value with its source before running
 
 IMAGE SELECTION (precedence: --image > --dockerfile > prebuilt):
   --dockerfile <path>    Build locally from a Dockerfile (file or directory)
@@ -64,13 +67,85 @@ RUNTIME OPTIONS:
                          n      : any valid TCP port (1–65535)
                          RANDOM : pick a random free port ≥ 10000
                          NEXT   : pick the next available free port ≥ 10000
+                         RANDOM and NEXT skip the ports of other booths (even
+                         stopped ones) and of concurrent launches
+  --bind-address <ip>    Host address the ports are published on (default: 127.0.0.1,
+                         only this machine); 0.0.0.0 publishes on all interfaces
   --env-file <file>      Provide an --env-file to docker run
                          Use 'none' to disable auto-detection of <code>/.env
 
 CONTAINER MODE:
   --daemon               Run the booth container in the background
+  --wait                 With --daemon, return only once the booth UI answers
+  --open                 Open the booth UI in the host browser once it answers
   --dind                 Enable a Docker-in-Docker sidecar and set DOCKER_HOST
+  --dind-cache <mode>    Image cache volume of the DinD sidecar (/var/lib/docker):
+                         project (default, cb-dind-cache-<project>), shared
+                         (cb-dind-cache) or none (fresh sidecar every run)
   --keep-alive           Do not remove the container when stopped
+  --engine <name>        Container engine: cli/docker (default) runs the docker CLI,
+                         api talks to the Docker Engine API (unix socket or DOCKER_HOST),
+                         podman or nerdctl run that CLI instead
+
+BOOTH COMMANDS:
+  list [--running|--stopped] [--json] [--quiet]
+                         List booth containers (found by their cb.* labels)
+  start [<name>|--name <name>|--code <path>] [--daemon]
+                         Start a stopped (--keep-alive) booth with its DinD sidecar/network
+                         (default booth: the one started from the current directory)
+  stop [<name>|--name <name>|--code <path>] [--time <sec>] [--force]
+                         Stop a running booth and its DinD sidecar; booths run without
+                         --keep-alive are removed (like --rm) together with sidecar/network
+  restart [<name>|--name <name>|--code <path>] [--time <sec>]
+                         Restart a running booth (and its DinD sidecar)
+  remove [<name>...|--name <name>|--code <path>] [--force]
+                         Remove a stopped booth with its DinD sidecar and network
+  exec [<name>|--name <name>|--code <path>] [-- <command>...]
+                         Open a login shell (or run a command) in a running booth as the
+                         coder user in /home/coder/code; the exit code is forwarded
+  logs [<name>|--name <name>|--code <path>] [--follow] [--since <time>]
+       [--phase <n|name>] [--dind] [--timestamps]
+                         Show booth logs; --phase keeps one booth-entry section (needs a
+                         booth run with --verbose): 1) user/group 2) sudo 3) profile
+                         4) home 5) startup hooks 6) command; --dind interleaves the sidecar
+  commit [<name>|--name <name>|--code <path>] [--tag <image>] [--message <msg>]
+                         Save a booth (running or stopped) as an image usable with --image
+                         (default tag: codingbooth-local:<name>-<timestamp>)
+  backup <image> [--output <file.tar.gz>] [--config <path>]
+                         Save an image to a tar.gz with a manifest (variant, version) and
+                         the booth's config.toml
+  restore <file.tar.gz> [--config-out <path>]
+                         Load an image from a backup and show how to run it
+                         (the booth commands take --engine <name>, default: CB_ENGINE)
+  dind prune [--project <name>] [--engine <name>]
+                         Remove the DinD cache volumes (of all projects, or of one)
+                         that no container uses
+
+PROJECT COMMANDS:
+  init [--template <name>] [--variant <name>] [--version <tag>] [--code <path>]
+       [--template-dir <dir>] [--force] [--list-templates]
+                         Create .booth/Dockerfile and .booth/config.toml from a template
+                         (base, go, python, java, js, dind, kind; picked interactively
+                         without --template); existing files are kept unless --force;
+                         --template-dir (or CB_TEMPLATE_DIR) adds local templates
+
+CONFIG COMMANDS:
+  config explain [options]
+                         Print every config value with its source (default, detected,
+                         CB_* env var, config file and line, CLI flag, derived) and the
+                         values it overrode; options are the same as for run
+  config profiles [--profile <name>] [--code <path>] [--config <path>]
+                         List the profiles of the config files (the selected one is marked)
+  config validate [options]
+                         Check the config files (unknown keys, wrong types, invalid ports,
+                         keys that cannot be combined) with file:line; exit code 1 on
+                         problems (for CI); options are the same as for run
+  config schema          Print the JSON Schema of config.toml (for editors and linters)
+  config migrate [--write] [options]
+                         Show the changes (as a diff) that bring the config files to the
+                         current keys and layout: renamed keys, semicolon-separated lists
+                         as arrays, the old home seed folder, .ws/ moved to .booth/;
+                         --write applies them
 
 COMMANDS:
   All arguments after '--' are executed *inside* the container instead of starting
@@ -88,10 +163,37 @@ NOTES:
 
   - In daemon mode, do not pass commands after '--'. Stop the container with:
         docker stop <container-name>
+    or, to also stop its DinD sidecar and network:
+        %s stop <container-name>
+
+  - Booth containers are labeled with cb.* labels (cb.managed, cb.project,
+    cb.variant, cb.code-path, cb.port, cb.ports, cb.created-at, cb.version)
+    so they can be found later with 'list'.
 
   - With --dind, a docker:dind sidecar runs on a private network and the main
     container uses DOCKER_HOST=tcp://<sidecar>:2375.
 
+  - Config files are applied in order (later wins): the user config
+    (~/.config/codingbooth/config.toml, or CB_USER_CONFIG; 'none' to disable),
+    the project config (--config or <code>/.booth/config.toml) and the local
+    config (<code>/.booth/config.local.toml). run-args, build-args and
+    common-args are appended unless a layer sets replace = ["run-args", ...].
+
+  - [[mounts]] (source, target, mode, seed, optional), [ports] (name = port)
+    and [env] (NAME = "value") tables in config.toml become -v, -p and -e
+    flags. Seed mounts go read-only to /etc/cb-home-seed; optional mounts
+    with a missing source are skipped with a warning.
+    A [ports] host port can be NEXT or RANDOM (e.g. web = "NEXT:3000");
+    each port is exported to the booth as CB_PORT_<NAME> (its host port).
+
+  - setups = ["go@1.23", "gh"] in config.toml installs setup scripts of the
+    image (<name>--setup.sh <version>) in a local image built without a
+    Dockerfile (one layer per setup, in order).
+
+  - String values in config.toml are interpolated from the environment:
+    $VAR, ${VAR:-default}, ${VAR:?message} (fails when VAR is not set) and
+    ~ at the start (the [hooks] are left to the shell).
+
 EXAMPLES:
   # Prebuilt, foreground
   %s --variant base --version latest --code /path/to/code
@@ -120,5 +222,6 @@ EXAMPLES:
 		scriptName,
 		scriptName,
 		scriptName,
+		scriptName,
 	)
 }
@@ -0,0 +1,127 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+	"github.com/nawaman/codingbooth/src/pkg/booth/scaffold"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+// runInit creates the .booth folder (Dockerfile and config.toml) of a project from a template.
+func runInit(args []string, version string) {
+	name := ""
+	code := "."
+	variant := ""
+	imageVersion := ""
+	templateDir := os.Getenv("CB_TEMPLATE_DIR")
+	force := false
+	listTemplates := false
+
+	for index := 0; index < len(args); index++ {
+		switch args[index] {
+		case "--template", "-t":
+			name = needArgValue("init", args, index)
+			index++
+		case "--template-dir":
+			templateDir = needArgValue("init", args, index)
+			index++
+		case "--code":
+			code = needArgValue("init", args, index)
+			index++
+		case "--variant":
+			variant = needArgValue("init", args, index)
+			index++
+		case "--version":
+			imageVersion = needArgValue("init", args, index)
+			index++
+		case "--force", "-f":
+			force = true
+		case "--list-templates":
+			listTemplates = true
+		default:
+			exitUnknownOption("init", args[index])
+		}
+	}
+
+	templates, err := scaffold.Templates(templateDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if listTemplates {
+		scaffold.WriteTemplateList(os.Stdout, templates)
+		return
+	}
+
+	var template scaffold.Template
+	switch {
+	case name != "":
+		template, err = scaffold.FindTemplate(name, templateDir)
+	case docker.HasInteractiveTTY():
+		template, err = scaffold.PickTemplate(templates, os.Stdin, os.Stdout)
+	default:
+		err = errors.New("init requires --template <name> (see --list-templates)")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	if variant == "" {
+		variant = template.Variant
+	}
+	variant, ok := booth.NormalizeVariant(variant)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown variant '%s' (valid: base|notebook|codeserver|desktop-xfce|desktop-kde)\n", variant)
+		os.Exit(1)
+	}
+
+	codePath, err := filepath.Abs(code)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	data := scaffold.TemplateData{
+		Template:   template.Name,
+		Project:    filepath.Base(codePath),
+		Variant:    variant,
+		Version:    imageVersion,
+		PinVersion: imageVersion != "",
+		Args:       template.Args,
+		Setups:     template.Setups,
+	}
+	if data.Version == "" {
+		data.Version = defaultImageVersion(version)
+	}
+
+	written, err := scaffold.Scaffold(codePath, template, data, force)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Created a booth from the '%s' template (%s):\n", template.Name, variant)
+	for _, path := range written {
+		if relative, err := filepath.Rel(codePath, path); err == nil {
+			path = relative
+		}
+		fmt.Println("   ", path)
+	}
+	fmt.Printf("   Start it with: %s run\n", scriptName())
+}
+
+// defaultImageVersion returns the image version of the CLI version ("latest" for a dev build).
+func defaultImageVersion(version string) string {
+	if version == "" || version == "dev" || strings.Contains(version, "-") {
+		return "latest"
+	}
+	return version
+}
@@ -0,0 +1,66 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+func runList(args []string) {
+	filter := booth.ListAll
+	asJson := false
+	quiet := false
+	engine := ""
+	flags := docker.DockerFlags{Silent: true}
+
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		switch arg {
+		case "--running":
+			filter = booth.ListRunning
+		case "--stopped":
+			filter = booth.ListStopped
+		case "--json":
+			asJson = true
+		case "--quiet", "-q":
+			quiet = true
+		case "--engine":
+			engine = needArgValue("list", args, index)
+			index++
+		case "--verbose":
+			flags.Verbose = true
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown option for list: %s\n", arg)
+			os.Exit(1)
+		}
+	}
+
+	booths, err := booth.ListBooths(newDockerClient(engine), flags, filter)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+
+	switch {
+	case asJson:
+		if booths == nil {
+			booths = []booth.BoothInfo{}
+		}
+		output, _ := json.MarshalIndent(booths, "", "  ")
+		fmt.Println(string(output))
+	case quiet:
+		for _, info := range booths {
+			fmt.Println(info.Name)
+		}
+	default:
+		booth.WriteBoothTable(os.Stdout, booths, time.Now())
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runLogs(args []string) {
+	target := targetArgs{}
+	options := booth.LogOptions{}
+
+	for index := 0; index < len(args); {
+		if consumed := target.parseCommon("logs", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		switch args[index] {
+		case "--follow", "-f":
+			options.Follow = true
+		case "--since":
+			options.Since = needArgValue("logs", args, index)
+			index++
+		case "--phase":
+			options.Phase = needArgValue("logs", args, index)
+			index++
+		case "--dind":
+			options.Dind = true
+		case "--timestamps":
+			options.Timestamps = true
+		default:
+			exitUnknownOption("logs", args[index])
+		}
+		index++
+	}
+	if len(target.names) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: logs accepts only one booth")
+		os.Exit(1)
+	}
+
+	found := target.resolve("logs", booth.ListAll)[0]
+	if err := booth.StreamBoothLogs(target.client(), target.flags, found, options, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+}
@@ -26,6 +26,45 @@ func main() {
 		case "run":
 			runBooth(version)
 			return
+		case "list":
+			runList(os.Args[2:])
+			return
+		case "start":
+			runStart(os.Args[2:])
+			return
+		case "stop":
+			runStop(os.Args[2:])
+			return
+		case "restart":
+			runRestart(os.Args[2:])
+			return
+		case "remove", "rm":
+			runRemove(os.Args[2:])
+			return
+		case "exec":
+			runExec(os.Args[2:])
+			return
+		case "logs":
+			runLogs(os.Args[2:])
+			return
+		case "commit":
+			runCommit(os.Args[2:])
+			return
+		case "backup":
+			runBackup(os.Args[2:], version)
+			return
+		case "restore":
+			runRestore(os.Args[2:])
+			return
+		case "config":
+			runConfig(os.Args[2:], version)
+			return
+		case "dind":
+			runDind(os.Args[2:])
+			return
+		case "init":
+			runInit(os.Args[2:], version)
+			return
 		default:
 			// If it starts with --, treat as run with options
 			if len(command) > 0 && command[0] == '-' {
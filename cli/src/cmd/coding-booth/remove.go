@@ -0,0 +1,39 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runRemove(args []string) {
+	target := targetArgs{}
+	force := false
+
+	for index := 0; index < len(args); {
+		if consumed := target.parseCommon("remove", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		switch args[index] {
+		case "--force", "-f":
+			force = true
+		default:
+			exitUnknownOption("remove", args[index])
+		}
+		index++
+	}
+
+	for _, found := range target.resolve("remove", booth.ListAll) {
+		if err := booth.RemoveBooth(target.client(), target.flags, found, force); err != nil {
+			fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🗑️  Removed booth '%s'.\n", found.Info.Name)
+	}
+}
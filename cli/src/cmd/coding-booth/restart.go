@@ -0,0 +1,40 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runRestart(args []string) {
+	target := targetArgs{}
+	timeout := ""
+
+	for index := 0; index < len(args); {
+		if consumed := target.parseCommon("restart", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		switch args[index] {
+		case "--time", "-t":
+			timeout = needArgValue("restart", args, index)
+			index++
+		default:
+			exitUnknownOption("restart", args[index])
+		}
+		index++
+	}
+
+	for _, found := range target.resolve("restart", booth.ListRunning) {
+		if err := booth.RestartBooth(target.client(), target.flags, found, timeout); err != nil {
+			fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔄 Restarted booth '%s'.\n", found.Info.Name)
+	}
+}
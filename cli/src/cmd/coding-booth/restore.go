@@ -0,0 +1,73 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+func runRestore(args []string) {
+	flags := docker.DockerFlags{}
+	engine := ""
+	input := ""
+	configOutput := ""
+
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		switch arg {
+		case "--config-out":
+			configOutput = needArgValue("restore", args, index)
+			index++
+		case "--engine":
+			engine = needArgValue("restore", args, index)
+			index++
+		case "--verbose":
+			flags.Verbose = true
+		case "--dryrun":
+			flags.Dryrun = true
+		default:
+			if len(arg) == 0 || arg[0] == '-' || input != "" {
+				exitUnknownOption("restore", arg)
+			}
+			input = arg
+		}
+	}
+	if input == "" {
+		fmt.Fprintln(os.Stderr, "Error: restore requires a backup file")
+		fmt.Fprintf(os.Stderr, "Usage: %s restore <FILE> [--config-out <PATH>]\n", scriptName())
+		os.Exit(1)
+	}
+
+	manifest, err := booth.RestoreBackup(newDockerClient(engine), flags, input, configOutput)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("📦 Restored image '%s' from '%s'.\n", manifest.Image, input)
+	if manifest.Variant != "" {
+		fmt.Printf("   Variant: %s  Version: %s\n", manifest.Variant, manifest.Version)
+	}
+	if manifest.HasConfig {
+		if configOutput != "" {
+			fmt.Printf("   Config:  written to %s\n", configOutput)
+		} else {
+			fmt.Printf("   Config:  included (originally %s); extract it with --config-out <PATH>\n", manifest.ConfigFile)
+		}
+	}
+
+	runCommand := fmt.Sprintf("%s run --image %s", scriptName(), manifest.Image)
+	if manifest.Variant != "" {
+		runCommand += " --variant " + manifest.Variant
+	}
+	if configOutput != "" {
+		runCommand += " --config " + configOutput
+	}
+	fmt.Printf("   Run it with: %s\n", runCommand)
+}
@@ -5,31 +5,68 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
 	"github.com/nawaman/codingbooth/src/pkg/booth"
 	boothinit "github.com/nawaman/codingbooth/src/pkg/booth/init"
 )
 
 func runBooth(version string) {
-	context := boothinit.InitializeAppContext(version, boothinit.DefaultInitializeAppContextBoundary{})
+	context, err := initializeRun(version)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+		return
+	}
 
 	if context.Verbose() {
 		fmt.Printf("%+v\n", context)
 	}
 
 	runner := booth.NewBoothRunner(context)
-	err := runner.Run()
+	err = runner.Run()
 	if err != nil {
 		// For SilentExitError (from command mode), exit with the code silently
-		if silentErr, ok := err.(*booth.SilentExitError); ok {
+		var silentErr *booth.SilentExitError
+		if errors.As(err, &silentErr) {
 			os.Exit(silentErr.ExitCode)
 			return
 		}
-		fmt.Println("❌ CodingBooth failed with error:", err)
+		reportRunError(err)
 		os.Exit(1)
 		return
 	}
 	os.Exit(0)
 }
+
+// initializeRun initializes the app context; with --explain, it also prints where each config value came from.
+func initializeRun(version string) (appctx.AppContext, error) {
+	boundary := boothinit.DefaultInitializeAppContextBoundary{}
+	if !boothinit.HasExplainFlag(os.Args[1:]) {
+		return boothinit.InitializeAppContext(version, boundary)
+	}
+
+	context, explanation, err := boothinit.ExplainAppContext(version, boundary)
+	fmt.Print(explanation)
+	return context, err
+}
+
+// reportRunError prints the error(s) of BoothRunner.Run; stage errors are shown with their CLI message.
+func reportRunError(err error) {
+	stageErrors := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		stageErrors = joined.Unwrap()
+	}
+
+	for _, stageErr := range stageErrors {
+		var messageErr booth.CliMessageError
+		if errors.As(stageErr, &messageErr) {
+			fmt.Fprintln(os.Stderr, messageErr.CliMessage())
+		} else {
+			fmt.Println("❌ CodingBooth failed with error:", stageErr)
+		}
+	}
+}
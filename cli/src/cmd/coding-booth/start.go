@@ -0,0 +1,41 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runStart(args []string) {
+	target := targetArgs{}
+	attach := true
+
+	for index := 0; index < len(args); {
+		if consumed := target.parseCommon("start", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		switch args[index] {
+		case "--daemon", "-d":
+			attach = false
+		default:
+			exitUnknownOption("start", args[index])
+		}
+		index++
+	}
+
+	for _, found := range target.resolve("start", booth.ListStopped) {
+		if !attach {
+			fmt.Printf("📦 Starting booth '%s' in the background.\n", found.Info.Name)
+		}
+		if err := booth.StartBooth(target.client(), target.flags, found, attach); err != nil {
+			fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nawaman/codingbooth/src/pkg/booth"
+)
+
+func runStop(args []string) {
+	target := targetArgs{}
+	timeout := ""
+	force := false
+
+	for index := 0; index < len(args); {
+		if consumed := target.parseCommon("stop", args, index); consumed > 0 {
+			index += consumed
+			continue
+		}
+		switch args[index] {
+		case "--force", "-f":
+			force = true
+		case "--time", "-t":
+			timeout = needArgValue("stop", args, index)
+			index++
+		default:
+			exitUnknownOption("stop", args[index])
+		}
+		index++
+	}
+
+	for _, found := range target.resolve("stop", booth.ListRunning) {
+		if err := booth.StopBooth(target.client(), target.flags, found, timeout, force); err != nil {
+			fmt.Fprintln(os.Stderr, "❌ CodingBooth failed with error:", err)
+			os.Exit(1)
+		}
+		if found.KeepAlive {
+			fmt.Printf("🛑 Stopped booth '%s' (kept; resume with '%s start %s').\n", found.Info.Name, scriptName(), found.Info.Name)
+		} else {
+			fmt.Printf("🛑 Stopped and removed booth '%s'.\n", found.Info.Name)
+		}
+	}
+}
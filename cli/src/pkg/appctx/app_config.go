@@ -6,6 +6,8 @@ package appctx
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -33,6 +35,18 @@ type AppConfig struct {
 	Daemon       bool `toml:"daemon,omitempty"        envconfig:"CB_DAEMON" default:"false"`
 	Pull         bool `toml:"pull,omitempty"          envconfig:"CB_PULL" default:"false"`
 	Dind         bool `toml:"dind,omitempty"          envconfig:"CB_DIND" default:"false"`
+	Open         bool `toml:"open,omitempty"          envconfig:"CB_OPEN" default:"false"`
+	Wait         bool `toml:"wait,omitempty"          envconfig:"CB_WAIT" default:"false"`
+
+	// --------------------
+	// Docker engine
+	// --------------------
+	Engine string `toml:"engine,omitempty" envconfig:"CB_ENGINE"`
+
+	// --------------------
+	// Config profile (the `[profile.<name>]` tables of the config files to apply)
+	// --------------------
+	Profile string `toml:"-" envconfig:"CB_PROFILE"`
 
 	// --------------------
 	// Image configuration
@@ -52,10 +66,12 @@ type AppConfig struct {
 	// --------------------
 	// Container configuration
 	// --------------------
-	Name    string `toml:"name,omitempty"      envconfig:"CB_NAME"`
-	Port    string `toml:"port,omitempty"      envconfig:"CB_PORT" default:"NEXT"`
-	EnvFile string `toml:"env-file,omitempty"  envconfig:"CB_ENV_FILE"`
-	Startup string `toml:"startup,omitempty"   envconfig:"CB_STARTUP"`
+	Name        string `toml:"name,omitempty"         envconfig:"CB_NAME"`
+	Port        string `toml:"port,omitempty"         envconfig:"CB_PORT" default:"NEXT"`
+	BindAddress string `toml:"bind-address,omitempty" envconfig:"CB_BIND_ADDRESS" default:"127.0.0.1"`
+	DindCache   string `toml:"dind-cache,omitempty"   envconfig:"CB_DIND_CACHE" default:"project"`
+	EnvFile     string `toml:"env-file,omitempty"     envconfig:"CB_ENV_FILE"`
+	Startup     string `toml:"startup,omitempty"      envconfig:"CB_STARTUP"`
 
 	// --------------------
 	// TOML-friendly array fields
@@ -64,6 +80,21 @@ type AppConfig struct {
 	BuildArgs  ilist.SemicolonStringList `toml:"build-args,omitempty"  envconfig:"CB_BUILD_ARGS"`
 	RunArgs    ilist.SemicolonStringList `toml:"run-args,omitempty"    envconfig:"CB_RUN_ARGS"`
 	Cmds       ilist.SemicolonStringList `toml:"cmds,omitempty"        envconfig:"CB_CMDS"`
+
+	// Setup scripts of the image ("<name>" or "<name>@<version>") built into a local image when there is no Dockerfile
+	Setups ilist.SemicolonStringList `toml:"setups,omitempty" ignored:"true"`
+
+	// --------------------
+	// Structured tables: [[mounts]], [ports] (name = port mapping) and [env] (name = value)
+	// --------------------
+	Mounts []Mount                `toml:"mounts,omitempty" ignored:"true"`
+	Ports  map[string]PortMapping `toml:"ports,omitempty"  ignored:"true"`
+	Env    map[string]string      `toml:"env,omitempty"    ignored:"true"`
+
+	// --------------------
+	// Host-side stage hooks (`before-<stage>`/`after-<stage>` = "<shell command>")
+	// --------------------
+	Hooks map[string]string `toml:"hooks,omitempty" ignored:"true"`
 }
 
 // Clone the content of the app config.
@@ -74,6 +105,11 @@ func (config *AppConfig) Clone() *AppConfig {
 	copy.BuildArgs = config.BuildArgs.Clone()
 	copy.RunArgs = config.RunArgs.Clone()
 	copy.Cmds = config.Cmds.Clone()
+	copy.Setups = config.Setups.Clone()
+	copy.Mounts = slices.Clone(config.Mounts)
+	copy.Ports = maps.Clone(config.Ports)
+	copy.Env = maps.Clone(config.Env)
+	copy.Hooks = maps.Clone(config.Hooks)
 
 	return &copy
 }
@@ -84,11 +120,150 @@ func ReadFromEnvVars(config *AppConfig) error {
 }
 
 // ReadFromToml reads configuration from a TOML file and populates the config (overriding existing values).
+// The deprecated keys are read as their replacement (see DeprecatedKeys) and the values are interpolated (see interpolateToml).
 func ReadFromToml(path string, config *AppConfig) error {
-	if _, err := toml.DecodeFile(path, config); err != nil {
+	content, _, err := readConfigFile(path)
+	if err != nil {
 		return err
 	}
-	return nil
+	content, problems := interpolateToml(content, "")
+	if len(problems) > 0 {
+		for i := range problems {
+			problems[i].File = path
+		}
+		return &ConfigValidationError{Problems: problems}
+	}
+	_, err = toml.Decode(content, config)
+	return err
+}
+
+// AppendedListKeys are the list keys that a config layer appends to the lower layers (see MergeFromToml).
+var AppendedListKeys = []string{"common-args", "build-args", "run-args", "setups", "mounts"}
+
+// configLayerFile is the part of a config file that is not an AppConfig key.
+type configLayerFile struct {
+	// Replace lists the lists that replace the ones of the lower layers instead of appending to them.
+	Replace []string `toml:"replace"`
+	// Profiles are the `[profile.<name>]` tables.
+	Profiles map[string]toml.Primitive `toml:"profile"`
+}
+
+// MergeFromToml reads a config layer over the config (like ReadFromToml, later layers win),
+// then its `[profile.<profile>]` table (if any) over that.
+// The lists in appendTo (common-args, build-args, run-args or mounts set by a lower layer) are appended to instead of replaced,
+// unless the layer (or the profile) names them in `replace = [...]`; ports, env and hooks merge by key.
+// The string values are interpolated first (see interpolateToml); the missing required variables are returned as a ConfigValidationError.
+// It returns the keys defined by the layer (including its profile).
+func MergeFromToml(path string, config *AppConfig, profile string, appendTo map[string]bool) (map[string]bool, error) {
+	lower := config.Clone()
+
+	content, _, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	interpolated, problems := interpolateToml(content, profile)
+	if len(problems) > 0 {
+		lines := tomlKeyLinesOf(content)
+		for i := range problems {
+			problems[i].File, problems[i].Line = path, lineOfKey(lines, problems[i].Key)
+		}
+		return nil, &ConfigValidationError{Problems: problems}
+	}
+	content = interpolated
+
+	var layer configLayerFile
+	meta, err := toml.Decode(content, &layer)
+	if err != nil {
+		return nil, err
+	}
+	// The mounts are decoded into a new slice (the decoder would merge the entries into the lower ones)
+	config.Mounts = nil
+	if _, err := toml.Decode(content, config); err != nil {
+		return nil, err
+	}
+
+	defined := map[string]bool{}
+	for _, key := range meta.Keys() {
+		if key[0] != "profile" {
+			defined[key[0]] = true
+		}
+	}
+	if !defined["mounts"] {
+		config.Mounts = lower.Mounts
+	}
+	appendLists(config, lower, defined, appendTo, layer.Replace)
+
+	primitive, found := layer.Profiles[profile]
+	if profile == "" || !found {
+		return defined, nil
+	}
+
+	lower = config.Clone()
+	var profileLayer configLayerFile
+	if err := meta.PrimitiveDecode(primitive, &profileLayer); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+	config.Mounts = nil
+	if err := meta.PrimitiveDecode(primitive, config); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+
+	profileKeys := map[string]bool{}
+	for _, key := range meta.Keys() {
+		if len(key) == 3 && key[0] == "profile" && key[1] == profile {
+			profileKeys[key[2]] = true
+		}
+	}
+	if !profileKeys["mounts"] {
+		config.Mounts = lower.Mounts
+	}
+	below := maps.Clone(appendTo)
+	maps.Copy(below, defined)
+	appendLists(config, lower, profileKeys, below, profileLayer.Replace)
+
+	maps.Copy(defined, profileKeys)
+	return defined, nil
+}
+
+// appendLists prepends the lower lists to the lists defined by a layer, when the lower layers set them too.
+func appendLists(config *AppConfig, lower *AppConfig, defined map[string]bool, appendTo map[string]bool, replace []string) {
+	for _, key := range AppendedListKeys {
+		if !defined[key] || !appendTo[key] || slices.Contains(replace, key) {
+			continue
+		}
+		if key == "mounts" {
+			config.Mounts = append(slices.Clone(lower.Mounts), config.Mounts...)
+			continue
+		}
+		list, lowerList := config.listOf(key), lower.listOf(key)
+		*list = ilist.SemicolonStringList{List: ilist.NewList(append(lowerList.Slice(), list.Slice()...)...)}
+	}
+}
+
+// ProfilesOf returns the names of the `[profile.<name>]` tables of a config file, sorted.
+func ProfilesOf(path string) ([]string, error) {
+	content, _, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var layer configLayerFile
+	if _, err := toml.Decode(content, &layer); err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(layer.Profiles)), nil
+}
+
+// listOf returns the list field of an AppendedListKeys key.
+func (config *AppConfig) listOf(key string) *ilist.SemicolonStringList {
+	switch key {
+	case "common-args":
+		return &config.CommonArgs
+	case "build-args":
+		return &config.BuildArgs
+	case "setups":
+		return &config.Setups
+	}
+	return &config.RunArgs
 }
 
 // String returns a string representation of the app config.
@@ -110,6 +285,10 @@ func (config AppConfig) String() string {
 	fmt.Fprintf(&str, "    Daemon:           %t\n", config.Daemon)
 	fmt.Fprintf(&str, "    Pull:             %t\n", config.Pull)
 	fmt.Fprintf(&str, "    Dind:             %t\n", config.Dind)
+	fmt.Fprintf(&str, "    Open:             %t\n", config.Open)
+	fmt.Fprintf(&str, "    Wait:             %t\n", config.Wait)
+	fmt.Fprintf(&str, "    Engine:           %q\n", config.Engine)
+	fmt.Fprintf(&str, "    Profile:          %q\n", config.Profile)
 
 	fmt.Fprintf(&str, "# Image Configuration -----------\n")
 	fmt.Fprintf(&str, "    Dockerfile:       %q\n", config.Dockerfile)
@@ -125,6 +304,8 @@ func (config AppConfig) String() string {
 	fmt.Fprintf(&str, "# Container Configuration -------\n")
 	fmt.Fprintf(&str, "    Name:             %q\n", config.Name)
 	fmt.Fprintf(&str, "    Port:             %q\n", config.Port)
+	fmt.Fprintf(&str, "    BindAddress:      %q\n", config.BindAddress)
+	fmt.Fprintf(&str, "    DindCache:        %q\n", config.DindCache)
 	fmt.Fprintf(&str, "    EnvFile:          %q\n", config.EnvFile)
 	fmt.Fprintf(&str, "    Startup:          %q\n", config.Startup)
 
@@ -133,6 +314,15 @@ func (config AppConfig) String() string {
 	formatList(&str, "BuildArgs", config.BuildArgs.List, "    ")
 	formatList(&str, "RunArgs", config.RunArgs.List, "    ")
 	formatList(&str, "Cmds", config.Cmds.List, "    ")
+	formatList(&str, "Setups", config.Setups.List, "    ")
+
+	fmt.Fprintf(&str, "# Structured tables -------------\n")
+	fmt.Fprintf(&str, "    Mounts:           %v\n", config.Mounts)
+	fmt.Fprintf(&str, "    Ports:            %v\n", config.Ports)
+	fmt.Fprintf(&str, "    Env:              %v\n", config.Env)
+
+	fmt.Fprintf(&str, "# Hooks -------------------------\n")
+	fmt.Fprintf(&str, "    Hooks:            %v\n", config.Hooks)
 
 	str.WriteString("==================================================================\n")
 
@@ -0,0 +1,101 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// KnownSetups are the setup scripts shipped in the images (variants/base/setups/<name>--setup.sh).
+var KnownSetups = []string{
+	"antigravity", "base-code-extension", "bash-code-extension", "bash-nb-kernel", "brew", "bun",
+	"chromium-browser", "claude-code", "cleanup-after", "codeserver", "deno", "dind", "docker-buildx",
+	"docker-compose", "eclipse", "firefox", "gh", "gh-copilot", "go", "go-code-extension", "google-chrome",
+	"gradle", "idea", "java-code-extension", "java-ijava-nb-kernel", "java-jjava-nb-kernel", "java-nb-kernel",
+	"jdk", "jenv", "jetbrains", "jetbrains-plugin", "jupyter-code-extension", "kde", "lombok-eclipse", "lxqt",
+	"mvn", "neovim", "network-whitelist", "nodejs", "notebook", "pycharm", "python", "python-code-extension",
+	"python-nb-kernel", "react-code-extension", "variant", "vscode", "warp", "xfce",
+}
+
+// SetupSpec is a `setups` entry: "<name>" or "<name>@<version>" (e.g. "go@1.23" runs go--setup.sh 1.23).
+type SetupSpec struct {
+	Name    string
+	Version string
+}
+
+var setupVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// ParseSetup parses a `setups` entry.
+func ParseSetup(entry string) SetupSpec {
+	name, version, _ := strings.Cut(strings.TrimSpace(entry), "@")
+	return SetupSpec{Name: strings.TrimSpace(name), Version: strings.TrimSpace(version)}
+}
+
+// Script returns the file name of the setup script (e.g. "go--setup.sh").
+func (spec SetupSpec) Script() string {
+	return spec.Name + "--setup.sh"
+}
+
+func (spec SetupSpec) String() string {
+	if spec.Version == "" {
+		return spec.Name
+	}
+	return spec.Name + "@" + spec.Version
+}
+
+// problem returns why the entry is invalid ("" if valid).
+func (spec SetupSpec) problem() string {
+	if !slices.Contains(KnownSetups, spec.Name) {
+		if suggestion := closest(spec.Name, KnownSetups); suggestion != "" {
+			return fmt.Sprintf("unknown setup '%s' (did you mean '%s'?)", spec.Name, suggestion)
+		}
+		return fmt.Sprintf("unknown setup '%s' (see variants/base/setups for the setups of the image)", spec.Name)
+	}
+	if strings.Contains(spec.String(), "@") && !setupVersionPattern.MatchString(spec.Version) {
+		return fmt.Sprintf("'%s' is not a version (use <name>@<version>, e.g. go@1.23)", spec.Version)
+	}
+	return ""
+}
+
+// SetupSpecs parses the `setups` entries in their order.
+// A setup listed again (e.g. by a later config layer) keeps its first position and takes the later version,
+// so the layers of the setups before it stay cached.
+func SetupSpecs(entries []string) []SetupSpec {
+	specs := []SetupSpec{}
+	positions := map[string]int{}
+	for _, entry := range entries {
+		spec := ParseSetup(entry)
+		if spec.Name == "" {
+			continue
+		}
+		if position, found := positions[spec.Name]; found {
+			specs[position] = spec
+			continue
+		}
+		positions[spec.Name] = len(specs)
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// setupsProblems returns the problems of the `setups` entries of one config file (unknown names, bad versions, duplicates).
+func setupsProblems(entries []string) []string {
+	problems := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		spec := ParseSetup(entry)
+		if problem := spec.problem(); problem != "" {
+			problems = append(problems, problem)
+		}
+		if seen[spec.Name] {
+			problems = append(problems, fmt.Sprintf("setup '%s' is listed more than once", spec.Name))
+		}
+		seen[spec.Name] = true
+	}
+	return problems
+}
@@ -0,0 +1,66 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// setupsDir is the folder of the setup scripts of the images (relative to this package).
+var setupsDir = filepath.Join("..", "..", "..", "..", "variants", "base", "setups")
+
+func TestKnownSetups_MatchTheImage(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join(setupsDir, "*--setup.sh"))
+	if err != nil || len(scripts) == 0 {
+		t.Fatalf("cannot list the setup scripts in %s: %v", setupsDir, err)
+	}
+	shipped := []string{}
+	for _, script := range scripts {
+		shipped = append(shipped, strings.TrimSuffix(filepath.Base(script), "--setup.sh"))
+	}
+	known := slices.Sorted(slices.Values(KnownSetups))
+	if !reflect.DeepEqual(known, shipped) {
+		t.Errorf("KnownSetups is out of date with %s:\n%q\nwant\n%q", setupsDir, known, shipped)
+	}
+}
+
+func TestSetupSpecs(t *testing.T) {
+	specs := SetupSpecs([]string{"go@1.22", " nodejs @ 22 ", "gh", "go@1.23", ""})
+	want := []SetupSpec{{Name: "go", Version: "1.23"}, {Name: "nodejs", Version: "22"}, {Name: "gh"}}
+	if !reflect.DeepEqual(specs, want) {
+		t.Errorf("SetupSpecs() = %v, want %v", specs, want)
+	}
+	if got := specs[0].Script(); got != "go--setup.sh" {
+		t.Errorf("Script() = %q", got)
+	}
+}
+
+func TestValidateToml_Setups(t *testing.T) {
+	path := writeToml(t, `variant = "base"
+setups = [
+    "go@1.23",
+    "nodej@22",
+    "python@3.12; rm -rf /",
+    "gh",
+    "gh",
+]
+`)
+	var got []string
+	for _, problem := range ValidateToml(path) {
+		got = append(got, problem.String()[len(path):])
+	}
+	want := []string{
+		":2: setups: unknown setup 'nodej' (did you mean 'nodejs'?)",
+		":2: setups: '3.12; rm -rf /' is not a version (use <name>@<version>, e.g. go@1.23)",
+		":2: setups: setup 'gh' is listed more than once",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateToml() =\n%q\nwant\n%q", got, want)
+	}
+}
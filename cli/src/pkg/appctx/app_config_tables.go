@@ -0,0 +1,173 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HomeSeedDir is the folder whose content booth-entry copies into the coder home at startup (without overwriting).
+const HomeSeedDir = "/etc/cb-home-seed"
+
+// CoderHome is the home of the coder user in the booth.
+const CoderHome = "/home/coder"
+
+// Mount is a `[[mounts]]` entry of the config: a host path mounted in the booth.
+type Mount struct {
+	// Source is the host path (~ and $VAR are expanded; relative to the code folder) or a docker volume name.
+	Source string `toml:"source"`
+	// Target is the path in the booth; with Seed, it is relative to the home (default: Source relative to the host home).
+	Target string `toml:"target,omitempty"`
+	// Mode is "rw" (the default) or "ro".
+	Mode string `toml:"mode,omitempty"`
+	// Seed mounts the source read-only in /etc/cb-home-seed so it is copied into the home at startup.
+	Seed bool `toml:"seed,omitempty"`
+	// Optional skips the mount (with a warning) when the source does not exist.
+	Optional bool `toml:"optional,omitempty"`
+}
+
+// MountTarget returns the path the mount is bound to in the booth and its mode.
+// Seed mounts go to /etc/cb-home-seed (read-only); hostHome is used to derive the target of a seed mount without one.
+func (mount Mount) MountTarget(source string, hostHome string) (string, string) {
+	if !mount.Seed {
+		mode := mount.Mode
+		if mode == "" {
+			mode = "rw"
+		}
+		return mount.Target, mode
+	}
+
+	target := mount.Target
+	if target == "" && hostHome != "" {
+		if relative, found := strings.CutPrefix(source, strings.TrimSuffix(hostHome, "/")+"/"); found {
+			target = relative
+		}
+	}
+	if relative, found := strings.CutPrefix(target, CoderHome+"/"); found {
+		target = relative
+	}
+	return path.Join(HomeSeedDir, strings.TrimPrefix(target, "~/")), "ro"
+}
+
+// problems returns what is wrong with the mount (empty if valid).
+func (mount Mount) problems() []string {
+	problems := []string{}
+	if mount.Source == "" {
+		problems = append(problems, "source is required")
+	}
+	if mount.Target == "" && !mount.Seed {
+		problems = append(problems, "target is required (unless seed = true)")
+	}
+	if mount.Target != "" && !mount.Seed && !strings.HasPrefix(mount.Target, "/") {
+		problems = append(problems, fmt.Sprintf("target '%s' must be an absolute path (or set seed = true for a path in the home)", mount.Target))
+	}
+	if mount.Mode != "" && mount.Mode != "ro" && mount.Mode != "rw" {
+		problems = append(problems, fmt.Sprintf("mode '%s' must be 'ro' or 'rw'", mount.Mode))
+	}
+	if mount.Seed && mount.Mode == "rw" {
+		problems = append(problems, "seed mounts are read-only (mode must be 'ro' or unset)")
+	}
+	return problems
+}
+
+// PortMapping is a `[ports]` value: "<host>:<container>", "<ip>:<host>:<container>" or a container port
+// published on the same host port (a number or "<port>"), with an optional "/tcp", "/udp" or "/sctp".
+// The host port can also be NEXT (the first free port from the container port up) or RANDOM (a random free port);
+// it is allocated when the booth starts (see booth.PortDetermination).
+type PortMapping string
+
+// UnmarshalTOML implements the toml.Unmarshaler interface (a port is a string or an integer).
+func (port *PortMapping) UnmarshalTOML(data interface{}) error {
+	switch value := data.(type) {
+	case string:
+		*port = PortMapping(value)
+	case int64:
+		*port = PortMapping(strconv.FormatInt(value, 10))
+	default:
+		return fmt.Errorf("expected a port (e.g. 3000 or \"3000:3000\"), got a %T (%v)", data, data)
+	}
+	return nil
+}
+
+var portMappingPattern = regexp.MustCompile(`^(?:(\d{1,3}(?:\.\d{1,3}){3}):)?(?:(\d+|NEXT|RANDOM|next|random):)?(\d+)(/(?:tcp|udp|sctp))?$`)
+
+// HostPort returns the host part of the mapping: a port number, "NEXT" or "RANDOM"
+// ("" for a single port, published on the same host port, or an invalid mapping).
+func (port PortMapping) HostPort() string {
+	match := portMappingPattern.FindStringSubmatch(string(port))
+	if match == nil {
+		return ""
+	}
+	return strings.ToUpper(match[2])
+}
+
+// ContainerPort returns the container port of the mapping (0 for an invalid mapping).
+func (port PortMapping) ContainerPort() int {
+	match := portMappingPattern.FindStringSubmatch(string(port))
+	if match == nil {
+		return 0
+	}
+	number, _ := strconv.Atoi(match[3])
+	return number
+}
+
+// Protocol returns the protocol of the mapping ("tcp" when not given).
+func (port PortMapping) Protocol() string {
+	match := portMappingPattern.FindStringSubmatch(string(port))
+	if match == nil || match[4] == "" {
+		return "tcp"
+	}
+	return match[4][1:]
+}
+
+// WithHostPort returns the mapping published on the given host port (the mapping as is for 0 or an invalid mapping).
+func (port PortMapping) WithHostPort(hostPort int) PortMapping {
+	match := portMappingPattern.FindStringSubmatch(string(port))
+	if match == nil || hostPort == 0 {
+		return port
+	}
+	ip := ""
+	if match[1] != "" {
+		ip = match[1] + ":"
+	}
+	return PortMapping(fmt.Sprintf("%s%d:%s%s", ip, hostPort, match[3], match[4]))
+}
+
+// Publish returns the value of the docker -p flag for the mapping (e.g. "3000:3000" for 3000).
+// A NEXT or RANDOM host port must be allocated first (see WithHostPort).
+func (port PortMapping) Publish() string {
+	match := portMappingPattern.FindStringSubmatch(string(port))
+	if match == nil || match[2] != "" {
+		return string(port)
+	}
+	// A single port is published on the same host port
+	number, _ := strconv.Atoi(match[3])
+	return string(port.WithHostPort(number))
+}
+
+// problem returns why the mapping is invalid ("" if valid).
+func (port PortMapping) problem() string {
+	match := portMappingPattern.FindStringSubmatch(string(port))
+	if match == nil {
+		return fmt.Sprintf("'%s' is not a port mapping (use <container>, <host>:<container> or <ip>:<host>:<container>; the host port can be NEXT or RANDOM)", port)
+	}
+	for _, number := range match[2:4] {
+		value, err := strconv.Atoi(number)
+		if err != nil {
+			// No host port, NEXT or RANDOM
+			continue
+		}
+		if value < 1 || value > 65535 {
+			return fmt.Sprintf("%s is out of the port range 1-65535", number)
+		}
+	}
+	return ""
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
@@ -0,0 +1,114 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPortMapping_Publish(t *testing.T) {
+	tests := map[PortMapping]string{
+		"3000":                "3000:3000",
+		"3000/udp":            "3000:3000/udp",
+		"8080:80":             "8080:80",
+		"127.0.0.1:5432":      "127.0.0.1:5432:5432",
+		"127.0.0.1:8080:80":   "127.0.0.1:8080:80",
+		"0.0.0.0:9000:90/tcp": "0.0.0.0:9000:90/tcp",
+	}
+	for port, want := range tests {
+		if got := port.Publish(); got != want {
+			t.Errorf("PortMapping(%q).Publish() = %q, want %q", port, got, want)
+		}
+	}
+}
+
+func TestPortMapping_WithHostPort(t *testing.T) {
+	tests := []struct {
+		port      PortMapping
+		host      string
+		container int
+		published string
+	}{
+		{"3000", "", 3000, "3001:3000"},
+		{"NEXT:3000", "NEXT", 3000, "3001:3000"},
+		{"127.0.0.1:random:5005/udp", "RANDOM", 5005, "127.0.0.1:3001:5005/udp"},
+		{"8080:80", "8080", 80, "3001:80"},
+	}
+	for _, tt := range tests {
+		if host, container := tt.port.HostPort(), tt.port.ContainerPort(); host != tt.host || container != tt.container {
+			t.Errorf("PortMapping(%q) host, container = %q, %d, want %q, %d", tt.port, host, container, tt.host, tt.container)
+		}
+		if got := tt.port.WithHostPort(3001).Publish(); got != tt.published {
+			t.Errorf("PortMapping(%q).WithHostPort(3001).Publish() = %q, want %q", tt.port, got, tt.published)
+		}
+	}
+}
+
+func TestMount_MountTarget(t *testing.T) {
+	tests := []struct {
+		mount  Mount
+		source string
+		target string
+		mode   string
+	}{
+		{Mount{Target: "/data"}, "/host/data", "/data", "rw"},
+		{Mount{Target: "/data", Mode: "ro"}, "/host/data", "/data", "ro"},
+		{Mount{Seed: true}, "/home/me/.claude", "/etc/cb-home-seed/.claude", "ro"},
+		{Mount{Seed: true, Target: "~/.config/gh"}, "/tmp/gh", "/etc/cb-home-seed/.config/gh", "ro"},
+		{Mount{Seed: true, Target: "/home/coder/.aws"}, "/tmp/aws", "/etc/cb-home-seed/.aws", "ro"},
+	}
+	for _, tt := range tests {
+		target, mode := tt.mount.MountTarget(tt.source, "/home/me")
+		if target != tt.target || mode != tt.mode {
+			t.Errorf("%+v.MountTarget(%q) = %q, %q, want %q, %q", tt.mount, tt.source, target, mode, tt.target, tt.mode)
+		}
+	}
+}
+
+func TestValidateToml_Tables(t *testing.T) {
+	path := writeToml(t, `[[mounts]]
+source = "~/.claude"
+seed = true
+optional = true
+
+[[mounts]]
+source = "~/data"
+target = "data"
+mode = "rx"
+
+[[mounts]]
+sourse = "~/cache"
+target = "/cache"
+
+[ports]
+web = 3000
+db = "127.0.0.1:5432:5432"
+api = "70000:80"
+debug = "NEXT:5005"
+proxy = "SOON:8080"
+
+[env]
+EDITOR = "vim"
+"BAD-NAME" = "x"
+`)
+
+	var got []string
+	for _, problem := range ValidateToml(path) {
+		got = append(got, problem.String()[len(path):])
+	}
+	want := []string{
+		":6: mounts.1: target 'data' must be an absolute path (or set seed = true for a path in the home)",
+		":6: mounts.1: mode 'rx' must be 'ro' or 'rw'",
+		":11: mounts.2: source is required",
+		":12: mounts.2.sourse: unknown mount key (keys: source, target, mode, seed, optional)",
+		":18: ports.api: 70000 is out of the port range 1-65535",
+		":20: ports.proxy: 'SOON:8080' is not a port mapping (use <container>, <host>:<container> or <ip>:<host>:<container>; the host port can be NEXT or RANDOM)",
+		":24: env.BAD-NAME: 'BAD-NAME' is not an environment variable name",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateToml() =\n%q\nwant\n%q", got, want)
+	}
+}
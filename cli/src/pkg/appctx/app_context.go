@@ -10,8 +10,11 @@ package appctx
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/nawaman/codingbooth/src/pkg/docker"
 	"github.com/nawaman/codingbooth/src/pkg/ilist"
 )
 
@@ -66,6 +69,11 @@ func (ctx AppContext) ScriptName() string { return ctx.values.ScriptName }
 func (ctx AppContext) ScriptDir() string  { return ctx.values.ScriptDir }
 func (ctx AppContext) LibDir() string     { return ctx.values.LibDir }
 
+// derived from variant
+func (ctx AppContext) HasNotebook() bool { return ctx.values.HasNotebook }
+func (ctx AppContext) HasVscode() bool   { return ctx.values.HasVscode }
+func (ctx AppContext) HasDesktop() bool  { return ctx.values.HasDesktop }
+
 // derived from DinD
 func (ctx AppContext) CreatedDindNet() bool { return ctx.values.CreatedDindNet }
 
@@ -78,17 +86,35 @@ func (ctx AppContext) ImageMode() string { return ctx.values.ImageMode }
 func (ctx AppContext) PortGenerated() bool { return ctx.values.PortGenerated }
 func (ctx AppContext) PortNumber() int     { return ctx.values.PortNumber }
 
+// NamedPorts returns the host ports allocated to the [ports] of the config by name.
+func (ctx AppContext) NamedPorts() map[string]int { return maps.Clone(ctx.values.NamedPorts) }
+
+// ReservedPorts returns the generated host ports reserved (against concurrent launches) until the booth runs.
+func (ctx AppContext) ReservedPorts() []int { return slices.Clone(ctx.values.ReservedPorts) }
+
+// Docker returns the docker client of the run pipeline (the docker CLI by default).
+func (ctx AppContext) Docker() docker.DockerClient {
+	if ctx.values.Docker == nil {
+		return docker.CliDockerClient{}
+	}
+	return ctx.values.Docker
+}
+
 // Flags
 func (ctx AppContext) KeepAlive() bool    { return ctx.values.Config.KeepAlive }
 func (ctx AppContext) SilenceBuild() bool { return ctx.values.Config.SilenceBuild }
 func (ctx AppContext) Daemon() bool       { return ctx.values.Config.Daemon }
 func (ctx AppContext) Pull() bool         { return ctx.values.Config.Pull }
 func (ctx AppContext) Dind() bool         { return ctx.values.Config.Dind }
+func (ctx AppContext) Open() bool         { return ctx.values.Config.Open }
+func (ctx AppContext) Wait() bool         { return ctx.values.Config.Wait }
 
 // Image Configuration
 func (ctx AppContext) Dockerfile() string { return ctx.values.Config.Dockerfile }
 func (ctx AppContext) Image() string      { return ctx.values.Config.Image }
 func (ctx AppContext) Variant() string    { return ctx.values.Config.Variant }
+func (ctx AppContext) Engine() string     { return ctx.values.Config.Engine }
+func (ctx AppContext) Profile() string    { return ctx.values.Config.Profile }
 
 // Runtime values
 func (ctx AppContext) ProjectName() string { return ctx.values.Config.ProjectName }
@@ -97,10 +123,12 @@ func (ctx AppContext) HostGID() string     { return ctx.values.Config.HostGID }
 func (ctx AppContext) Timezone() string    { return ctx.values.Config.Timezone }
 
 // Container Configuration
-func (ctx AppContext) Name() string    { return ctx.values.Config.Name }
-func (ctx AppContext) Port() string    { return ctx.values.Config.Port }
-func (ctx AppContext) EnvFile() string { return ctx.values.Config.EnvFile }
-func (ctx AppContext) Startup() string { return ctx.values.Config.Startup }
+func (ctx AppContext) Name() string        { return ctx.values.Config.Name }
+func (ctx AppContext) Port() string        { return ctx.values.Config.Port }
+func (ctx AppContext) BindAddress() string { return ctx.values.Config.BindAddress }
+func (ctx AppContext) DindCache() string   { return ctx.values.Config.DindCache }
+func (ctx AppContext) EnvFile() string     { return ctx.values.Config.EnvFile }
+func (ctx AppContext) Startup() string     { return ctx.values.Config.Startup }
 
 // derived from all the context processing (IMMUTABLE SNAPSHOTS)
 func (ctx AppContext) CommonArgs() ilist.List[ilist.List[string]] { return ctx.commonArgs }
@@ -108,6 +136,17 @@ func (ctx AppContext) BuildArgs() ilist.List[ilist.List[string]]  { return ctx.b
 func (ctx AppContext) RunArgs() ilist.List[ilist.List[string]]    { return ctx.runArgs }
 func (ctx AppContext) Cmds() ilist.List[ilist.List[string]]       { return ctx.cmds }
 
+// Setups of the image to build (see SetupSpecs)
+func (ctx AppContext) Setups() []SetupSpec { return SetupSpecs(ctx.values.Config.Setups.Slice()) }
+
+// Structured tables (copies)
+func (ctx AppContext) Mounts() []Mount               { return slices.Clone(ctx.values.Config.Mounts) }
+func (ctx AppContext) Ports() map[string]PortMapping { return maps.Clone(ctx.values.Config.Ports) }
+func (ctx AppContext) Env() map[string]string        { return maps.Clone(ctx.values.Config.Env) }
+
+// Host-side stage hooks (a copy)
+func (ctx AppContext) Hooks() map[string]string { return maps.Clone(ctx.values.Config.Hooks) }
+
 // ToBuilder converts an immutable AppContext back into a mutable builder.
 func (ctx AppContext) ToBuilder() *AppContextBuilder {
 	b := ctx.values.Clone()
@@ -136,6 +175,11 @@ func (ctx AppContext) String() string {
 	fmt.Fprintf(&str, "    ScriptDir:        %q\n", ctx.ScriptDir())
 	fmt.Fprintf(&str, "    LibDir:           %q\n", ctx.LibDir())
 
+	fmt.Fprintf(&str, "# Variant -----------------------\n")
+	fmt.Fprintf(&str, "    HasNotebook:      %t\n", ctx.HasNotebook())
+	fmt.Fprintf(&str, "    HasVscode:        %t\n", ctx.HasVscode())
+	fmt.Fprintf(&str, "    HasDesktop:       %t\n", ctx.HasDesktop())
+
 	fmt.Fprintf(&str, "# DinD --------------------------\n")
 	fmt.Fprintf(&str, "    CreatedDindNet:   %t\n", ctx.CreatedDindNet())
 
@@ -147,6 +191,8 @@ func (ctx AppContext) String() string {
 	fmt.Fprintf(&str, "# Port --------------------------\n")
 	fmt.Fprintf(&str, "    PortGenerated:    %t\n", ctx.PortGenerated())
 	fmt.Fprintf(&str, "    PortNumber:       %d\n", ctx.PortNumber())
+	fmt.Fprintf(&str, "    NamedPorts:       %v\n", ctx.NamedPorts())
+	fmt.Fprintf(&str, "    ReservedPorts:    %v\n", ctx.ReservedPorts())
 
 	fmt.Fprintf(&str, "# General configuration ---------\n")
 	fmt.Fprintf(&str, "    Dryrun:           %t\n", ctx.Dryrun())
@@ -161,6 +207,10 @@ func (ctx AppContext) String() string {
 	fmt.Fprintf(&str, "    Daemon:           %t\n", ctx.Daemon())
 	fmt.Fprintf(&str, "    Pull:             %t\n", ctx.Pull())
 	fmt.Fprintf(&str, "    Dind:             %t\n", ctx.Dind())
+	fmt.Fprintf(&str, "    Open:             %t\n", ctx.Open())
+	fmt.Fprintf(&str, "    Wait:             %t\n", ctx.Wait())
+	fmt.Fprintf(&str, "    Engine:           %q\n", ctx.Engine())
+	fmt.Fprintf(&str, "    Profile:          %q\n", ctx.Profile())
 
 	fmt.Fprintf(&str, "# Image Configuration -----------\n")
 	fmt.Fprintf(&str, "    Dockerfile:       %q\n", ctx.Dockerfile())
@@ -176,6 +226,8 @@ func (ctx AppContext) String() string {
 	fmt.Fprintf(&str, "# Container Configuration -------\n")
 	fmt.Fprintf(&str, "    Name:             %q\n", ctx.Name())
 	fmt.Fprintf(&str, "    Port:             %q\n", ctx.Port())
+	fmt.Fprintf(&str, "    BindAddress:      %q\n", ctx.BindAddress())
+	fmt.Fprintf(&str, "    DindCache:        %q\n", ctx.DindCache())
 	fmt.Fprintf(&str, "    EnvFile:          %q\n", ctx.EnvFile())
 	fmt.Fprintf(&str, "    Startup:          %q\n", ctx.Startup())
 
@@ -185,6 +237,16 @@ func (ctx AppContext) String() string {
 	formatList(&str, "RunArgs", ctx.RunArgs(), "    ")
 	formatList(&str, "Cmds", ctx.Cmds(), "    ")
 
+	fmt.Fprintf(&str, "    Setups:           %v\n", ctx.Setups())
+
+	fmt.Fprintf(&str, "# Structured tables -------------\n")
+	fmt.Fprintf(&str, "    Mounts:           %v\n", ctx.Mounts())
+	fmt.Fprintf(&str, "    Ports:            %v\n", ctx.Ports())
+	fmt.Fprintf(&str, "    Env:              %v\n", ctx.Env())
+
+	fmt.Fprintf(&str, "# Hooks -------------------------\n")
+	fmt.Fprintf(&str, "    Hooks:            %v\n", ctx.Hooks())
+
 	str.WriteString("==================================================================\n")
 
 	return str.String()
@@ -5,6 +5,10 @@
 package appctx
 
 import (
+	"maps"
+	"slices"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
 	"github.com/nawaman/codingbooth/src/pkg/ilist"
 )
 
@@ -44,6 +48,11 @@ type AppContextBuilder struct {
 	// derived from port determination
 	PortGenerated bool
 	PortNumber    int
+	NamedPorts    map[string]int // the host ports allocated to the [ports] by name
+	ReservedPorts []int          // the generated host ports reserved until the booth runs
+
+	// docker client used by the run pipeline (nil: the docker CLI)
+	Docker docker.DockerClient
 
 	// Configurable
 	Config AppConfig
@@ -62,6 +71,8 @@ func (builder *AppContextBuilder) Clone() *AppContextBuilder {
 	copy.BuildArgs = cloneAppendableList(builder.BuildArgs)
 	copy.RunArgs = cloneAppendableList(builder.RunArgs)
 	copy.Cmds = cloneAppendableList(builder.Cmds)
+	copy.NamedPorts = maps.Clone(builder.NamedPorts)
+	copy.ReservedPorts = slices.Clone(builder.ReservedPorts)
 
 	copy.Config = *builder.Config.Clone()
 
@@ -44,6 +44,9 @@ func TestIntegration_ReadFromToml(t *testing.T) {
 verbose = true
 project-name = "toml-test-project"
 host-uid = "1002"
+
+[hooks]
+before-prepare-common-args = "./gen-certs.sh"
 `
 	tmpfile, err := os.CreateTemp("", "config-*.toml")
 	assert.NoError(t, err)
@@ -64,4 +67,5 @@ host-uid = "1002"
 	assert.True(t, config.Verbose.ValueOr(false))
 	assert.Equal(t, "toml-test-project", config.ProjectName)
 	assert.Equal(t, "1002", config.HostUID)
+	assert.Equal(t, map[string]string{"before-prepare-common-args": "./gen-certs.sh"}, config.Hooks)
 }
@@ -0,0 +1,93 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// interpolateToml returns the content of a config file with the environment variables of its string values expanded
+// (see ilist.Interpolate: $VAR, ${VAR:-default}, ${VAR:?message} and ~ at the start).
+// The problems are the missing required variables of the top-level table and of the profile table
+// (the other profiles are interpolated too, but they are not applied so their missing variables do not matter).
+//
+// Some values are kept as is: the hooks (run by a shell, which expands them) and the replace lists;
+// the ~ of the mount targets and of the [env] values is not expanded (it is the home of the booth, not of the host).
+func interpolateToml(content string, profile string) (string, []ConfigProblem) {
+	var table map[string]any
+	if _, err := toml.Decode(content, &table); err != nil {
+		// Reported by ValidateToml
+		return content, nil
+	}
+
+	problems := []ConfigProblem{}
+	interpolateTable("", table, &problems)
+	if profiles, ok := table["profile"].(map[string]any); ok {
+		for name, profileTable := range profiles {
+			if profileTable, ok := profileTable.(map[string]any); ok {
+				var profileProblems []ConfigProblem
+				interpolateTable("profile."+name+".", profileTable, &profileProblems)
+				if name == profile {
+					problems = append(problems, profileProblems...)
+				}
+			}
+		}
+	}
+	slices.SortFunc(problems, func(a, b ConfigProblem) int { return strings.Compare(a.Key, b.Key) })
+
+	var interpolated bytes.Buffer
+	if err := toml.NewEncoder(&interpolated).Encode(table); err != nil {
+		return content, append(problems, ConfigProblem{Message: err.Error()})
+	}
+	return interpolated.String(), problems
+}
+
+// interpolateTable interpolates the values of a config table (the top-level one or a profile one) in place.
+func interpolateTable(prefix string, table map[string]any, problems *[]ConfigProblem) {
+	for key, value := range table {
+		if key == "hooks" || key == "replace" || (key == "profile" && prefix == "") {
+			continue
+		}
+		table[key] = interpolateValue(prefix+key, value, key != "env", problems)
+	}
+}
+
+// interpolateValue returns the value with its strings interpolated (the ones of arrays and tables too).
+func interpolateValue(key string, value any, tilde bool, problems *[]ConfigProblem) any {
+	switch value := value.(type) {
+	case string:
+		expand := ilist.ExpandVars
+		if tilde {
+			expand = ilist.Interpolate
+		}
+		expanded, err := expand(value)
+		if err != nil {
+			for _, message := range strings.Split(err.Error(), "\n") {
+				*problems = append(*problems, ConfigProblem{Key: key, Message: message})
+			}
+		}
+		return expanded
+	case []any:
+		for i, item := range value {
+			value[i] = interpolateValue(fmt.Sprintf("%s.%d", key, i), item, tilde, problems)
+		}
+	case []map[string]any:
+		for i, item := range value {
+			interpolateValue(fmt.Sprintf("%s.%d", key, i), item, tilde, problems)
+		}
+	case map[string]any:
+		for name, item := range value {
+			// The target of a mount is in the booth
+			value[name] = interpolateValue(key+"."+name, item, tilde && name != "target", problems)
+		}
+	}
+	return value
+}
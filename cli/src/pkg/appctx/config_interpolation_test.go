@@ -0,0 +1,81 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeFromToml_Interpolation(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	t.Setenv("TEAM_REGISTRY", "registry.example.com")
+	t.Setenv("AWS_PROFILE", "")
+	path := writeToml(t, `image = "${TEAM_REGISTRY:?set the registry}/booth:${TAG:-latest}"
+dockerfile = "~/booths/Dockerfile"
+run-args = ["-e", "AWS_PROFILE=${AWS_PROFILE:-dev}", "-v", "~/.aws:/etc/cb-home-seed/.aws:ro"]
+
+[[mounts]]
+source = "~/.kube"
+target = "~/.kube"
+seed = true
+
+[ports]
+web = "${WEB_PORT:-3000}"
+
+[env]
+KUBECONFIG = "~/.kube/config"
+REGION = "${AWS_REGION:-us-east-1}"
+
+[hooks]
+before-run = "echo ${USER:-me}"
+
+[profile.ci]
+name = "${CI_NAME:?}"
+`)
+	if problems := ValidateToml(path); len(problems) > 0 {
+		t.Errorf("ValidateToml() = %v", problems)
+	}
+
+	config := AppConfig{}
+	if _, err := MergeFromToml(path, &config, "", map[string]bool{}); err != nil {
+		t.Fatalf("MergeFromToml() returned error: %v", err)
+	}
+	if config.Image != "registry.example.com/booth:latest" || config.Dockerfile != "/home/me/booths/Dockerfile" {
+		t.Errorf("image = %q, dockerfile = %q", config.Image, config.Dockerfile)
+	}
+	if want := []string{"-e", "AWS_PROFILE=dev", "-v", "/home/me/.aws:/etc/cb-home-seed/.aws:ro"}; !reflect.DeepEqual(config.RunArgs.Slice(), want) {
+		t.Errorf("run-args = %q, want %q", config.RunArgs.Slice(), want)
+	}
+	if want := (Mount{Source: "/home/me/.kube", Target: "~/.kube", Seed: true}); len(config.Mounts) != 1 || config.Mounts[0] != want {
+		t.Errorf("mounts = %+v, want %+v (the target is in the booth)", config.Mounts, want)
+	}
+	if config.Ports["web"] != "3000" {
+		t.Errorf("ports = %v", config.Ports)
+	}
+	if want := map[string]string{"KUBECONFIG": "~/.kube/config", "REGION": "us-east-1"}; !reflect.DeepEqual(config.Env, want) {
+		t.Errorf("env = %v, want %v", config.Env, want)
+	}
+	if config.Hooks["before-run"] != "echo ${USER:-me}" {
+		t.Errorf("expected the hooks to be kept for the shell, got %v", config.Hooks)
+	}
+
+	// The missing required variables of the selected profile
+	_, err := MergeFromToml(path, &AppConfig{}, "ci", map[string]bool{})
+	var validationErr *ConfigValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 1 {
+		t.Fatalf("expected a ConfigValidationError, got %v", err)
+	}
+	if got, want := validationErr.Problems[0].String(), path+":21: profile.ci.name: required variable CI_NAME is not set"; got != want {
+		t.Errorf("problem = %q, want %q", got, want)
+	}
+
+	t.Setenv("TEAM_REGISTRY", "")
+	_, err = MergeFromToml(path, &AppConfig{}, "", map[string]bool{})
+	if !errors.As(err, &validationErr) || validationErr.Problems[0].String() != path+":1: image: required variable TEAM_REGISTRY is not set: set the registry" {
+		t.Errorf("expected the missing registry, got %v", err)
+	}
+}
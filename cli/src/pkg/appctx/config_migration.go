@@ -0,0 +1,252 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DeprecatedKeys maps the config keys that were renamed to their new name (none so far).
+// The loader reads them as the new key (with a warning) and `config migrate` renames them in the file.
+var DeprecatedKeys = map[string]string{}
+
+// The home seed folder of the image before v0.12 and now.
+const (
+	legacyHomeSeed = "/tmp/ws-home-seed"
+	homeSeed       = "/etc/cb-home-seed"
+)
+
+// legacyBoothPathPattern matches the paths in the folder of the project files before .booth (e.g. ".ws/Dockerfile").
+var legacyBoothPathPattern = regexp.MustCompile(`(^|["'/=:;\s])\.ws/`)
+
+// semicolonListKeys are the keys that also take a semicolon-separated string (e.g. run-args = "-p;8080:80").
+var semicolonListKeys = []string{"common-args", "build-args", "run-args", "cmds", "setups"}
+
+// readConfigFile returns the content of a config file with its deprecated keys renamed (see renameDeprecatedKeys).
+func readConfigFile(path string) (string, []ConfigProblem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	content, deprecated := renameDeprecatedKeys(string(data))
+	return content, deprecated, nil
+}
+
+// DeprecatedKeysOf returns the deprecated keys of a config file with their replacement (for the loader to warn about).
+func DeprecatedKeysOf(path string) []ConfigProblem {
+	_, deprecated, _ := readConfigFile(path)
+	for i := range deprecated {
+		deprecated[i].File = path
+	}
+	return deprecated
+}
+
+// MigrateToml returns the content of a config file in the current format with its changes (in line order):
+// the deprecated keys renamed, the semicolon-separated lists as arrays and the legacy folders (.ws and the home seed) replaced.
+// The changes are made within their line, so the comments and the line numbers are kept;
+// the ones that cannot be made that way (e.g. in a multi-line string) are returned with a message to make them by hand.
+func MigrateToml(content string) (string, []ConfigProblem) {
+	content, changes := renameDeprecatedKeys(content)
+	content, listChanges := listsAsArrays(content)
+	content, folderChanges := replaceLegacyFolders(content)
+	changes = append(append(changes, listChanges...), folderChanges...)
+	slices.SortStableFunc(changes, func(a, b ConfigProblem) int { return a.Line - b.Line })
+	return content, changes
+}
+
+// renameDeprecatedKeys renames the DeprecatedKeys of the top-level and the profile tables.
+// A deprecated key is left as is (and ignored) when its replacement is set too.
+func renameDeprecatedKeys(content string) (string, []ConfigProblem) {
+	var table map[string]any
+	meta, err := toml.Decode(content, &table)
+	if err != nil {
+		// Reported by ValidateToml
+		return content, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	keyLines := tomlKeyLinesOf(content)
+	changes := []ConfigProblem{}
+	for _, key := range configKeysOf(meta) {
+		name := key[len(key)-1]
+		replacement, deprecated := DeprecatedKeys[name]
+		if !deprecated {
+			continue
+		}
+		path := strings.Join(key, ".")
+		line := keyLines[path]
+		change := ConfigProblem{Line: line, Key: path, Message: fmt.Sprintf("deprecated, use '%s'", replacement)}
+		switch {
+		case meta.IsDefined(append(slices.Clone(key[:len(key)-1]), replacement)...):
+			change.Message = fmt.Sprintf("deprecated and ignored ('%s' is set), remove it", replacement)
+		case line == 0 || !renameKeyOnLine(lines, line, name, replacement):
+			change.Message = fmt.Sprintf("deprecated and ignored, rename it to '%s'", replacement)
+		}
+		changes = append(changes, change)
+	}
+	return strings.Join(lines, "\n"), changes
+}
+
+// listsAsArrays rewrites the semicolon-separated strings of the list keys as arrays.
+func listsAsArrays(content string) (string, []ConfigProblem) {
+	var table map[string]any
+	meta, err := toml.Decode(content, &table)
+	if err != nil {
+		return content, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	keyLines := tomlKeyLinesOf(content)
+	changes := []ConfigProblem{}
+	for _, key := range configKeysOf(meta) {
+		if !slices.Contains(semicolonListKeys, key[len(key)-1]) || meta.Type(key...) != "String" {
+			continue
+		}
+		items := []string{}
+		for _, item := range strings.Split(valueAt(table, key).(string), ";") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		path := strings.Join(key, ".")
+		line := keyLines[path]
+		change := ConfigProblem{Line: line, Key: path, Message: "semicolon-separated list, use an array"}
+		if line == 0 || !replaceStringOnLine(lines, line, tomlArray(items)) {
+			change.Message = "semicolon-separated list, rewrite it as an array"
+		}
+		changes = append(changes, change)
+	}
+	return strings.Join(lines, "\n"), changes
+}
+
+// replaceLegacyFolders replaces the paths in .ws (now .booth) and the home seed folder of the old images (outside comments).
+func replaceLegacyFolders(content string) (string, []ConfigProblem) {
+	lines := strings.Split(content, "\n")
+	changes := []ConfigProblem{}
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if legacyBoothPathPattern.MatchString(line) {
+			line = legacyBoothPathPattern.ReplaceAllString(line, "${1}.booth/")
+			changes = append(changes, ConfigProblem{Line: i + 1, Message: "the project files are in .booth now (was .ws)"})
+		}
+		if strings.Contains(line, legacyHomeSeed) {
+			line = strings.ReplaceAll(line, legacyHomeSeed, homeSeed)
+			changes = append(changes, ConfigProblem{Line: i + 1, Message: fmt.Sprintf("the home seed folder is now %s (was %s)", homeSeed, legacyHomeSeed)})
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), changes
+}
+
+// configKeysOf returns the keys of the top-level table and of the profile tables
+// (e.g. ["port"] or ["profile", "ci", "port"]) in the order of the file.
+func configKeysOf(meta toml.MetaData) []toml.Key {
+	keys := []toml.Key{}
+	for _, key := range meta.Keys() {
+		if (len(key) == 1 && key[0] != "profile") || (len(key) == 3 && key[0] == "profile") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// valueAt returns the value of a key of a decoded table.
+func valueAt(table map[string]any, key toml.Key) any {
+	for _, part := range key[:len(key)-1] {
+		table, _ = table[part].(map[string]any)
+	}
+	return table[key[len(key)-1]]
+}
+
+// renameKeyOnLine renames the (last part of the) key of a `key = value` line (numbered from 1).
+func renameKeyOnLine(lines []string, number int, name string, replacement string) bool {
+	line := lines[number-1]
+	match := tomlKeyLinePattern.FindStringSubmatchIndex(line)
+	if match == nil {
+		return false
+	}
+	index := strings.LastIndex(line[match[2]:match[3]], name)
+	if index < 0 {
+		return false
+	}
+	index += match[2]
+	lines[number-1] = line[:index] + replacement + line[index+len(name):]
+	return true
+}
+
+// replaceStringOnLine replaces the single-line string value of a `key = "value"` line (numbered from 1),
+// keeping what follows it (e.g. a comment).
+func replaceStringOnLine(lines []string, number int, replacement string) bool {
+	line := lines[number-1]
+	match := tomlKeyLinePattern.FindStringIndex(line)
+	if match == nil {
+		return false
+	}
+	start := match[1] + len(line[match[1]:]) - len(strings.TrimLeft(line[match[1]:], " \t"))
+	length := stringLiteralLength(line[start:])
+	if length < 0 {
+		return false
+	}
+	lines[number-1] = line[:start] + replacement + line[start+length:]
+	return true
+}
+
+// stringLiteralLength returns the length of the single-line TOML string at the start of text (-1 if there is none).
+func stringLiteralLength(text string) int {
+	switch {
+	case strings.HasPrefix(text, `"""`), strings.HasPrefix(text, "'''"):
+		return -1
+	case strings.HasPrefix(text, "'"):
+		if end := strings.IndexByte(text[1:], '\''); end >= 0 {
+			return end + 2
+		}
+	case strings.HasPrefix(text, `"`):
+		for i := 1; i < len(text); i++ {
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// tomlArray returns the items as a TOML array of strings (on one line).
+func tomlArray(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = tomlString(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// tomlString returns the value as a TOML basic string.
+func tomlString(value string) string {
+	var str strings.Builder
+	str.WriteByte('"')
+	for _, ch := range value {
+		switch {
+		case ch == '"' || ch == '\\':
+			str.WriteRune('\\')
+			str.WriteRune(ch)
+		case ch < 0x20 || ch == 0x7f:
+			fmt.Fprintf(&str, `\u%04X`, ch)
+		default:
+			str.WriteRune(ch)
+		}
+	}
+	str.WriteByte('"')
+	return str.String()
+}
@@ -0,0 +1,142 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrateToml(t *testing.T) {
+	content := `# The booth of the project
+variant = "codeserver"   # the variant
+dockerfile = ".ws/Dockerfile"
+
+run-args = "-p;8080:8080; -v;~/.aws:/tmp/ws-home-seed/.aws:ro"   # ports and seeds
+build-args = ["A=1"]
+# run-args = "/tmp/ws-home-seed in a comment"
+
+[profile.ci]
+name = "ci"
+cmds = 'echo "hi";make'
+setups = """go;gh"""
+`
+	migrated, changes := MigrateToml(content)
+
+	want := `# The booth of the project
+variant = "codeserver"   # the variant
+dockerfile = ".booth/Dockerfile"
+
+run-args = ["-p", "8080:8080", "-v", "~/.aws:/etc/cb-home-seed/.aws:ro"]   # ports and seeds
+build-args = ["A=1"]
+# run-args = "/tmp/ws-home-seed in a comment"
+
+[profile.ci]
+name = "ci"
+cmds = ["echo \"hi\"", "make"]
+setups = """go;gh"""
+`
+	if migrated != want {
+		t.Errorf("MigrateToml() =\n%s\nwant\n%s", migrated, want)
+	}
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	wantChanges := []string{
+		"the project files are in .booth now (was .ws)",
+		"run-args: semicolon-separated list, use an array",
+		"the home seed folder is now /etc/cb-home-seed (was /tmp/ws-home-seed)",
+		"profile.ci.cmds: semicolon-separated list, use an array",
+		"profile.ci.setups: semicolon-separated list, rewrite it as an array",
+	}
+	if !reflect.DeepEqual(got, wantChanges) {
+		t.Errorf("changes =\n%q\nwant\n%q", got, wantChanges)
+	}
+
+	if again, changes := MigrateToml(migrated); again != migrated || len(changes) != 1 {
+		t.Errorf("expected only the change to make by hand on the migrated content, got %v", changes)
+	}
+}
+
+func TestMigrateToml_DeprecatedKeys(t *testing.T) {
+	withDeprecatedKeys(t, map[string]string{"variant-tag": "variant", "container-name": "name"})
+	content := "variant-tag = \"notebook\"   # the variant\n\n[profile.ci]\ncontainer-name = \"ci\"\n"
+
+	migrated, changes := MigrateToml(content)
+
+	if want := "variant = \"notebook\"   # the variant\n\n[profile.ci]\nname = \"ci\"\n"; migrated != want {
+		t.Errorf("MigrateToml() =\n%s\nwant\n%s", migrated, want)
+	}
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"variant-tag: deprecated, use 'variant'",
+		"profile.ci.container-name: deprecated, use 'name'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestMigrateToml_DeprecatedKeyWithItsReplacement(t *testing.T) {
+	withDeprecatedKeys(t, map[string]string{"image-name": "image"})
+	content := "image-name = \"old\"\nimage = \"new\"\n"
+	migrated, changes := MigrateToml(content)
+	if migrated != content {
+		t.Errorf("expected the content to be kept, got:\n%s", migrated)
+	}
+	if len(changes) != 1 || changes[0].Line != 1 || changes[0].Message != "deprecated and ignored ('image' is set), remove it" {
+		t.Errorf("changes = %v", changes)
+	}
+}
+
+func TestMergeFromToml_DeprecatedKeys(t *testing.T) {
+	withDeprecatedKeys(t, map[string]string{"variant-tag": "variant", "image-name": "image", "container-name": "name"})
+	path := writeToml(t, `variant-tag = "notebook"
+image-name = "old"
+image = "new"
+
+[profile.ci]
+container-name = "ci-booth"
+`)
+	if problems := ValidateToml(path); len(problems) > 0 {
+		t.Errorf("expected the deprecated keys to be valid, got %v", problems)
+	}
+	if lines := TomlKeyLines(path); lines["variant"] != 1 || lines["profile.ci.name"] != 6 {
+		t.Errorf("TomlKeyLines() = %v", lines)
+	}
+
+	config := AppConfig{}
+	if _, err := MergeFromToml(path, &config, "ci", map[string]bool{}); err != nil {
+		t.Fatalf("MergeFromToml() returned error: %v", err)
+	}
+	if config.Variant != "notebook" || config.Image != "new" || config.Name != "ci-booth" {
+		t.Errorf("config = variant %q, image %q, name %q", config.Variant, config.Image, config.Name)
+	}
+
+	var got []string
+	for _, deprecated := range DeprecatedKeysOf(path) {
+		got = append(got, deprecated.String()[len(path):])
+	}
+	want := []string{
+		":1: variant-tag: deprecated, use 'variant'",
+		":2: image-name: deprecated and ignored ('image' is set), remove it",
+		":6: profile.ci.container-name: deprecated, use 'name'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeprecatedKeysOf() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+// withDeprecatedKeys replaces DeprecatedKeys for the test (there are no renamed keys yet).
+func withDeprecatedKeys(t *testing.T, keys map[string]string) {
+	saved := DeprecatedKeys
+	DeprecatedKeys = keys
+	t.Cleanup(func() { DeprecatedKeys = saved })
+}
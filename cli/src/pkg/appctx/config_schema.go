@@ -0,0 +1,203 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+// configDescriptions describes the config keys in the JSON Schema (every AppConfig key must have one).
+var configDescriptions = map[string]string{
+	"dryrun":        "Print the docker commands without running them.",
+	"verbose":       "Print extra debugging information.",
+	"config":        "Path to the config file (a bootstrap option: only taken from --config or the default).",
+	"code":          "Path to the code folder mounted in the booth (a bootstrap option: only taken from --code or the current folder).",
+	"version":       "Prebuilt image version tag.",
+	"keep-alive":    "Do not remove the container when it stops.",
+	"silence-build": "Hide the build progress; show the output only on failure.",
+	"daemon":        "Run the booth container in the background.",
+	"pull":          "Always pull the image, even if it exists locally.",
+	"dind":          "Run a Docker-in-Docker sidecar and set DOCKER_HOST.",
+	"open":          "Open the booth UI in the host browser once it is ready.",
+	"wait":          "In daemon mode, return only once the booth UI is ready.",
+	"engine":        "Container engine that runs the booth.",
+	"dockerfile":    "Dockerfile (or a folder with .booth/Dockerfile) to build the image locally.",
+	"image":         "Existing local or remote image to run (wins over dockerfile and variant).",
+	"variant":       "Prebuilt variant (base, notebook, codeserver, desktop-xfce, desktop-kde) or an alias.",
+	"project-name":  "Project name (default: the code folder name).",
+	"host-uid":      "UID of the host user the coder user is mapped to (default: detected).",
+	"host-gid":      "GID of the host user the coder user is mapped to (default: detected).",
+	"timezone":      "Timezone of the booth (default: detected).",
+	"name":          "Container name (default: the project name).",
+	"port":          "Host port mapped to the booth port 10000: a number (1-65535), NEXT or RANDOM.",
+	"bind-address":  "Host address the booth ports are published on (default: 127.0.0.1, only this machine); 0.0.0.0 publishes them on all interfaces.",
+	"dind-cache":    "Docker image cache of the DinD sidecar (its /var/lib/docker): a volume per project, one shared by all projects or none.",
+	"env-file":      "Env file passed to docker run ('none' disables the default <code>/.env).",
+	"startup":       "Custom startup script run in the booth.",
+	"common-args":   "Flags applied before the command-line flags.",
+	"build-args":    "Extra args for docker build (only with dockerfile).",
+	"run-args":      "Extra args for docker run.",
+	"cmds":          "Command to run in the booth (replaced by the command after '--').",
+	"setups":        "Setup scripts of the image (\"<name>\" or \"<name>@<version>\", e.g. \"go@1.23\") installed in a local image built without a Dockerfile.",
+	"mounts":        "Host paths mounted in the booth ([[mounts]] tables); seed mounts are copied into the home at startup.",
+	"ports":         "Extra published ports by name ([ports] table): a container port (published on the same host port) or \"<host>:<container>\" where <host> can be NEXT or RANDOM; exported to the booth as CB_PORT_<NAME>.",
+	"env":           "Environment variables of the booth ([env] table: NAME = \"value\").",
+	"hooks":         "Host commands run before or after a stage: before-<stage> or after-<stage> = \"<shell command>\".",
+}
+
+// configEnums lists the allowed values of the keys that have a fixed set of values.
+var configEnums = map[string][]string{
+	"engine":     {"cli", "api", "docker", "podman", "nerdctl"},
+	"dind-cache": DindCacheModes,
+}
+
+// mapKeyPatterns are the patterns of the keys of the map fields.
+var mapKeyPatterns = map[string]string{
+	"env":   envNamePattern.String(),
+	"hooks": "^(before|after)-.+$",
+}
+
+// mountSchema returns the schema of a [[mounts]] entry.
+func mountSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"source"},
+		"properties": map[string]any{
+			"source":   map[string]any{"type": "string", "description": "Host path (~ and $VAR are expanded; \"./<path>\" is relative to the code folder) or a docker volume name."},
+			"target":   map[string]any{"type": "string", "description": "Absolute path in the booth; with seed, a path relative to the home (default: the source relative to the host home)."},
+			"mode":     map[string]any{"enum": []string{"rw", "ro"}, "default": "rw"},
+			"seed":     map[string]any{"type": "boolean", "default": false, "description": "Mount read-only in /etc/cb-home-seed so it is copied into the home at startup."},
+			"optional": map[string]any{"type": "boolean", "default": false, "description": "Skip the mount (with a warning) when the source does not exist."},
+		},
+		"additionalProperties": false,
+	}
+}
+
+// ConfigSchema returns the JSON Schema of config.toml, generated from the toml, envconfig and default tags of AppConfig.
+// It returns an error for a field without a description or with a type the schema does not know.
+func ConfigSchema() (map[string]any, error) {
+	properties := map[string]any{}
+	configType := reflect.TypeOf(AppConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		property, err := fieldSchema(field, key)
+		if err != nil {
+			return nil, err
+		}
+		properties[key] = property
+	}
+	properties["replace"] = map[string]any{
+		"description": "Lists that replace the ones of the lower config layers instead of appending to them.",
+		"type":        "array",
+		"items":       map[string]any{"enum": AppendedListKeys},
+		"uniqueItems": true,
+	}
+
+	profileProperties := map[string]any{}
+	for key, property := range properties {
+		profileProperties[key] = property
+	}
+	properties["profile"] = map[string]any{
+		"description": "Profiles ([profile.<name>] tables) selected with --profile or CB_PROFILE; their keys override the ones of the file.",
+		"type":        "object",
+		"additionalProperties": map[string]any{
+			"type":                 "object",
+			"properties":           profileProperties,
+			"additionalProperties": false,
+		},
+	}
+
+	return map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "CodingBooth config (.booth/config.toml)",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}, nil
+}
+
+// ConfigSchemaJSON returns the JSON Schema of config.toml as indented JSON (see ConfigSchema).
+func ConfigSchemaJSON() ([]byte, error) {
+	schema, err := ConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+var (
+	nillableBoolType   = reflect.TypeOf(nillable.NillableBool{})
+	nillableStringType = reflect.TypeOf(nillable.NillableString{})
+	stringListType     = reflect.TypeOf(ilist.SemicolonStringList{})
+)
+
+// fieldSchema returns the schema of an AppConfig field.
+func fieldSchema(field reflect.StructField, key string) (map[string]any, error) {
+	description, found := configDescriptions[key]
+	if !found {
+		return nil, fmt.Errorf("config key '%s' (AppConfig.%s) has no description for the schema", key, field.Name)
+	}
+	if env := field.Tag.Get("envconfig"); env != "" {
+		description += " Env var: " + env + "."
+	}
+	property := map[string]any{"description": description}
+
+	switch {
+	case field.Type == nillableBoolType || field.Type.Kind() == reflect.Bool:
+		property["type"] = "boolean"
+	case field.Type == nillableStringType || field.Type.Kind() == reflect.String:
+		property["type"] = "string"
+	case field.Type == stringListType:
+		property["oneOf"] = []any{
+			map[string]any{"type": "string", "description": "Semicolon-separated values (e.g. \"-p;8080:8080\")."},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		}
+	case field.Type == reflect.TypeOf([]Mount{}):
+		property["type"] = "array"
+		property["items"] = mountSchema()
+	case field.Type == reflect.TypeOf(map[string]PortMapping{}):
+		property["type"] = "object"
+		property["additionalProperties"] = map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "integer", "minimum": 1, "maximum": 65535},
+				map[string]any{"type": "string", "pattern": portMappingPattern.String()},
+				map[string]any{"type": "string", "pattern": `\$\{?[A-Za-z_]`,
+					"description": "Interpolated from the environment (e.g. \"${WEB_PORT:-3000}\")."},
+			},
+		}
+	case field.Type == reflect.TypeOf(map[string]string{}):
+		property["type"] = "object"
+		property["propertyNames"] = map[string]any{"pattern": mapKeyPatterns[key]}
+		property["additionalProperties"] = map[string]any{"type": "string"}
+	default:
+		return nil, fmt.Errorf("config key '%s' (AppConfig.%s) has a type the schema does not know: %s", key, field.Name, field.Type)
+	}
+
+	if value, found := field.Tag.Lookup("default"); found {
+		if property["type"] == "boolean" {
+			property["default"] = value == "true"
+		} else {
+			property["default"] = value
+		}
+	}
+	if enum, found := configEnums[key]; found {
+		property["enum"] = enum
+	}
+	return property, nil
+}
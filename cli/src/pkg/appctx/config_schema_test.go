@@ -0,0 +1,66 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// schemaFile is the published schema (relative to this package).
+var schemaFile = filepath.Join("..", "..", "..", "..", "docs", "config.schema.json")
+
+func TestConfigSchema_IsPublished(t *testing.T) {
+	generated, err := ConfigSchemaJSON()
+	if err != nil {
+		t.Fatalf("ConfigSchemaJSON() returned error: %v", err)
+	}
+	published, err := os.ReadFile(schemaFile)
+	if err != nil {
+		t.Fatalf("cannot read the published schema: %v", err)
+	}
+	if !bytes.Equal(generated, published) {
+		t.Errorf("docs/config.schema.json is out of date with AppConfig; regenerate it with:\n" +
+			"    coding-booth config schema > docs/config.schema.json")
+	}
+}
+
+func TestConfigSchema_MatchesAppConfig(t *testing.T) {
+	schema, err := ConfigSchema()
+	if err != nil {
+		t.Fatalf("ConfigSchema() returned error: %v", err)
+	}
+	properties := schema["properties"].(map[string]any)
+
+	keys := []string{}
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if want := ConfigKeys(); !reflect.DeepEqual(keys, want) {
+		t.Errorf("schema keys = %v\nwant the config keys %v", keys, want)
+	}
+
+	port := properties["port"].(map[string]any)
+	if port["type"] != "string" || port["default"] != "NEXT" {
+		t.Errorf("port = %v", port)
+	}
+	dind := properties["dind"].(map[string]any)
+	if dind["type"] != "boolean" || dind["default"] != false {
+		t.Errorf("dind = %v", dind)
+	}
+	runArgs := properties["run-args"].(map[string]any)
+	if oneOf, ok := runArgs["oneOf"].([]any); !ok || len(oneOf) != 2 {
+		t.Errorf("run-args must accept a semicolon string or an array: %v", runArgs)
+	}
+	if description := properties["image"].(map[string]any)["description"].(string); !strings.HasSuffix(description, "Env var: CB_IMAGE.") {
+		t.Errorf("image description = %q", description)
+	}
+}
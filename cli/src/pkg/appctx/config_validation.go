@@ -0,0 +1,469 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigProblem is a problem of a config file (see ValidateToml) or of the resolved config (see AppConfig.Validate).
+type ConfigProblem struct {
+	// File is the config file (empty for the resolved config).
+	File string
+	// Line is the line of the key in the file (0 if unknown).
+	Line    int
+	Key     string
+	Message string
+}
+
+func (problem ConfigProblem) String() string {
+	location := problem.File
+	if location != "" && problem.Line > 0 {
+		location += ":" + strconv.Itoa(problem.Line)
+	}
+	if location != "" {
+		location += ": "
+	}
+	if problem.Key == "" {
+		return location + problem.Message
+	}
+	return location + problem.Key + ": " + problem.Message
+}
+
+// ConfigValidationError is returned when the config files or the resolved config have problems.
+type ConfigValidationError struct {
+	Problems []ConfigProblem
+}
+
+func (e *ConfigValidationError) Error() string {
+	var str strings.Builder
+	fmt.Fprintf(&str, "invalid config (%d problem(s)):", len(e.Problems))
+	for _, problem := range e.Problems {
+		str.WriteString("\n  - " + problem.String())
+	}
+	return str.String()
+}
+
+// ValidateToml checks a config file: syntax, unknown keys (with a suggestion), values of the wrong type,
+// `replace` entries, port values and keys that cannot be combined. The keys of the `[profile.<name>]` tables are checked too.
+func ValidateToml(path string) []ConfigProblem {
+	content, _, err := readConfigFile(path)
+	if err != nil {
+		return []ConfigProblem{{File: path, Message: err.Error()}}
+	}
+	// The values are checked as they are read (see MergeFromToml); the lines are the ones of the file
+	interpolated, _ := interpolateToml(content, "")
+	var table map[string]toml.Primitive
+	meta, err := toml.Decode(interpolated, &table)
+	if err != nil {
+		problem := ConfigProblem{File: path, Message: strings.TrimPrefix(err.Error(), "toml: ")}
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			problem.Line = parseErr.Position.Line
+			problem.Message = parseErr.Message
+		}
+		return []ConfigProblem{problem}
+	}
+	validator := tomlValidator{path: path, lines: tomlKeyLinesOf(content), meta: meta}
+	validator.validateTable("", table)
+	slices.SortStableFunc(validator.problems, func(a, b ConfigProblem) int { return a.Line - b.Line })
+	return validator.problems
+}
+
+// Validate checks the combinations of the resolved config (after the env vars, config files and CLI flags).
+func (config AppConfig) Validate() []ConfigProblem {
+	problems := []ConfigProblem{}
+	if message := portProblem(config.Port); message != "" {
+		problems = append(problems, ConfigProblem{Key: "port", Message: message})
+	}
+	if message := dindCacheProblem(config.DindCache); message != "" {
+		problems = append(problems, ConfigProblem{Key: "dind-cache", Message: message})
+	}
+	if config.BuildArgs.Length() > 0 && config.Dockerfile == "" && config.Setups.Length() == 0 {
+		problems = append(problems, ConfigProblem{Key: "build-args",
+			Message: "build args are only used with a dockerfile or setups (set dockerfile or remove build-args/--build-arg)"})
+	}
+	return problems
+}
+
+// tomlValidator collects the problems of a config file.
+type tomlValidator struct {
+	path     string
+	lines    map[string]int
+	meta     toml.MetaData
+	problems []ConfigProblem
+}
+
+func (validator *tomlValidator) report(key string, format string, args ...any) {
+	validator.problems = append(validator.problems, ConfigProblem{
+		File:    validator.path,
+		Line:    lineOfKey(validator.lines, key),
+		Key:     key,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// lineOfKey returns the line of the key or of its closest parent (e.g. an inline table) from TomlKeyLines.
+func lineOfKey(lines map[string]int, key string) int {
+	line := 0
+	for parent := key; line == 0 && parent != ""; parent, _ = cutLast(parent, ".") {
+		line = lines[parent]
+	}
+	return line
+}
+
+// validateTable checks the keys of the top-level table (prefix "") or of a profile table (prefix "profile.<name>.").
+func (validator *tomlValidator) validateTable(prefix string, table map[string]toml.Primitive) {
+	fields := configFields()
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	// In the order of the file
+	slices.SortFunc(keys, func(a, b string) int {
+		if diff := validator.lines[prefix+a] - validator.lines[prefix+b]; diff != 0 {
+			return diff
+		}
+		return strings.Compare(a, b)
+	})
+
+	config := AppConfig{}
+	configValue := reflect.ValueOf(&config).Elem()
+	for _, key := range keys {
+		switch index, isField := fields[key]; {
+		case key == "replace":
+			var replace []string
+			if err := validator.meta.PrimitiveDecode(table[key], &replace); err != nil {
+				validator.report(prefix+key, "%s", decodeMessage(err))
+				continue
+			}
+			for _, name := range replace {
+				if !slices.Contains(AppendedListKeys, name) {
+					validator.report(prefix+key, "'%s' cannot be replaced (only: %s)", name, strings.Join(AppendedListKeys, ", "))
+				}
+			}
+
+		case key == "profile" && prefix == "":
+			var profiles map[string]map[string]toml.Primitive
+			if err := validator.meta.PrimitiveDecode(table[key], &profiles); err != nil {
+				validator.report(key, "profiles must be tables ([profile.<name>]): %s", decodeMessage(err))
+				continue
+			}
+			names := make([]string, 0, len(profiles))
+			for name := range profiles {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				validator.validateTable("profile."+name+".", profiles[name])
+			}
+
+		case isField:
+			if err := validator.meta.PrimitiveDecode(table[key], configValue.Field(index).Addr().Interface()); err != nil {
+				validator.report(prefix+key, "%s", decodeMessage(err))
+				continue
+			}
+			validator.validateTables(prefix, key, table[key], &config)
+
+		case DeprecatedKeys[key] != "":
+			// Left by renameDeprecatedKeys (the loader warns about it; see DeprecatedKeysOf)
+
+		default:
+			if suggestion := suggestKey(key); suggestion != "" {
+				validator.report(prefix+key, "unknown key (did you mean '%s'?)", suggestion)
+			} else {
+				validator.report(prefix+key, "unknown key")
+			}
+		}
+	}
+
+	if _, found := table["port"]; found {
+		if message := portProblem(config.Port); message != "" {
+			validator.report(prefix+"port", "%s", message)
+		}
+	}
+	if _, found := table["dind-cache"]; found {
+		if message := dindCacheProblem(config.DindCache); message != "" {
+			validator.report(prefix+"dind-cache", "%s", message)
+		}
+	}
+	if _, found := table["cmds"]; found && config.Daemon {
+		validator.report(prefix+"cmds", "cannot be combined with daemon = true in the same config (use '--daemon -- <cmd>' for a one-off background command)")
+	}
+}
+
+// validateTables checks the entries of the setups list and of the [[mounts]], [ports] and [env] tables (decoded in config).
+func (validator *tomlValidator) validateTables(prefix string, key string, primitive toml.Primitive, config *AppConfig) {
+	switch key {
+	case "mounts":
+		var entries []map[string]toml.Primitive
+		validator.meta.PrimitiveDecode(primitive, &entries)
+		known := tomlKeysOf(reflect.TypeOf(Mount{}))
+		for i, mount := range config.Mounts {
+			entryKey := fmt.Sprintf("%s%s.%d", prefix, key, i)
+			for name := range entries[i] {
+				if !slices.Contains(known, name) {
+					validator.report(entryKey+"."+name, "unknown mount key (keys: %s)", strings.Join(known, ", "))
+				}
+			}
+			for _, problem := range mount.problems() {
+				validator.report(entryKey, "%s", problem)
+			}
+		}
+	case "ports":
+		for _, name := range sortedKeys(config.Ports) {
+			if problem := config.Ports[name].problem(); problem != "" {
+				validator.report(prefix+key+"."+name, "%s", problem)
+			}
+		}
+	case "setups":
+		for _, problem := range setupsProblems(config.Setups.Slice()) {
+			validator.report(prefix+key, "%s", problem)
+		}
+	case "env":
+		for _, name := range sortedKeys(config.Env) {
+			if !envNamePattern.MatchString(name) {
+				validator.report(prefix+key+"."+name, "'%s' is not an environment variable name", name)
+			}
+		}
+	}
+}
+
+// tomlKeysOf returns the TOML keys of a struct type.
+func tomlKeysOf(structType reflect.Type) []string {
+	keys := []string{}
+	for i := 0; i < structType.NumField(); i++ {
+		key, _, _ := strings.Cut(structType.Field(i).Tag.Get("toml"), ",")
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	return slices.Sorted(maps.Keys(values))
+}
+
+// configFields returns the index of the AppConfig fields by their TOML key.
+func configFields() map[string]int {
+	fields := map[string]int{}
+	configType := reflect.TypeOf(AppConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		key, _, _ := strings.Cut(configType.Field(i).Tag.Get("toml"), ",")
+		if key != "" && key != "-" {
+			fields[key] = i
+		}
+	}
+	return fields
+}
+
+// ConfigKeys returns the keys of a config file: the AppConfig keys, "replace" and "profile".
+func ConfigKeys() []string {
+	keys := []string{"replace", "profile"}
+	for key := range configFields() {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// suggestKey returns the known key closest to an unknown one (e.g. "keep-alive" for "keepalive" or "keep_alive").
+func suggestKey(key string) string {
+	return closest(key, ConfigKeys())
+}
+
+// closest returns the candidate that is the same name with other separators or case, or the closest one
+// (within an edit distance of 2), or "".
+func closest(name string, candidates []string) string {
+	normalize := func(name string) string {
+		return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	}
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if normalize(candidate) == normalize(name) {
+			return candidate
+		}
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// portProblem returns why a port value is invalid ("" if valid): it must be NEXT, RANDOM or a number in 1-65535.
+func portProblem(port string) string {
+	switch strings.ToUpper(port) {
+	case "", "NEXT", "RANDOM":
+		return ""
+	}
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Sprintf("'%s' is not a port (use a number, NEXT or RANDOM)", port)
+	}
+	if number < 1 || number > 65535 {
+		return fmt.Sprintf("%d is out of the port range 1-65535", number)
+	}
+	return ""
+}
+
+// DindCacheModes are the values of dind-cache: a cache volume per project, one shared by all the projects or none.
+var DindCacheModes = []string{"project", "shared", "none"}
+
+// dindCacheProblem returns why a dind-cache value is invalid ("" if valid).
+func dindCacheProblem(mode string) string {
+	if mode == "" || slices.Contains(DindCacheModes, mode) {
+		return ""
+	}
+	return fmt.Sprintf("'%s' is not a DinD cache mode (use %s)", mode, strings.Join(DindCacheModes, ", "))
+}
+
+var decodeErrorPrefix = regexp.MustCompile(`^toml: (line \d+ )?(\(last key "[^"]*"\): )?`)
+
+// decodeMessage returns the message of a TOML decode error without its "toml: line N (last key ...)" prefix.
+func decodeMessage(err error) string {
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.Message
+	}
+	return decodeErrorPrefix.ReplaceAllString(err.Error(), "")
+}
+
+var (
+	tomlKeyLinePattern   = regexp.MustCompile(`^\s*((?:"[^"]*"|[A-Za-z0-9_-]+)(?:\s*\.\s*(?:"[^"]*"|[A-Za-z0-9_-]+))*)\s*=`)
+	tomlTableLinePattern = regexp.MustCompile(`^\s*\[\[?\s*((?:"[^"]*"|[A-Za-z0-9_-]+)(?:\s*\.\s*(?:"[^"]*"|[A-Za-z0-9_-]+))*)\s*\]`)
+)
+
+// TomlKeyLines returns the first line of each key and table of a TOML file by their dotted path
+// (e.g. "port", "hooks", "hooks.before-run" or "profile.ci.port"); entries of arrays of tables are numbered (e.g. "mounts.0.source").
+// The deprecated keys are at the line of their replacement (see DeprecatedKeys).
+func TomlKeyLines(path string) map[string]int {
+	content, _, err := readConfigFile(path)
+	if err != nil {
+		return map[string]int{}
+	}
+	return tomlKeyLinesOf(content)
+}
+
+// tomlKeyLinesOf returns the first line of each key and table of the content of a TOML file (see TomlKeyLines).
+func tomlKeyLinesOf(content string) map[string]int {
+	lines := map[string]int{}
+	table := ""
+	depth := 0
+	arrays := map[string]int{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if depth > 0 {
+			// Inside a multi-line array
+			depth += bracketDepth(line)
+			continue
+		}
+		if match := tomlTableLinePattern.FindStringSubmatch(line); match != nil {
+			table = dottedKey(match[1])
+			if strings.HasPrefix(strings.TrimSpace(line), "[[") {
+				// An array of tables: its entries are "<table>.<index>"
+				index := arrays[table]
+				arrays[table]++
+				table += "." + strconv.Itoa(index)
+			}
+			// The parent tables too (e.g. "profile" of "profile.ci")
+			for parent := table; parent != ""; parent, _ = cutLast(parent, ".") {
+				if _, found := lines[parent]; !found {
+					lines[parent] = number
+				}
+			}
+			continue
+		}
+		match := tomlKeyLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		key := dottedKey(match[1])
+		if table != "" {
+			key = table + "." + key
+		}
+		if _, found := lines[key]; !found {
+			lines[key] = number
+		}
+		depth = bracketDepth(line[len(match[0]):])
+	}
+	return lines
+}
+
+// cutLast slices a string around the last separator (like strings.Cut from the end).
+func cutLast(str string, separator string) (string, string) {
+	index := strings.LastIndex(str, separator)
+	if index < 0 {
+		return "", str
+	}
+	return str[:index], str[index+len(separator):]
+}
+
+// dottedKey returns a TOML key (e.g. `profile . "ci"`) as a dotted path (e.g. "profile.ci").
+func dottedKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// bracketDepth returns the number of '[' minus the number of ']' outside strings and comments.
+func bracketDepth(line string) int {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, ch := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && ch == '\\':
+			escaped = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#':
+			return depth
+		case ch == '[':
+			depth++
+		case ch == ']':
+			depth--
+		}
+	}
+	return depth
+}
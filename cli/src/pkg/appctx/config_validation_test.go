@@ -0,0 +1,133 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package appctx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeToml(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateToml(t *testing.T) {
+	path := writeToml(t, `variant = "base"
+keepalive = true
+run-args = [
+    "-e", "PORT=1",
+    "-v", "/a:/b",
+]
+dind = "yes"
+build-args = ["--no-cache", 3]
+port = "abc"
+replace = ["cmds"]
+daemon = true
+cmds = ["sleep", "10"]
+
+[hooks]
+before-run = "echo hi"
+
+[profile.ci]
+prot = "NEXT"
+`)
+
+	var got []string
+	for _, problem := range ValidateToml(path) {
+		got = append(got, problem.String()[len(path):])
+	}
+	want := []string{
+		":2: keepalive: unknown key (did you mean 'keep-alive'?)",
+		":7: dind: incompatible types: TOML value has type string; destination has type boolean",
+		":8: build-args: item 1 of the array is a int64 (3), not a string",
+		":9: port: 'abc' is not a port (use a number, NEXT or RANDOM)",
+		":10: replace: 'cmds' cannot be replaced (only: common-args, build-args, run-args, setups, mounts)",
+		":12: cmds: cannot be combined with daemon = true in the same config (use '--daemon -- <cmd>' for a one-off background command)",
+		":18: profile.ci.prot: unknown key (did you mean 'port'?)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateToml() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestValidateToml_Valid(t *testing.T) {
+	path := writeToml(t, `variant = "base"
+port = "NEXT"
+run-args = "-p;8080:8080"
+replace = ["run-args"]
+
+[profile.desktop]
+variant = "xfce"
+port = "12000"
+`)
+	if problems := ValidateToml(path); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateToml_Syntax(t *testing.T) {
+	path := writeToml(t, "variant = \"base\"\nport = \n")
+	problems := ValidateToml(path)
+	if len(problems) != 1 || problems[0].Line != 2 {
+		t.Errorf("expected one syntax problem on line 2, got %v", problems)
+	}
+}
+
+func TestAppConfig_Validate(t *testing.T) {
+	config := AppConfig{Port: "70000", DindCache: "always"}
+	config.BuildArgs.Decode("--no-cache")
+
+	want := []ConfigProblem{
+		{Key: "port", Message: "70000 is out of the port range 1-65535"},
+		{Key: "dind-cache", Message: "'always' is not a DinD cache mode (use project, shared, none)"},
+		{Key: "build-args", Message: "build args are only used with a dockerfile or setups (set dockerfile or remove build-args/--build-arg)"},
+	}
+	if got := config.Validate(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+
+	config.Port = "random"
+	config.DindCache = "shared"
+	config.Dockerfile = ".booth/Dockerfile"
+	if got := config.Validate(); len(got) != 0 {
+		t.Errorf("Validate() = %v, want no problems", got)
+	}
+}
+
+func TestTomlKeyLines(t *testing.T) {
+	path := writeToml(t, `# comment
+port = "NEXT"
+run-args = [
+    "-e", "variant=x",   # [not a table]
+]
+"quoted-key" = 1
+
+[hooks]
+before-run = "echo [x]"
+
+[profile . "ci"]
+port = "RANDOM"
+`)
+	want := map[string]int{
+		"port":             2,
+		"run-args":         3,
+		"quoted-key":       6,
+		"hooks":            8,
+		"hooks.before-run": 9,
+		"profile":          11,
+		"profile.ci":       11,
+		"profile.ci.port":  12,
+	}
+	if got := TomlKeyLines(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("TomlKeyLines() = %v, want %v", got, want)
+	}
+}
@@ -14,7 +14,8 @@ import (
 )
 
 // ApplyEnvFile applies environment file configuration and returns updated AppContext.
-func ApplyEnvFile(ctx appctx.AppContext) appctx.AppContext {
+// It returns an EnvFileMissingError when the given env file does not exist.
+func ApplyEnvFile(ctx appctx.AppContext) (appctx.AppContext, error) {
 	builder := ctx.ToBuilder()
 
 	containerEnvFile := ctx.EnvFile()
@@ -33,14 +34,13 @@ func ApplyEnvFile(ctx appctx.AppContext) appctx.AppContext {
 		if ctx.Verbose() {
 			fmt.Println("Skipping --env-file (explicitly disabled).")
 		}
-		return builder.Build()
+		return builder.Build(), nil
 	}
 
 	// If specified, it must exist; otherwise error out
 	if containerEnvFile != "" {
 		if !fileExists(containerEnvFile) {
-			fmt.Fprintf(os.Stderr, "Error: env-file must be an existing file: %s\n", containerEnvFile)
-			os.Exit(1)
+			return ctx, &EnvFileMissingError{Path: containerEnvFile}
 		}
 
 		builder.CommonArgs.Append(ilist.NewList[string]("--env-file", containerEnvFile))
@@ -49,7 +49,7 @@ func ApplyEnvFile(ctx appctx.AppContext) appctx.AppContext {
 		}
 	}
 
-	return builder.Build()
+	return builder.Build(), nil
 }
 
 // fileExists checks if a file exists.
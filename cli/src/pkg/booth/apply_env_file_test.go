@@ -5,6 +5,7 @@
 package booth
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -51,7 +52,10 @@ func TestApplyEnvFile_Default(t *testing.T) {
 	ctx := builder.Build()
 
 	// Execute
-	newCtx := ApplyEnvFile(ctx)
+	newCtx, err := ApplyEnvFile(ctx)
+	if err != nil {
+		t.Fatalf("ApplyEnvFile() returned error: %v", err)
+	}
 
 	// Verify
 	// It should have added --env-file ./.env to CommonArgs
@@ -89,7 +93,10 @@ func TestApplyEnvFile_Explicit(t *testing.T) {
 	builder.Config.EnvFile = myEnv // Explicitly set
 
 	ctx := builder.Build()
-	newCtx := ApplyEnvFile(ctx)
+	newCtx, err := ApplyEnvFile(ctx)
+	if err != nil {
+		t.Fatalf("ApplyEnvFile() returned error: %v", err)
+	}
 
 	args := flattenArgs(newCtx.CommonArgs())
 	found := false
@@ -114,7 +121,10 @@ func TestApplyEnvFile_Disabled(t *testing.T) {
 	builder.Config.Verbose = nillable.NewNillableBool(true)
 
 	ctx := builder.Build()
-	newCtx := ApplyEnvFile(ctx)
+	newCtx, err := ApplyEnvFile(ctx)
+	if err != nil {
+		t.Fatalf("ApplyEnvFile() returned error: %v", err)
+	}
 
 	args := flattenArgs(newCtx.CommonArgs())
 	for _, arg := range args {
@@ -123,3 +133,17 @@ func TestApplyEnvFile_Disabled(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyEnvFile_Missing(t *testing.T) {
+	builder := &appctx.AppContextBuilder{
+		CommonArgs: ilist.NewAppendableList[ilist.List[string]](),
+	}
+	builder.Config.EnvFile = filepath.Join(t.TempDir(), "missing.env")
+
+	_, err := ApplyEnvFile(builder.Build())
+
+	var envErr *EnvFileMissingError
+	if !errors.As(err, &envErr) || envErr.Path != builder.Config.EnvFile {
+		t.Fatalf("expected EnvFileMissingError, got %v", err)
+	}
+}
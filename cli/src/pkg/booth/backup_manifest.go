@@ -0,0 +1,85 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// BackupManifest describes the booth image stored in a backup archive.
+type BackupManifest struct {
+	FormatVersion int    `json:"formatVersion"`
+	Image         string `json:"image"`
+	Project       string `json:"project,omitempty"`
+	Variant       string `json:"variant,omitempty"`
+	Version       string `json:"version,omitempty"`
+	CodePath      string `json:"codePath,omitempty"`
+	ConfigFile    string `json:"configFile,omitempty"`
+	HasConfig     bool   `json:"hasConfig"`
+	CreatedAt     string `json:"createdAt"`
+	CbVersion     string `json:"cbVersion"`
+}
+
+// backupFormatVersion is bumped whenever the archive layout changes.
+const backupFormatVersion = 1
+
+// imageConfig is the subset of `docker image inspect` .Config used for backups.
+type imageConfig struct {
+	Env    []string          `json:"Env"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// readImageManifest builds a manifest from the labels and CB_* environment baked into a (committed) booth image.
+func readImageManifest(client docker.DockerClient, flags docker.DockerFlags, image string) (BackupManifest, error) {
+	output, err := client.Output(flags, "image", ilist.NewList(ilist.NewList(
+		"inspect", "--format", "{{json .Config}}", image,
+	)))
+	if err != nil {
+		return BackupManifest{}, fmt.Errorf("image '%s' not found: %w", image, err)
+	}
+
+	return parseImageManifest(image, output)
+}
+
+// parseImageManifest parses the `{{json .Config}}` output of an image inspect.
+func parseImageManifest(image string, output string) (BackupManifest, error) {
+	manifest := BackupManifest{FormatVersion: backupFormatVersion, Image: image}
+	if strings.TrimSpace(output) == "" {
+		return manifest, nil
+	}
+
+	var config imageConfig
+	if err := json.Unmarshal([]byte(output), &config); err != nil {
+		return manifest, fmt.Errorf("failed to parse image config of '%s': %w", image, err)
+	}
+
+	env := map[string]string{}
+	for _, entry := range config.Env {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			env[key] = value
+		}
+	}
+
+	manifest.Project = firstNonEmpty(config.Labels[LabelProject], env["CB_PROJECT_NAME"])
+	manifest.Variant = firstNonEmpty(env["CB_VARIANT_TAG"], config.Labels[LabelVariant])
+	manifest.Version = env["CB_VERSION_TAG"]
+	manifest.CodePath = firstNonEmpty(config.Labels[LabelCodePath], env["CB_CODE_PATH"])
+	manifest.ConfigFile = env["CB_CONFIG_FILE"]
+	return manifest, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
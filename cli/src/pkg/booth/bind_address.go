@@ -0,0 +1,125 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"net"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// defaultBindAddress is the host address the booth ports are published on when none is set:
+// only this machine can reach them.
+const defaultBindAddress = "127.0.0.1"
+
+// bindAddress returns the host address the booth ports are published on (the bind-address of the config).
+func bindAddress(ctx appctx.AppContext) string {
+	if address := strings.TrimSpace(ctx.BindAddress()); address != "" {
+		return address
+	}
+	return defaultBindAddress
+}
+
+// onAllInterfaces tells if an address publishes the ports on all the host interfaces (0.0.0.0 or ::).
+func onAllInterfaces(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// isLocalAddress tells if something can listen on the address (an address of this machine).
+func isLocalAddress(address string) bool {
+	return isPortFree(address, 0)
+}
+
+// bindPortMapping prefixes a docker -p value without a host address (e.g. "8080:80" or "80")
+// with the bind address (e.g. "127.0.0.1:8080:80" or "127.0.0.1::80").
+// A mapping with its own host address is left as is, and so is every mapping when publishing on all interfaces
+// (docker then publishes on IPv4 and IPv6).
+func bindPortMapping(address string, mapping string) string {
+	if onAllInterfaces(address) || strings.HasPrefix(mapping, "[") {
+		return mapping
+	}
+
+	ports, _, _ := strings.Cut(mapping, "/")
+	host := address
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	switch strings.Count(ports, ":") {
+	case 0:
+		return host + "::" + mapping
+	case 1:
+		return host + ":" + mapping
+	}
+	return mapping
+}
+
+// bindPortFlags returns the run-args with the bind address added to their -p and --publish values (see bindPortMapping).
+func bindPortFlags(runArgs ilist.List[ilist.List[string]], address string) *ilist.AppendableList[ilist.List[string]] {
+	result := ilist.NewAppendableList[ilist.List[string]]()
+	for _, argList := range runArgs.Slice() {
+		bound := ilist.NewAppendableList[string]()
+		for j := 0; j < argList.Length(); j++ {
+			flag := argList.At(j)
+			switch {
+			case (flag == "-p" || flag == "--publish") && j+1 < argList.Length():
+				bound.Append(flag, bindPortMapping(address, argList.At(j+1)))
+				j++
+			case strings.HasPrefix(flag, "-p="):
+				bound.Append("-p=" + bindPortMapping(address, strings.TrimPrefix(flag, "-p=")))
+			case strings.HasPrefix(flag, "--publish="):
+				bound.Append("--publish=" + bindPortMapping(address, strings.TrimPrefix(flag, "--publish=")))
+			case strings.HasPrefix(flag, "-p") && len(flag) > 2:
+				bound.Append("-p" + bindPortMapping(address, flag[2:]))
+			default:
+				bound.Append(flag)
+			}
+		}
+		result.Append(bound.ToList())
+	}
+	return result
+}
+
+// reachableHost returns the host name the booth ports are reached at from this machine:
+// localhost for a loopback address or all interfaces, the address itself otherwise.
+func reachableHost(address string) string {
+	if ip := net.ParseIP(address); ip == nil || ip.IsLoopback() || ip.IsUnspecified() {
+		return "localhost"
+	}
+	return address
+}
+
+// listensOn tells if a line of `ss -tln` or `lsof -n -i` shows a socket on the port that conflicts with
+// publishing it on the address: one on the same address or on all interfaces (or any when publishing on all interfaces).
+func listensOn(line string, address string, port string) bool {
+	for _, field := range strings.Fields(line) {
+		host, fieldPort, found := cutLast(field, ":")
+		if !found || fieldPort != port {
+			continue
+		}
+		host = strings.Trim(host, "[]")
+		if zone := strings.Index(host, "%"); zone >= 0 {
+			// e.g. 127.0.0.53%lo
+			host = host[:zone]
+		}
+		if onAllInterfaces(address) || host == "*" || onAllInterfaces(host) || host == address {
+			return true
+		}
+		if ip := net.ParseIP(address); ip != nil && ip.Equal(net.ParseIP(host)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s string, sep string) (string, string, bool) {
+	if index := strings.LastIndex(s, sep); index >= 0 {
+		return s[:index], s[index+len(sep):], true
+	}
+	return s, "", false
+}
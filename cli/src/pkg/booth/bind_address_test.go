@@ -0,0 +1,169 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func TestBindPortMapping(t *testing.T) {
+	tests := []struct {
+		address string
+		mapping string
+		want    string
+	}{
+		{"127.0.0.1", "10000:10000", "127.0.0.1:10000:10000"},
+		{"127.0.0.1", "8080", "127.0.0.1::8080"},
+		{"127.0.0.1", "5353:53/udp", "127.0.0.1:5353:53/udp"},
+		{"127.0.0.1", "192.168.1.10:8080:80", "192.168.1.10:8080:80"},
+		{"127.0.0.1", "[::1]:8080:80", "[::1]:8080:80"},
+		{"::1", "8080:80", "[::1]:8080:80"},
+		{"0.0.0.0", "8080:80", "8080:80"},
+		{"::", "8080", "8080"},
+	}
+	for _, tt := range tests {
+		if got := bindPortMapping(tt.address, tt.mapping); got != tt.want {
+			t.Errorf("bindPortMapping(%q, %q) = %q, want %q", tt.address, tt.mapping, got, tt.want)
+		}
+	}
+}
+
+func TestBindPortFlags(t *testing.T) {
+	runArgs := ilist.NewList(
+		ilist.NewList("-p", "8080:80", "--publish", "9090"),
+		ilist.NewList("-p=3000:3000", "--publish=0.0.0.0:4000:4000", "-p5000:5000", "-e", "A=1"),
+	)
+
+	got := [][]string{}
+	for _, args := range bindPortFlags(runArgs, "127.0.0.1").Slice() {
+		got = append(got, args.Slice())
+	}
+	want := [][]string{
+		{"-p", "127.0.0.1:8080:80", "--publish", "127.0.0.1::9090"},
+		{"-p=127.0.0.1:3000:3000", "--publish=0.0.0.0:4000:4000", "-p127.0.0.1:5000:5000", "-e", "A=1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("bindPortFlags() = %q, want %q", got, want)
+	}
+}
+
+func TestBoothURL(t *testing.T) {
+	tests := map[string]string{
+		"":             "http://localhost:12000",
+		"127.0.0.1":    "http://localhost:12000",
+		"0.0.0.0":      "http://localhost:12000",
+		"::1":          "http://localhost:12000",
+		"192.168.1.10": "http://192.168.1.10:12000",
+		"fd00::10":     "http://[fd00::10]:12000",
+	}
+	for address, want := range tests {
+		builder := &appctx.AppContextBuilder{PortNumber: 12000}
+		builder.Config.BindAddress = address
+		if got := boothURL(builder.Build()); got != want {
+			t.Errorf("boothURL() with bind-address %q = %q, want %q", address, got, want)
+		}
+	}
+}
+
+func TestListensOn(t *testing.T) {
+	tests := []struct {
+		line    string
+		address string
+		want    bool
+	}{
+		{"LISTEN 0 4096 127.0.0.1:10000 0.0.0.0:*", "127.0.0.1", true},
+		{"LISTEN 0 4096 0.0.0.0:10000 0.0.0.0:*", "127.0.0.1", true},
+		{"LISTEN 0 4096 *:10000 *:*", "127.0.0.1", true},
+		{"LISTEN 0 4096 [::]:10000 [::]:*", "127.0.0.1", true},
+		{"LISTEN 0 4096 192.168.1.10:10000 0.0.0.0:*", "127.0.0.1", false},
+		{"LISTEN 0 4096 192.168.1.10:10000 0.0.0.0:*", "0.0.0.0", true},
+		{"LISTEN 0 4096 127.0.0.1:100000 0.0.0.0:*", "127.0.0.1", false},
+		{"node 1234 user 20u IPv4 0x1 0t0 TCP 127.0.0.1:10000 (LISTEN)", "127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		if got := listensOn(tt.line, tt.address, "10000"); got != tt.want {
+			t.Errorf("listensOn(%q, %q) = %t, want %t", tt.line, tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestIsPortFree_BindAddress(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if isPortFree("127.0.0.1", port) {
+		t.Errorf("expected port %d to be taken on 127.0.0.1", port)
+	}
+	if isPortFree("0.0.0.0", port) {
+		t.Errorf("expected port %d to be taken on all interfaces", port)
+	}
+}
+
+func TestPortDetermination_InvalidBindAddress(t *testing.T) {
+	builder := &appctx.AppContextBuilder{Cmds: ilist.NewAppendableList[ilist.List[string]]()}
+	builder.Config.Port = "12000"
+	builder.Config.BindAddress = "my-laptop"
+
+	_, err := PortDetermination(builder.Build())
+
+	var addressErr *InvalidBindAddressError
+	if !errors.As(err, &addressErr) {
+		t.Fatalf("expected InvalidBindAddressError, got %v", err)
+	}
+	want := "Error: --bind-address must be an IP address such as 127.0.0.1 or 0.0.0.0 (got 'my-laptop')."
+	if got := addressErr.CliMessage(); got != want {
+		t.Errorf("CliMessage() = %q, want %q", got, want)
+	}
+
+	// An address of another machine (TEST-NET-1)
+	builder.Config.BindAddress = "192.0.2.1"
+	_, err = PortDetermination(builder.Build())
+	if !errors.As(err, &addressErr) || !addressErr.NotLocal {
+		t.Errorf("expected InvalidBindAddressError (not local), got %v", err)
+	}
+
+	// Only reported in dryrun (the commands are still printed)
+	builder.Config.Dryrun = nillable.NewNillableBool(true)
+	if _, err = PortDetermination(builder.Build()); err != nil {
+		t.Errorf("expected no error for an address of another machine in dryrun, got %v", err)
+	}
+}
+
+func TestPrepareCommonArgs_BindAddress(t *testing.T) {
+	builder := engineTestContext("docker")
+	builder.RunArgs.Append(ilist.NewList("-p", "8080:80"))
+
+	ctx, err := PrepareCommonArgs(builder.Build())
+	if err != nil {
+		t.Fatalf("PrepareCommonArgs() returned error: %v", err)
+	}
+	if !containsArgs(ctx.CommonArgs(), "-p", "127.0.0.1:12000:10000") {
+		t.Errorf("expected the booth port on 127.0.0.1, got %v", ctx.CommonArgs())
+	}
+	if !containsArgs(ctx.RunArgs(), "-p", "127.0.0.1:8080:80") {
+		t.Errorf("expected the run-args port on 127.0.0.1, got %v", ctx.RunArgs())
+	}
+}
+
+// containsArgs tells if one of the argument lists is exactly the given arguments.
+func containsArgs(argLists ilist.List[ilist.List[string]], args ...string) bool {
+	for _, argList := range argLists.Slice() {
+		if reflect.DeepEqual(argList.Slice(), args) {
+			return true
+		}
+	}
+	return false
+}
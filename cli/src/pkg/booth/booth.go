@@ -7,9 +7,12 @@ package booth
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
 	"github.com/nawaman/codingbooth/src/pkg/docker"
@@ -80,17 +83,11 @@ func (booth *Booth) runAsCommand() error {
 	)
 
 	// Execute the docker run command
-	err := docker.Docker(flags, "run", args)
+	err := booth.ctx.Docker().Run(flags, args)
 
 	// Cleanup DinD resources if enabled
 	if booth.ctx.Dind() {
-		flags.Silent = true
-		dindName := getDindName(booth.ctx)
-		dindNet := getDindNet(booth.ctx)
-		_ = docker.Docker(flags, "stop", ilist.NewList(ilist.NewList(dindName)))
-		if booth.ctx.CreatedDindNet() {
-			_ = docker.Docker(flags, "network", ilist.NewList(ilist.NewList("rm", dindNet)))
-		}
+		booth.cleanupDind(flags)
 	}
 
 	// In command mode, forward exit codes silently (no error message)
@@ -125,7 +122,11 @@ func (booth *Booth) runAsDaemon() error {
 		fmt.Println("👉 Stop with Ctrl+C. The container will be removed (--rm) when stop.")
 	}
 
-	fmt.Printf("👉 Visit 'http://localhost:%d'\n", booth.ctx.PortNumber()) // HostPort
+	if !waitsForUI(booth.ctx) {
+		// Else shown once the UI answers (see WaitReady)
+		fmt.Printf("👉 Visit '%s'\n", boothURL(booth.ctx))
+	}
+	fmt.Printf("👉 To open a shell in this booth: %s exec %s\n", booth.ctx.ScriptName(), booth.ctx.Name())
 	fmt.Printf("👉 To open an interactive shell instead: %s -- bash\n", booth.ctx.ScriptName())
 	fmt.Println("👉 To stop the running container:")
 	fmt.Println()
@@ -158,7 +159,7 @@ func (booth *Booth) runAsDaemon() error {
 	args = args.ExtendByLists(ilist.NewListFromSlice(extraArgs))
 
 	// Execute the docker run command
-	err := docker.Docker(flags, "run", args)
+	err := booth.ctx.Docker().Run(flags, args)
 
 	// If DinD is enabled in daemon mode, inform user how to stop it
 	if booth.ctx.Dind() {
@@ -208,22 +209,28 @@ func (booth *Booth) runAsForeground() error {
 	))
 
 	// Execute the docker run command
-	err := docker.Docker(flags, "run", args)
+	err := booth.ctx.Docker().Run(flags, args)
 
 	// Cleanup DinD resources if enabled
 	if booth.ctx.Dind() {
-		dindName := getDindName(booth.ctx)
-		dindNet := getDindNet(booth.ctx)
-		flags.Silent = true
-		_ = docker.Docker(flags, "stop", ilist.NewList(ilist.NewList(dindName)))
-		if booth.ctx.CreatedDindNet() {
-			_ = docker.Docker(flags, "network", ilist.NewList(ilist.NewList("rm", dindNet)))
-		}
+		booth.cleanupDind(flags)
 	}
 
 	return err
 }
 
+// cleanupDind stops the DinD sidecar and removes its network once the booth exits.
+// With --keep-alive both are kept (sidecar stopped, not removed) so 'start' can resume them.
+func (booth *Booth) cleanupDind(flags docker.DockerFlags) {
+	flags.Silent = true
+	dindName := getDindName(booth.ctx)
+	dindNet := getDindNet(booth.ctx)
+	_ = booth.ctx.Docker().Stop(flags, ilist.NewList(ilist.NewList(dindName)))
+	if booth.ctx.CreatedDindNet() && !booth.ctx.KeepAlive() {
+		_, _ = booth.ctx.Docker().Network(flags, ilist.NewList(ilist.NewList("rm", dindNet)))
+	}
+}
+
 func prepareTtyArgs() []string {
 	if term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) {
 		return []string{"-it"}
@@ -246,8 +253,11 @@ func getDindNet(ctx appctx.AppContext) string {
 	return ctx.Name() + "-" + strconv.Itoa(ctx.PortNumber()) + "-net"
 }
 
+// keepIDUser is the container UID/GID the host user is mapped to with --userns=keep-id (booth-entry's default HOST_UID/HOST_GID).
+const keepIDUser = "1000"
+
 // PrepareCommonArgs prepares common Docker run arguments and returns updated AppContext.
-func PrepareCommonArgs(ctx appctx.AppContext) appctx.AppContext {
+func PrepareCommonArgs(ctx appctx.AppContext) (appctx.AppContext, error) {
 	builder := ctx.ToBuilder()
 
 	containerName := ctx.Name()
@@ -256,16 +266,35 @@ func PrepareCommonArgs(ctx appctx.AppContext) appctx.AppContext {
 	}
 
 	builder.CommonArgs.Append(ilist.NewList[string]("--name", containerName))
-	builder.CommonArgs.Append(ilist.NewList[string]("-e", "HOST_UID="+ctx.HostUID()))
-	builder.CommonArgs.Append(ilist.NewList[string]("-e", "HOST_GID="+ctx.HostGID()))
+
+	// With keep-id (podman), the host user is mapped to the coder UID/GID so booth-entry has nothing to remap.
+	engine := docker.EngineOf(ctx.Engine())
+	if engine.KeepID {
+		builder.CommonArgs.Append(ilist.NewList[string]("--userns=keep-id:uid="+keepIDUser+",gid="+keepIDUser, "--user=root"))
+		builder.CommonArgs.Append(ilist.NewList[string]("-e", "HOST_UID="+keepIDUser))
+		builder.CommonArgs.Append(ilist.NewList[string]("-e", "HOST_GID="+keepIDUser))
+	} else {
+		builder.CommonArgs.Append(ilist.NewList[string]("-e", "HOST_UID="+ctx.HostUID()))
+		builder.CommonArgs.Append(ilist.NewList[string]("-e", "HOST_GID="+ctx.HostGID()))
+	}
 	builder.CommonArgs.Append(ilist.NewList[string]("-v", ctx.Code()+":/home/coder/code"))
 	builder.CommonArgs.Append(ilist.NewList[string]("-w", "/home/coder/code"))
 
 	// Skip port mapping when using DinD (port is exposed on DinD container instead)
+	// The ports are published on the bind address only (127.0.0.1 by default), including the ones of the run-args
 	if !ctx.Dind() {
-		builder.CommonArgs.Append(ilist.NewList[string]("-p", fmt.Sprintf("%d:10000", ctx.PortNumber())))
+		address := bindAddress(ctx)
+		builder.CommonArgs.Append(ilist.NewList[string]("-p", bindPortMapping(address, fmt.Sprintf("%d:10000", ctx.PortNumber()))))
+		builder.RunArgs = bindPortFlags(ctx.RunArgs(), address)
 	}
 
+	// Structured config tables ([[mounts]], [env] and [ports])
+	hostHome, _ := os.UserHomeDir()
+	builder.CommonArgs.Append(configTableArgs(ctx, hostHome)...)
+
+	// Labels (used by list/start/stop to find booth containers)
+	builder.CommonArgs.Append(labelArgs(ctx, time.Now())...)
+
 	// Metadata
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_SETUPS="+ctx.SetupsDir()))
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_CONTAINER_NAME="+ctx.Name()))
@@ -293,20 +322,29 @@ func PrepareCommonArgs(ctx appctx.AppContext) appctx.AppContext {
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_PROJECT_NAME="+ctx.ProjectName()))
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_TIMEZONE="+ctx.Timezone()))
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_PORT="+ctx.Port()))
+	namedPorts := ctx.NamedPorts()
+	for _, name := range slices.Sorted(maps.Keys(namedPorts)) {
+		builder.CommonArgs.Append(ilist.NewList[string]("-e", namedPortEnv(name)+"="+strconv.Itoa(namedPorts[name])))
+	}
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_ENV_FILE="+ctx.EnvFile()))
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_HOST_UID="+ctx.HostUID()))
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_HOST_GID="+ctx.HostGID()))
 
+	// Selected config profile
+	if ctx.Profile() != "" {
+		builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_PROFILE="+ctx.Profile()))
+	}
+
 	// Custom startup script
 	if ctx.Startup() != "" {
 		builder.CommonArgs.Append(ilist.NewList[string]("-e", "CB_STARTUP="+ctx.Startup()))
 	}
 
 	if !ctx.Pull() {
-		builder.CommonArgs.Append(ilist.NewList[string]("--pull=never"))
+		builder.CommonArgs.Append(ilist.NewListFromSlice(engine.PullNever))
 	}
 
-	return builder.Build()
+	return builder.Build(), nil
 }
 
 func flattenArgs(argsList ilist.List[ilist.List[string]]) []string {
@@ -0,0 +1,278 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// Entry names inside a backup archive.
+const (
+	backupManifestEntry = "manifest.json"
+	backupConfigEntry   = "config.toml"
+	backupImageEntry    = "image.tar"
+)
+
+// DefaultCommitTag returns the image tag used by `commit` when none is given.
+func DefaultCommitTag(boothName string, now time.Time) string {
+	return fmt.Sprintf("codingbooth-local:%s-%s", boothName, now.UTC().Format("20060102-150405"))
+}
+
+// DefaultBackupFile returns the archive path used by `backup` when no output is given.
+func DefaultBackupFile(image string) string {
+	replacer := strings.NewReplacer("/", "_", ":", "-", "@", "-")
+	return replacer.Replace(image) + ".tar.gz"
+}
+
+// CommitBooth saves the current state of a booth container (running or stopped) as an image.
+// The container labels and CB_* environment are carried over, so the image can later be run with --image.
+func CommitBooth(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, tag string, message string) error {
+	commitArgs := ilist.NewList[string]()
+	if message != "" {
+		commitArgs = ilist.NewList("--message", message)
+	}
+
+	err := client.Command(flags, "commit", ilist.NewList(commitArgs, ilist.NewList(target.Info.Name, tag)))
+	if err != nil {
+		return fmt.Errorf("failed to commit booth '%s': %w", target.Info.Name, err)
+	}
+	return nil
+}
+
+// BackupImage writes a booth image to a tar.gz archive holding a manifest, the booth config.toml (if found)
+// and the `docker save` output.
+// The config file is configFile, or else the one recorded in the image (CB_CONFIG_FILE or <code>/.booth/config.toml).
+func BackupImage(client docker.DockerClient, flags docker.DockerFlags, image string, output string, configFile string, cbVersion string) (BackupManifest, error) {
+	// Lookups must always run, even in dryrun mode
+	lookupFlags := docker.DockerFlags{Verbose: flags.Verbose, Silent: true}
+	manifest, err := readImageManifest(client, lookupFlags, image)
+	if err != nil {
+		return manifest, err
+	}
+	manifest.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	manifest.CbVersion = cbVersion
+
+	configContent, configPath := readBackupConfig(manifest, configFile)
+	if configPath != "" {
+		manifest.ConfigFile = configPath
+		manifest.HasConfig = true
+	}
+
+	tempDir, err := os.MkdirTemp("", "cb-backup-")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	imageTar := filepath.Join(tempDir, backupImageEntry)
+	if err := client.Command(flags, "save", ilist.NewList(ilist.NewList("--output", imageTar, image))); err != nil {
+		return manifest, fmt.Errorf("failed to save image '%s': %w", image, err)
+	}
+	if flags.Dryrun {
+		return manifest, nil
+	}
+
+	if err := writeBackupArchive(output, manifest, configContent, imageTar); err != nil {
+		return manifest, fmt.Errorf("failed to write backup '%s': %w", output, err)
+	}
+	return manifest, nil
+}
+
+// readBackupConfig returns the content and path of the config.toml to store in the backup, if any.
+func readBackupConfig(manifest BackupManifest, configFile string) ([]byte, string) {
+	candidates := []string{configFile}
+	if configFile == "" {
+		candidates = []string{manifest.ConfigFile}
+		if manifest.CodePath != "" {
+			candidates = append(candidates, filepath.Join(manifest.CodePath, ".booth", "config.toml"))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		content, err := os.ReadFile(candidate)
+		if err == nil {
+			return content, candidate
+		}
+	}
+	return nil, ""
+}
+
+// writeBackupArchive writes the manifest, config and image tar into a gzipped tar at output.
+func writeBackupArchive(output string, manifest BackupManifest, configContent []byte, imageTar string) (err error) {
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(output)
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifestJson, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := writeTarEntry(tarWriter, backupManifestEntry, int64(len(manifestJson)), bytes.NewReader(manifestJson)); err != nil {
+		return err
+	}
+	if manifest.HasConfig {
+		if err := writeTarEntry(tarWriter, backupConfigEntry, int64(len(configContent)), bytes.NewReader(configContent)); err != nil {
+			return err
+		}
+	}
+
+	image, err := os.Open(imageTar)
+	if err != nil {
+		return err
+	}
+	defer image.Close()
+	imageInfo, err := image.Stat()
+	if err != nil {
+		return err
+	}
+	if err := writeTarEntry(tarWriter, backupImageEntry, imageInfo.Size(), image); err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func writeTarEntry(tarWriter *tar.Writer, name string, size int64, content io.Reader) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tarWriter, content)
+	return err
+}
+
+// RestoreBackup loads the image of a backup archive into docker and returns its manifest.
+// When configOutput is not empty, the stored config.toml is written there.
+func RestoreBackup(client docker.DockerClient, flags docker.DockerFlags, input string, configOutput string) (BackupManifest, error) {
+	tempDir, err := os.MkdirTemp("", "cb-restore-")
+	if err != nil {
+		return BackupManifest{}, fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	imageTar := filepath.Join(tempDir, backupImageEntry)
+	manifest, configContent, err := readBackupArchive(input, imageTar)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read backup '%s': %w", input, err)
+	}
+
+	if err := client.Command(flags, "load", ilist.NewList(ilist.NewList("--input", imageTar))); err != nil {
+		return manifest, fmt.Errorf("failed to load image '%s': %w", manifest.Image, err)
+	}
+
+	if configOutput != "" && !flags.Dryrun {
+		if !manifest.HasConfig {
+			return manifest, fmt.Errorf("backup '%s' does not contain a config.toml", input)
+		}
+		if err := os.MkdirAll(filepath.Dir(configOutput), 0755); err != nil {
+			return manifest, err
+		}
+		if err := os.WriteFile(configOutput, configContent, 0644); err != nil {
+			return manifest, fmt.Errorf("failed to write config '%s': %w", configOutput, err)
+		}
+	}
+	return manifest, nil
+}
+
+// readBackupArchive reads the manifest and config.toml of a backup and extracts its image tar to imageTar.
+func readBackupArchive(input string, imageTar string) (BackupManifest, []byte, error) {
+	var manifest BackupManifest
+	var configContent []byte
+
+	file, err := os.Open(input)
+	if err != nil {
+		return manifest, nil, err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("not a gzip archive: %w", err)
+	}
+	defer gzipReader.Close()
+
+	hasManifest := false
+	hasImage := false
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return manifest, nil, err
+		}
+
+		switch header.Name {
+		case backupManifestEntry:
+			if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+				return manifest, nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			hasManifest = true
+		case backupConfigEntry:
+			if configContent, err = io.ReadAll(tarReader); err != nil {
+				return manifest, nil, err
+			}
+		case backupImageEntry:
+			if err := extractTarEntry(tarReader, imageTar); err != nil {
+				return manifest, nil, err
+			}
+			hasImage = true
+		}
+	}
+
+	if !hasManifest || !hasImage {
+		return manifest, nil, fmt.Errorf("not a CodingBooth backup (missing %s or %s)", backupManifestEntry, backupImageEntry)
+	}
+	if manifest.FormatVersion > backupFormatVersion {
+		return manifest, nil, fmt.Errorf("backup format version %d is newer than supported (%d)", manifest.FormatVersion, backupFormatVersion)
+	}
+	return manifest, configContent, nil
+}
+
+func extractTarEntry(tarReader *tar.Reader, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, tarReader); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
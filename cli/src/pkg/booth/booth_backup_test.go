@@ -0,0 +1,114 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseImageManifest(t *testing.T) {
+	output := `{"Env":["PATH=/usr/bin","CB_VARIANT_TAG=codeserver","CB_VERSION_TAG=0.13.0",` +
+		`"CB_CONFIG_FILE=/work/app/.booth/config.toml"],` +
+		`"Labels":{"cb.managed":"true","cb.project":"app","cb.code-path":"/work/app","cb.variant":"base"}}`
+
+	manifest, err := parseImageManifest("mywork:v1", output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if manifest.Image != "mywork:v1" || manifest.FormatVersion != backupFormatVersion {
+		t.Errorf("unexpected image/format: %+v", manifest)
+	}
+	if manifest.Project != "app" {
+		t.Errorf("Project = %q, want app", manifest.Project)
+	}
+	// The variant tag from the environment wins over the label
+	if manifest.Variant != "codeserver" {
+		t.Errorf("Variant = %q, want codeserver", manifest.Variant)
+	}
+	if manifest.Version != "0.13.0" {
+		t.Errorf("Version = %q, want 0.13.0", manifest.Version)
+	}
+	if manifest.CodePath != "/work/app" || manifest.ConfigFile != "/work/app/.booth/config.toml" {
+		t.Errorf("unexpected paths: %+v", manifest)
+	}
+}
+
+func TestParseImageManifest_NotBoothImage(t *testing.T) {
+	manifest, err := parseImageManifest("ubuntu:24.04", `{"Env":["PATH=/usr/bin"],"Labels":null}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.Variant != "" || manifest.Project != "" {
+		t.Errorf("expected empty booth info, got %+v", manifest)
+	}
+
+	if _, err := parseImageManifest("broken", "not json"); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestDefaultCommitTag(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := DefaultCommitTag("my-project", now); got != "codingbooth-local:my-project-20260304-050607" {
+		t.Errorf("DefaultCommitTag = %q", got)
+	}
+}
+
+func TestDefaultBackupFile(t *testing.T) {
+	if got := DefaultBackupFile("ghcr.io/team/app:v1"); got != "ghcr.io_team_app-v1.tar.gz" {
+		t.Errorf("DefaultBackupFile = %q", got)
+	}
+}
+
+func TestBackupArchive_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	imageTar := filepath.Join(dir, "saved.tar")
+	if err := os.WriteFile(imageTar, []byte("image layers"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := BackupManifest{
+		FormatVersion: backupFormatVersion,
+		Image:         "mywork:v1",
+		Variant:       "codeserver",
+		Version:       "0.13.0",
+		HasConfig:     true,
+	}
+	archive := filepath.Join(dir, "backup.tar.gz")
+	if err := writeBackupArchive(archive, manifest, []byte("variant = \"codeserver\"\n"), imageTar); err != nil {
+		t.Fatalf("writeBackupArchive failed: %v", err)
+	}
+
+	extracted := filepath.Join(dir, "extracted.tar")
+	readManifest, config, err := readBackupArchive(archive, extracted)
+	if err != nil {
+		t.Fatalf("readBackupArchive failed: %v", err)
+	}
+	if readManifest != manifest {
+		t.Errorf("manifest = %+v, want %+v", readManifest, manifest)
+	}
+	if string(config) != "variant = \"codeserver\"\n" {
+		t.Errorf("config = %q", config)
+	}
+	content, _ := os.ReadFile(extracted)
+	if string(content) != "image layers" {
+		t.Errorf("image content = %q", content)
+	}
+}
+
+func TestReadBackupArchive_NotBackup(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.tar.gz")
+	if err := os.WriteFile(plain, []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readBackupArchive(plain, filepath.Join(dir, "image.tar")); err == nil {
+		t.Error("expected an error for a non-backup file")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func engineTestContext(engine string) *appctx.AppContextBuilder {
+	builder := &appctx.AppContextBuilder{
+		CommonArgs: ilist.NewAppendableList[ilist.List[string]](),
+		BuildArgs:  ilist.NewAppendableList[ilist.List[string]](),
+		RunArgs:    ilist.NewAppendableList[ilist.List[string]](),
+		Cmds:       ilist.NewAppendableList[ilist.List[string]](),
+		PortNumber: 12000,
+	}
+	builder.Config.Code = nillable.NewNillableString("/home/user/my-project")
+	builder.Config.Name = "my-project"
+	builder.Config.HostUID = "1234"
+	builder.Config.HostGID = "5678"
+	builder.Config.Engine = engine
+	return builder
+}
+
+func TestPrepareCommonArgs_Engines(t *testing.T) {
+	tests := []struct {
+		engine   string
+		expected []string
+		absent   []string
+	}{
+		{
+			engine:   "docker",
+			expected: []string{"-e HOST_UID=1234 -e HOST_GID=5678", "--pull=never"},
+			absent:   []string{"--userns"},
+		},
+		{
+			engine:   "podman",
+			expected: []string{"--userns=keep-id:uid=1000,gid=1000 --user=root", "-e HOST_UID=1000 -e HOST_GID=1000", "-e CB_HOST_UID=1234", "--pull=never"},
+		},
+		{
+			engine:   "nerdctl",
+			expected: []string{"-e HOST_UID=1234 -e HOST_GID=5678", "--pull never"},
+			absent:   []string{"--userns", "--pull=never"},
+		},
+	}
+	for _, tt := range tests {
+		ctx, err := PrepareCommonArgs(engineTestContext(tt.engine).Build())
+		if err != nil {
+			t.Fatalf("%s: PrepareCommonArgs() returned error: %v", tt.engine, err)
+		}
+		args := strings.Join(flattenArgs(ctx.CommonArgs()), " ")
+		for _, arg := range tt.expected {
+			if !strings.Contains(args, arg) {
+				t.Errorf("%s: expected %q in common args, got: %s", tt.engine, arg, args)
+			}
+		}
+		for _, arg := range tt.absent {
+			if strings.Contains(args, arg) {
+				t.Errorf("%s: unexpected %q in common args, got: %s", tt.engine, arg, args)
+			}
+		}
+	}
+}
+
+func TestStartDindSidecar_Podman(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		if call.Subcommand == "info" {
+			return "false\n", nil
+		}
+		return "", nil
+	}
+	builder := engineTestContext("podman")
+	builder.Docker = client
+	// The cache volume is covered by TestDindCacheArgs
+	builder.Config.DindCache = "none"
+
+	if err := startDindSidecar(builder.Build(), "my-project-12000-dind", "my-project-12000-net", 12000, nil); err != nil {
+		t.Fatalf("startDindSidecar() returned error: %v", err)
+	}
+
+	commands := client.Commands()
+	if len(commands) != 3 || commands[1] != "docker info --format {{.Host.ServiceIsRemote}}" {
+		t.Fatalf("Commands() = %q", commands)
+	}
+	run := commands[2]
+	if !strings.HasSuffix(run, "docker.io/library/docker:dind") {
+		t.Errorf("expected a fully qualified dind image: %s", run)
+	}
+	if strings.Contains(run, "--cgroupns=host") || strings.Contains(run, "/sys/fs/cgroup") {
+		t.Errorf("rootless podman cannot share the host cgroups: %s", run)
+	}
+}
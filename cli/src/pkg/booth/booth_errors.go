@@ -0,0 +1,288 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"time"
+)
+
+// CliMessageError is implemented by stage errors that know the exact text the CLI prints for them (on stderr).
+type CliMessageError interface {
+	error
+	CliMessage() string
+}
+
+// UnknownVariantError is returned by ValidateVariant for a variant that is neither known nor an alias.
+type UnknownVariantError struct {
+	Variant string
+}
+
+func (e *UnknownVariantError) Error() string {
+	return fmt.Sprintf("unknown variant '%s'", e.Variant)
+}
+
+func (e *UnknownVariantError) CliMessage() string {
+	return fmt.Sprintf("Error: unknown --variant '%s' (valid: base|notebook|codeserver|desktop-xfce|desktop-kde;\n", e.Variant) +
+		"       aliases: console|ide|desktop|xfce|kde)"
+}
+
+// DockerfileNotFileError is returned by EnsureDockerImage when the Dockerfile path is not a file.
+type DockerfileNotFileError struct {
+	Path string
+}
+
+func (e *DockerfileNotFileError) Error() string {
+	return fmt.Sprintf("dockerfile '%s' is not a file", e.Path)
+}
+
+func (e *DockerfileNotFileError) CliMessage() string {
+	return fmt.Sprintf("DOCKER_FILE (%s) is not a file.", e.Path)
+}
+
+// ImageBuildError is returned by EnsureDockerImage when the local image cannot be built.
+type ImageBuildError struct {
+	Image string
+	Err   error
+}
+
+func (e *ImageBuildError) Error() string {
+	return fmt.Sprintf("failed to build image '%s': %v", e.Image, e.Err)
+}
+
+func (e *ImageBuildError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ImageBuildError) CliMessage() string {
+	return "Error: failed to build image"
+}
+
+// ImagePullError is returned by EnsureDockerImage when the image cannot be pulled.
+type ImagePullError struct {
+	Image string
+	Err   error
+}
+
+func (e *ImagePullError) Error() string {
+	return fmt.Sprintf("failed to pull image '%s': %v", e.Image, e.Err)
+}
+
+func (e *ImagePullError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ImagePullError) CliMessage() string {
+	return fmt.Sprintf("Error: failed to pull '%s'", e.Image)
+}
+
+// ImageNotFoundError is returned by EnsureDockerImage when the image is still not available locally.
+type ImageNotFoundError struct {
+	Image string
+}
+
+func (e *ImageNotFoundError) Error() string {
+	return fmt.Sprintf("image '%s' not available locally", e.Image)
+}
+
+func (e *ImageNotFoundError) CliMessage() string {
+	return fmt.Sprintf("Error: image '%s' not available locally.\n", e.Image) +
+		"       Use '--pull' if you want to force pulling it."
+}
+
+// EnvFileMissingError is returned by ApplyEnvFile when the given env file does not exist.
+type EnvFileMissingError struct {
+	Path string
+}
+
+func (e *EnvFileMissingError) Error() string {
+	return fmt.Sprintf("env-file '%s' does not exist", e.Path)
+}
+
+func (e *EnvFileMissingError) CliMessage() string {
+	return fmt.Sprintf("Error: env-file must be an existing file: %s", e.Path)
+}
+
+// InvalidPortError is returned by PortDetermination for a port that is not a number in 1-65535.
+type InvalidPortError struct {
+	Value      string
+	OutOfRange bool
+}
+
+func (e *InvalidPortError) Error() string {
+	return fmt.Sprintf("invalid port '%s'", e.Value)
+}
+
+func (e *InvalidPortError) CliMessage() string {
+	if e.OutOfRange {
+		return fmt.Sprintf("Error: --port must be between 1 and 65535 (got '%s').", e.Value)
+	}
+	return fmt.Sprintf("Error: --port must be a number (got '%s').", e.Value)
+}
+
+// InvalidBindAddressError is returned by PortDetermination for a bind-address that is not an IP address
+// (or not an address of this machine).
+type InvalidBindAddressError struct {
+	Value    string
+	NotLocal bool
+}
+
+func (e *InvalidBindAddressError) Error() string {
+	if e.NotLocal {
+		return fmt.Sprintf("bind address '%s' is not an address of this machine", e.Value)
+	}
+	return fmt.Sprintf("invalid bind address '%s'", e.Value)
+}
+
+func (e *InvalidBindAddressError) CliMessage() string {
+	if e.NotLocal {
+		return fmt.Sprintf("Error: --bind-address must be an address of this machine (got '%s').", e.Value)
+	}
+	return fmt.Sprintf("Error: --bind-address must be an IP address such as 127.0.0.1 or 0.0.0.0 (got '%s').", e.Value)
+}
+
+// PortUnavailableError is returned by PortDetermination when no free RANDOM or NEXT port can be found.
+// Name is the name of the [ports] entry ("" for the booth port).
+type PortUnavailableError struct {
+	Mode string
+	Name string
+}
+
+func (e *PortUnavailableError) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("no free %s host port for ports.%s", e.Mode, e.Name)
+	}
+	return fmt.Sprintf("no free %s port above 10000", e.Mode)
+}
+
+func (e *PortUnavailableError) CliMessage() string {
+	if e.Name != "" {
+		return fmt.Sprintf("Error: unable to find a free %s host port for ports.%s.", e.Mode, e.Name)
+	}
+	if e.Mode == "NEXT" {
+		return "Error: unable to find the NEXT free port above 10000."
+	}
+	return fmt.Sprintf("Error: unable to find a free %s port above 10000.", e.Mode)
+}
+
+// DuplicateHostPortError is returned by PortDetermination when a [ports] entry is published on a host port
+// that is already taken by the booth port or another entry (UsedBy).
+type DuplicateHostPortError struct {
+	Name   string
+	Port   int
+	UsedBy string
+}
+
+func (e *DuplicateHostPortError) Error() string {
+	return fmt.Sprintf("host port %d of ports.%s is already used by %s", e.Port, e.Name, e.UsedBy)
+}
+
+func (e *DuplicateHostPortError) CliMessage() string {
+	return fmt.Sprintf("Error: host port %d of ports.%s is already used by %s (use another port, NEXT or RANDOM).", e.Port, e.Name, e.UsedBy)
+}
+
+// DuplicatePortEnvError is returned by PortDetermination when two [ports] entries (Name and Other)
+// are exported as the same environment variable (e.g. dev-server and dev_server as CB_PORT_DEV_SERVER).
+type DuplicatePortEnvError struct {
+	Name  string
+	Other string
+	Env   string
+}
+
+func (e *DuplicatePortEnvError) Error() string {
+	return fmt.Sprintf("ports.%s and ports.%s are both exported as %s", e.Other, e.Name, e.Env)
+}
+
+func (e *DuplicatePortEnvError) CliMessage() string {
+	return fmt.Sprintf("Error: ports.%s and ports.%s are both exported as %s (rename one of them).", e.Other, e.Name, e.Env)
+}
+
+// HostPortTakenError is returned by the stages that bind the host ports (setup-dind and run) when docker failed
+// because a generated (NEXT or RANDOM) host port was taken after it was determined; BoothRunner then determines the ports again.
+type HostPortTakenError struct {
+	Port int
+	Err  error
+}
+
+func (e *HostPortTakenError) Error() string {
+	return fmt.Sprintf("host port %d was taken by another process: %v", e.Port, e.Err)
+}
+
+func (e *HostPortTakenError) Unwrap() error {
+	return e.Err
+}
+
+func (e *HostPortTakenError) CliMessage() string {
+	return fmt.Sprintf("Error: host port %d was taken by another process while the booth was starting (tried %d times).", e.Port, maxPortAttempts)
+}
+
+// ServiceNotReadyError is returned by WaitReady when the booth UI does not answer in time (the booth keeps running).
+type ServiceNotReadyError struct {
+	Service string
+	URL     string
+	Timeout time.Duration
+}
+
+func (e *ServiceNotReadyError) Error() string {
+	return fmt.Sprintf("%s did not answer at %s within %s", e.Service, e.URL, e.Timeout)
+}
+
+func (e *ServiceNotReadyError) CliMessage() string {
+	return fmt.Sprintf("Error: %s did not answer at %s within %s (the booth is still running; check its logs).", e.Service, e.URL, e.Timeout)
+}
+
+// DindStartError is returned by SetupDind when the DinD sidecar fails to start.
+// Diagnostic explains a detected port conflict (empty if none was found).
+type DindStartError struct {
+	Err        error
+	Diagnostic string
+}
+
+func (e *DindStartError) Error() string {
+	return fmt.Sprintf("failed to start DinD sidecar: %v", e.Err)
+}
+
+func (e *DindStartError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DindStartError) CliMessage() string {
+	message := "❌ Failed to start DinD sidecar.\n\n"
+	if e.Diagnostic != "" {
+		return message + "   " + e.Diagnostic
+	}
+	return message +
+		fmt.Sprintf("   Error: %v\n", e.Err) +
+		"   Check if any port is already in use.\n" +
+		"   Use 'lsof -i :<port>' or 'ss -tlnp | grep <port>' to find the process."
+}
+
+// StageError wraps the error of a failed BoothRunner stage with the stage name.
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %s: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// HookError is returned when a `before-<stage>`/`after-<stage>` hook command fails.
+type HookError struct {
+	Hook string
+	Err  error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("hook %s failed: %v", e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,45 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// Booth user and working directory used by `exec` (matching booth-entry and the -w of `run`).
+const (
+	execUser    = "coder"
+	execWorkdir = "/home/coder/code"
+)
+
+// ExecInBooth runs commands (or an interactive login shell when there are none) inside a running booth
+// as the coder user, so the environment from /etc/profile.d/99z-cb--profile.sh is loaded.
+// A non-zero exit of the command is returned as SilentExitError.
+func ExecInBooth(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, cmds []string) error {
+	err := client.Command(flags, "exec", execArgs(target, cmds))
+
+	if exitErr, ok := err.(*docker.DockerExitError); ok {
+		return &SilentExitError{ExitCode: exitErr.ExitCode}
+	}
+	return err
+}
+
+// execArgs returns the `docker exec` arguments; the TTY flags (-i/-t) are added by the docker package.
+func execArgs(target BoothTarget, cmds []string) ilist.List[ilist.List[string]] {
+	shell := ilist.NewList("bash", "-l")
+	if len(cmds) > 0 {
+		shell = ilist.NewList("bash", "-lc", strings.Join(cmds, " "))
+	}
+
+	return ilist.NewList(
+		ilist.NewList("--user", execUser),
+		ilist.NewList("--workdir", execWorkdir),
+		ilist.NewList(target.Info.Name),
+		shell,
+	)
+}
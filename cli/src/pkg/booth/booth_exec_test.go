@@ -0,0 +1,40 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExecArgs(t *testing.T) {
+	target := BoothTarget{Info: BoothInfo{Name: "my-project"}}
+
+	tests := []struct {
+		name string
+		cmds []string
+		want []string
+	}{
+		{
+			name: "interactive login shell",
+			cmds: nil,
+			want: []string{"--user", "coder", "--workdir", "/home/coder/code", "my-project", "bash", "-l"},
+		},
+		{
+			name: "command joined like command mode",
+			cmds: []string{"ls", "-la"},
+			want: []string{"--user", "coder", "--workdir", "/home/coder/code", "my-project", "bash", "-lc", "ls -la"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := flattenArgs(execArgs(target, test.cmds))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("execArgs() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
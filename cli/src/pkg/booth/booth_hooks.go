@@ -0,0 +1,97 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+// stageHooks holds the host-side commands to run before and after each stage (keyed by stageKey).
+type stageHooks struct {
+	before map[string]string
+	after  map[string]string
+}
+
+// resolveStageHooks matches the `before-<stage>`/`after-<stage>` entries of the [hooks] config table to the stages.
+// It returns an error for a hook that does not name a known stage.
+func resolveStageHooks(hooks map[string]string, registry *StageRegistry) (stageHooks, error) {
+	resolved := stageHooks{before: map[string]string{}, after: map[string]string{}}
+
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		var stageName string
+		var target map[string]string
+		switch {
+		case strings.HasPrefix(lower, "before-"):
+			stageName, target = name[len("before-"):], resolved.before
+		case strings.HasPrefix(lower, "after-"):
+			stageName, target = name[len("after-"):], resolved.after
+		default:
+			return resolved, fmt.Errorf("invalid hook '%s': must be 'before-<stage>' or 'after-<stage>' (stages: %s)", name, stageNames(registry))
+		}
+
+		if _, found := registry.Find(stageName); !found {
+			return resolved, fmt.Errorf("invalid hook '%s': unknown stage '%s' (stages: %s)", name, stageName, stageNames(registry))
+		}
+		target[stageKey(stageName)] = hooks[name]
+	}
+	return resolved, nil
+}
+
+func stageNames(registry *StageRegistry) string {
+	names := []string{}
+	for _, stage := range registry.Stages() {
+		names = append(names, stage.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+// runHook runs a hook command with `sh -c` on the host, in the code folder.
+// The hook gets CB_HOOK_* variables (not CB_* so a nested coding-booth call does not pick them up as configuration).
+// In dryrun mode the command is only printed.
+func runHook(ctx appctx.AppContext, hook string, stage string, command string) error {
+	if command == "" {
+		return nil
+	}
+	if ctx.Dryrun() || ctx.Verbose() {
+		fmt.Printf("🪝 Hook %s: %s\n", hook, command)
+	}
+	if ctx.Dryrun() {
+		return nil
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = ctx.Code()
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"CB_HOOK_NAME="+hook,
+		"CB_HOOK_STAGE="+stage,
+		"CB_HOOK_BOOTH="+ctx.Name(),
+		"CB_HOOK_PROJECT="+ctx.ProjectName(),
+		"CB_HOOK_CODE="+ctx.Code(),
+		"CB_HOOK_IMAGE="+ctx.Image(),
+		"CB_HOOK_VARIANT="+ctx.Variant(),
+		"CB_HOOK_PORT="+strconv.Itoa(ctx.PortNumber()),
+	)
+	if err := cmd.Run(); err != nil {
+		return &HookError{Hook: hook, Err: err}
+	}
+	return nil
+}
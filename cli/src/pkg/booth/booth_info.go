@@ -0,0 +1,156 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// BoothInfo describes a booth container as found through its cb.* labels.
+type BoothInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Status    string `json:"status"`
+	Project   string `json:"project"`
+	Variant   string `json:"variant"`
+	Port      string `json:"port"`
+	CodePath  string `json:"codePath"`
+	CreatedAt string `json:"createdAt"`
+	Version   string `json:"version"`
+}
+
+// Booth list filters.
+const (
+	ListAll     = "ALL"
+	ListRunning = "RUNNING"
+	ListStopped = "STOPPED"
+)
+
+// boothPsFormat is the `docker ps --format` template matching parseBoothInfoLine.
+var boothPsFormat = strings.Join([]string{
+	"{{.ID}}",
+	"{{.Names}}",
+	"{{.State}}",
+	`{{.Label "` + LabelProject + `"}}`,
+	`{{.Label "` + LabelVariant + `"}}`,
+	`{{.Label "` + LabelPort + `"}}`,
+	`{{.Label "` + LabelCodePath + `"}}`,
+	`{{.Label "` + LabelCreatedAt + `"}}`,
+	`{{.Label "` + LabelVersion + `"}}`,
+}, "\t")
+
+// IsRunning returns true if the booth container is currently running.
+func (info BoothInfo) IsRunning() bool {
+	return info.State == "running"
+}
+
+// ListBooths returns all booth-managed containers matching the filter (ListAll, ListRunning or ListStopped).
+func ListBooths(client docker.DockerClient, flags docker.DockerFlags, filter string) ([]BoothInfo, error) {
+	output, err := client.Ps(flags, ilist.NewList(ilist.NewList(
+		"-a",
+		"--filter", "label="+LabelManaged+"=true",
+		"--format", boothPsFormat,
+	)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booth containers: %w", err)
+	}
+
+	booths := parseBoothInfoLines(output)
+
+	filtered := make([]BoothInfo, 0, len(booths))
+	for _, info := range booths {
+		switch filter {
+		case ListRunning:
+			if !info.IsRunning() {
+				continue
+			}
+		case ListStopped:
+			if info.IsRunning() {
+				continue
+			}
+		}
+		filtered = append(filtered, info)
+	}
+	return filtered, nil
+}
+
+// parseBoothInfoLines parses the output of `docker ps --format boothPsFormat`.
+func parseBoothInfoLines(output string) []BoothInfo {
+	var booths []BoothInfo
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if info, ok := parseBoothInfoLine(line); ok {
+			booths = append(booths, info)
+		}
+	}
+	return booths
+}
+
+// parseBoothInfoLine parses a single tab-separated line; returns false if the line is malformed.
+func parseBoothInfoLine(line string) (BoothInfo, bool) {
+	fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+	if len(fields) < 9 {
+		return BoothInfo{}, false
+	}
+
+	info := BoothInfo{
+		ID:        fields[0],
+		Name:      fields[1],
+		State:     fields[2],
+		Project:   fields[3],
+		Variant:   fields[4],
+		Port:      fields[5],
+		CodePath:  fields[6],
+		CreatedAt: fields[7],
+		Version:   fields[8],
+	}
+	if info.IsRunning() {
+		info.Status = "Running"
+	} else {
+		info.Status = "Stopped"
+	}
+	return info, true
+}
+
+// WriteBoothTable writes the booths as an aligned table.
+func WriteBoothTable(writer io.Writer, booths []BoothInfo, now time.Time) {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tSTATUS\tVARIANT\tPORT\tCODE PATH\tCREATED")
+	for _, info := range booths {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Name, info.Status, info.Variant, info.Port, info.CodePath, formatAge(info.CreatedAt, now))
+	}
+	table.Flush()
+}
+
+// formatAge turns an RFC3339 timestamp into a short relative age such as "2m ago".
+func formatAge(createdAt string, now time.Time) string {
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return "-"
+	}
+
+	age := now.Sub(created)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
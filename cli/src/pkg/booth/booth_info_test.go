@@ -0,0 +1,61 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBoothInfoLines(t *testing.T) {
+	output := "abc123\tmy-project\trunning\tmy-project\tbase\t10000\t/home/user/my-project\t2025-01-02T03:04:05Z\t0.13.0\n" +
+		"def456\tapi-service\texited\tapi-service\tcodeserver\t11000\t/home/user/api service\t\t0.13.0\n" +
+		"malformed line\n"
+
+	booths := parseBoothInfoLines(output)
+	if len(booths) != 2 {
+		t.Fatalf("expected 2 booths, got %d: %+v", len(booths), booths)
+	}
+
+	first := booths[0]
+	if first.Name != "my-project" || first.Status != "Running" || first.Variant != "base" || first.Port != "10000" {
+		t.Errorf("unexpected first booth: %+v", first)
+	}
+	if !first.IsRunning() {
+		t.Errorf("expected first booth to be running")
+	}
+
+	second := booths[1]
+	if second.Status != "Stopped" || second.CodePath != "/home/user/api service" {
+		t.Errorf("unexpected second booth: %+v", second)
+	}
+}
+
+func TestWriteBoothTable(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 6, 5, 0, time.UTC)
+	booths := []BoothInfo{
+		{Name: "my-project", Status: "Running", Variant: "base", Port: "10000", CodePath: "/home/user/my-project", CreatedAt: "2025-01-02T03:04:05Z"},
+		{Name: "api-service", Status: "Stopped", Variant: "codeserver", Port: "11000", CodePath: "/home/user/api", CreatedAt: ""},
+	}
+
+	var buffer bytes.Buffer
+	WriteBoothTable(&buffer, booths, now)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d lines:\n%s", len(lines), buffer.String())
+	}
+	if !strings.HasPrefix(lines[0], "NAME") || !strings.Contains(lines[0], "CODE PATH") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "2m ago") {
+		t.Errorf("expected relative age in first row, got %q", lines[1])
+	}
+	if !strings.HasSuffix(strings.TrimSpace(lines[2]), "-") {
+		t.Errorf("expected '-' for unknown age, got %q", lines[2])
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// Labels stamped on every booth container so that lifecycle commands can find them later.
+const (
+	LabelManaged   = "cb.managed"
+	LabelProject   = "cb.project"
+	LabelVariant   = "cb.variant"
+	LabelCodePath  = "cb.code-path"
+	LabelPort      = "cb.port"
+	LabelPorts     = "cb.ports"
+	LabelCreatedAt = "cb.created-at"
+	LabelVersion   = "cb.version"
+	// LabelDind names the DinD sidecar whose network the booth shares (podman reports the sidecar ID as the
+	// network mode and nerdctl does not report it, so the lifecycle commands cannot rely on the network mode).
+	LabelDind = "cb.dind"
+)
+
+// labelArgs returns the --label arguments identifying the booth container described by ctx.
+// The host ports of the [ports] of the config are labeled too ("<name>=<port>,...") so later launches leave them to it.
+func labelArgs(ctx appctx.AppContext, createdAt time.Time) []ilist.List[string] {
+	codePath := ctx.Code()
+	if absPath, err := filepath.Abs(codePath); err == nil && codePath != "" {
+		codePath = absPath
+	}
+
+	labels := []ilist.List[string]{
+		ilist.NewList("--label", LabelManaged+"=true"),
+		ilist.NewList("--label", LabelProject+"="+ctx.ProjectName()),
+		ilist.NewList("--label", LabelVariant+"="+ctx.Variant()),
+		ilist.NewList("--label", LabelCodePath+"="+codePath),
+		ilist.NewList("--label", LabelPort+"="+strconv.Itoa(ctx.PortNumber())),
+	}
+	if namedPorts := ctx.NamedPorts(); len(namedPorts) > 0 {
+		ports := []string{}
+		for _, name := range slices.Sorted(maps.Keys(namedPorts)) {
+			ports = append(ports, name+"="+strconv.Itoa(namedPorts[name]))
+		}
+		labels = append(labels, ilist.NewList("--label", LabelPorts+"="+strings.Join(ports, ",")))
+	}
+	return append(labels,
+		ilist.NewList("--label", LabelCreatedAt+"="+createdAt.UTC().Format(time.RFC3339)),
+		ilist.NewList("--label", LabelVersion+"="+ctx.CbVersion()),
+	)
+}
@@ -0,0 +1,67 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func TestPrepareCommonArgs_Labels(t *testing.T) {
+	builder := &appctx.AppContextBuilder{
+		CbVersion:  "0.13.0",
+		CommonArgs: ilist.NewAppendableList[ilist.List[string]](),
+		BuildArgs:  ilist.NewAppendableList[ilist.List[string]](),
+		RunArgs:    ilist.NewAppendableList[ilist.List[string]](),
+		Cmds:       ilist.NewAppendableList[ilist.List[string]](),
+		PortNumber: 12000,
+	}
+	builder.Config.Code = nillable.NewNillableString("/home/user/my-project")
+	builder.Config.ProjectName = "my-project"
+	builder.Config.Name = "my-project"
+	builder.Config.Variant = "codeserver"
+
+	ctx, err := PrepareCommonArgs(builder.Build())
+	if err != nil {
+		t.Fatalf("PrepareCommonArgs() returned error: %v", err)
+	}
+	args := strings.Join(flattenArgs(ctx.CommonArgs()), " ")
+
+	expected := []string{
+		"--label cb.managed=true",
+		"--label cb.project=my-project",
+		"--label cb.variant=codeserver",
+		"--label cb.code-path=/home/user/my-project",
+		"--label cb.port=12000",
+		"--label cb.created-at=",
+		"--label cb.version=0.13.0",
+	}
+	for _, label := range expected {
+		if !strings.Contains(args, label) {
+			t.Errorf("expected %q in common args, got: %s", label, args)
+		}
+	}
+}
+
+func TestLabelArgs_CreatedAtIsUTC(t *testing.T) {
+	builder := &appctx.AppContextBuilder{}
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.FixedZone("ICT", 7*60*60))
+
+	args := labelArgs(builder.Build(), createdAt)
+	found := false
+	for _, arg := range args {
+		if arg.At(1) == LabelCreatedAt+"=2025-06-01T05:00:00Z" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected created-at label in UTC, got %v", args)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// BoothNotFoundError is returned when no booth container matches the requested name or code path.
+type BoothNotFoundError struct {
+	Name   string
+	Code   string
+	Filter string
+}
+
+func (e *BoothNotFoundError) Error() string {
+	state := ""
+	switch e.Filter {
+	case ListRunning:
+		state = "running "
+	case ListStopped:
+		state = "stopped "
+	}
+	if e.Name != "" {
+		return fmt.Sprintf("no %sbooth '%s' found", state, e.Name)
+	}
+	return fmt.Sprintf("no %sbooth found for code path '%s'", state, e.Code)
+}
+
+// BoothTarget is a booth container resolved for a lifecycle command, together with its DinD companions.
+type BoothTarget struct {
+	Info      BoothInfo
+	KeepAlive bool
+	DindName  string
+	DindNet   string
+}
+
+// HasDind returns true if the booth shares the network namespace of a DinD sidecar.
+func (target BoothTarget) HasDind() bool {
+	return target.DindName != ""
+}
+
+// FindBooth resolves a booth container by name, or by the code path it was started with.
+// When name is empty, the code path is used (an empty code path means the current directory).
+// The filter (ListAll, ListRunning or ListStopped) restricts which containers can match.
+func FindBooth(client docker.DockerClient, flags docker.DockerFlags, name string, code string, filter string) (BoothTarget, error) {
+	// Lookups must always run, even in dryrun mode
+	lookupFlags := docker.DockerFlags{Verbose: flags.Verbose, Silent: true}
+
+	booths, err := ListBooths(client, lookupFlags, filter)
+	if err != nil {
+		return BoothTarget{}, err
+	}
+
+	var found *BoothInfo
+	if name != "" {
+		for index := range booths {
+			if booths[index].Name == name {
+				found = &booths[index]
+				break
+			}
+		}
+		if found == nil {
+			return BoothTarget{}, &BoothNotFoundError{Name: name, Filter: filter}
+		}
+	} else {
+		codePath, err := filepath.Abs(code)
+		if err != nil {
+			codePath = code
+		}
+
+		var matches []BoothInfo
+		for _, info := range booths {
+			if info.CodePath == codePath {
+				matches = append(matches, info)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return BoothTarget{}, &BoothNotFoundError{Code: codePath, Filter: filter}
+		case 1:
+			found = &matches[0]
+		default:
+			names := make([]string, 0, len(matches))
+			for _, info := range matches {
+				names = append(names, info.Name)
+			}
+			return BoothTarget{}, fmt.Errorf("multiple booths found for code path '%s' (%s); use --name to pick one",
+				codePath, strings.Join(names, ", "))
+		}
+	}
+
+	return inspectBoothTarget(client, lookupFlags, *found)
+}
+
+// inspectBoothTarget reads the keep-alive (--rm) and DinD (LabelDind, or else the container network) settings of the booth container.
+func inspectBoothTarget(client docker.DockerClient, flags docker.DockerFlags, info BoothInfo) (BoothTarget, error) {
+	output, err := client.Inspect(flags, ilist.NewList(ilist.NewList(
+		"--format", `{{.HostConfig.AutoRemove}}\t{{.HostConfig.NetworkMode}}\t{{index .Config.Labels "`+LabelDind+`"}}`,
+		info.Name,
+	)))
+	if err != nil {
+		return BoothTarget{}, fmt.Errorf("failed to inspect booth '%s': %w", info.Name, err)
+	}
+
+	return parseBoothTarget(info, output), nil
+}
+
+// parseBoothTarget builds a BoothTarget from `docker inspect` output of inspectBoothTarget.
+func parseBoothTarget(info BoothInfo, output string) BoothTarget {
+	target := BoothTarget{Info: info}
+
+	fields := strings.Split(strings.TrimSpace(output), "\t")
+	if len(fields) > 0 {
+		target.KeepAlive = fields[0] != "true"
+	}
+	dindName := ""
+	if len(fields) > 2 && fields[2] != "<no value>" {
+		dindName = fields[2]
+	}
+	if dindName == "" && len(fields) > 1 && strings.HasPrefix(fields[1], "container:") {
+		// Booths started before LabelDind (docker reports the sidecar name as the network mode)
+		dindName = strings.TrimPrefix(fields[1], "container:")
+	}
+	if strings.HasSuffix(dindName, "-dind") {
+		target.DindName = dindName
+		target.DindNet = strings.TrimSuffix(dindName, "-dind") + "-net"
+	}
+	return target
+}
+
+// StartBooth starts a stopped booth, bringing up its DinD network and sidecar first.
+// When attach is true, the booth's console is attached to the current terminal.
+func StartBooth(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, attach bool) error {
+	if target.HasDind() {
+		silentFlags := flags
+		silentFlags.Silent = true
+		if _, err := client.Network(silentFlags, ilist.NewList(ilist.NewList("inspect", target.DindNet))); err != nil {
+			if _, err := client.Network(flags, ilist.NewList(ilist.NewList("create", target.DindNet))); err != nil {
+				return fmt.Errorf("failed to create DinD network '%s': %w", target.DindNet, err)
+			}
+		}
+		if err := client.Command(flags, "start", ilist.NewList(ilist.NewList(target.DindName))); err != nil {
+			return fmt.Errorf("failed to start DinD sidecar '%s': %w", target.DindName, err)
+		}
+	}
+
+	args := ilist.NewList[ilist.List[string]]()
+	if attach {
+		// Long forms: the docker package strips standalone -i as a TTY flag
+		args = args.ExtendByLists(ilist.NewList(ilist.NewList("--attach", "--interactive")))
+	}
+	args = args.ExtendByLists(ilist.NewList(ilist.NewList(target.Info.Name)))
+
+	return client.Command(flags, "start", args)
+}
+
+// StopBooth stops a booth and its DinD sidecar; force kills them (SIGKILL) instead.
+// Booths that were not kept alive (--rm) are removed together with their sidecar and network.
+func StopBooth(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, timeout string, force bool) error {
+	subcommand := "stop"
+	stopArgs := ilist.NewList[string]()
+	if force {
+		subcommand = "kill"
+	} else if timeout != "" {
+		stopArgs = ilist.NewList("--time", timeout)
+	}
+
+	err := client.Command(flags, subcommand, ilist.NewList(stopArgs, ilist.NewList(target.Info.Name)))
+	if err != nil {
+		return fmt.Errorf("failed to stop booth '%s': %w", target.Info.Name, err)
+	}
+
+	if target.HasDind() {
+		silentFlags := flags
+		silentFlags.Silent = true
+		_ = client.Command(silentFlags, subcommand, ilist.NewList(stopArgs, ilist.NewList(target.DindName)))
+	}
+
+	if !target.KeepAlive {
+		// Normally already gone through --rm; make sure nothing is left behind
+		silentFlags := flags
+		silentFlags.Silent = true
+		_ = client.Command(silentFlags, "rm", ilist.NewList(ilist.NewList("-f", target.Info.Name)))
+		removeDindCompanions(client, flags, target)
+	}
+	return nil
+}
+
+// RestartBooth restarts a booth; the DinD sidecar is restarted first so the booth rejoins its network.
+func RestartBooth(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, timeout string) error {
+	restartArgs := ilist.NewList[string]()
+	if timeout != "" {
+		restartArgs = ilist.NewList("--time", timeout)
+	}
+
+	if target.HasDind() {
+		err := client.Command(flags, "restart", ilist.NewList(restartArgs, ilist.NewList(target.DindName)))
+		if err != nil {
+			return fmt.Errorf("failed to restart DinD sidecar '%s': %w", target.DindName, err)
+		}
+	}
+
+	err := client.Command(flags, "restart", ilist.NewList(restartArgs, ilist.NewList(target.Info.Name)))
+	if err != nil {
+		return fmt.Errorf("failed to restart booth '%s': %w", target.Info.Name, err)
+	}
+	return nil
+}
+
+// RemoveBooth removes a stopped booth (or a running one when force is true) with its DinD sidecar and network.
+func RemoveBooth(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, force bool) error {
+	if target.Info.IsRunning() && !force {
+		return fmt.Errorf("booth '%s' is running; stop it first or use --force", target.Info.Name)
+	}
+
+	rmArgs := ilist.NewList[string]()
+	if force {
+		rmArgs = ilist.NewList("-f")
+	}
+
+	err := client.Command(flags, "rm", ilist.NewList(rmArgs, ilist.NewList(target.Info.Name)))
+	if err != nil {
+		return fmt.Errorf("failed to remove booth '%s': %w", target.Info.Name, err)
+	}
+
+	removeDindCompanions(client, flags, target)
+	return nil
+}
+
+// removeDindCompanions removes the DinD sidecar and network of the booth, if any (errors ignored).
+func removeDindCompanions(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget) {
+	if !target.HasDind() {
+		return
+	}
+
+	silentFlags := flags
+	silentFlags.Silent = true
+	_ = client.Command(silentFlags, "rm", ilist.NewList(ilist.NewList("-f", target.DindName)))
+	_, _ = client.Network(silentFlags, ilist.NewList(ilist.NewList("rm", target.DindNet)))
+}
@@ -0,0 +1,153 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+func TestParseBoothTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		wantKeepAlive bool
+		wantDindName  string
+		wantDindNet   string
+	}{
+		{
+			name:          "removed on stop, default network",
+			output:        "true\tdefault\n",
+			wantKeepAlive: false,
+		},
+		{
+			name:          "kept alive, bridge network",
+			output:        "false\tbridge\n",
+			wantKeepAlive: true,
+		},
+		{
+			name:          "kept alive with DinD sidecar",
+			output:        "false\tcontainer:my-project-10000-dind\n",
+			wantKeepAlive: true,
+			wantDindName:  "my-project-10000-dind",
+			wantDindNet:   "my-project-10000-net",
+		},
+		{
+			name:          "podman DinD sidecar (network mode with the sidecar ID)",
+			output:        "false\tcontainer:3f4e5d6c7b8a\tmy-project-10000-dind\n",
+			wantKeepAlive: true,
+			wantDindName:  "my-project-10000-dind",
+			wantDindNet:   "my-project-10000-net",
+		},
+		{
+			name:          "no DinD label",
+			output:        "true\tbridge\t<no value>\n",
+			wantKeepAlive: false,
+		},
+		{
+			name:          "container network that is not a DinD sidecar",
+			output:        "true\tcontainer:some-other\n",
+			wantKeepAlive: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target := parseBoothTarget(BoothInfo{Name: "my-project"}, test.output)
+			if target.KeepAlive != test.wantKeepAlive {
+				t.Errorf("KeepAlive = %v, want %v", target.KeepAlive, test.wantKeepAlive)
+			}
+			if target.DindName != test.wantDindName || target.DindNet != test.wantDindNet {
+				t.Errorf("DinD = (%q, %q), want (%q, %q)", target.DindName, target.DindNet, test.wantDindName, test.wantDindNet)
+			}
+			if target.HasDind() != (test.wantDindName != "") {
+				t.Errorf("HasDind = %v", target.HasDind())
+			}
+		})
+	}
+}
+
+func TestBoothNotFoundError(t *testing.T) {
+	byName := &BoothNotFoundError{Name: "my-project", Filter: ListStopped}
+	if got := byName.Error(); got != "no stopped booth 'my-project' found" {
+		t.Errorf("unexpected message: %q", got)
+	}
+
+	byCode := &BoothNotFoundError{Code: "/home/user/my-project", Filter: ListAll}
+	if got := byCode.Error(); got != "no booth found for code path '/home/user/my-project'" {
+		t.Errorf("unexpected message: %q", got)
+	}
+}
+
+func TestFindBooth_ByName(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		switch call.Subcommand {
+		case "ps":
+			return strings.Join([]string{"abc123", "my-project", "running", "my-project", "base", "12000", "/code", "", ""}, "\t") + "\n", nil
+		case "inspect":
+			return "false\tcontainer:my-project-12000-dind\n", nil
+		}
+		return "", nil
+	}
+
+	target, err := FindBooth(client, docker.DockerFlags{}, "my-project", "", ListAll)
+	if err != nil {
+		t.Fatalf("FindBooth() returned error: %v", err)
+	}
+	if target.Info.Name != "my-project" || !target.KeepAlive || target.DindNet != "my-project-12000-net" {
+		t.Errorf("unexpected target: %+v", target)
+	}
+	if commands := client.Commands(); len(commands) != 2 || !strings.HasPrefix(commands[1], "docker inspect --format") {
+		t.Errorf("expected a ps and an inspect, got %q", commands)
+	}
+}
+
+func TestStartBooth_Dind(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		if call.Subcommand == "network" && call.Args[0] == "inspect" {
+			return "", errors.New("no such network")
+		}
+		return "", nil
+	}
+	target := BoothTarget{Info: BoothInfo{Name: "my-project"}, DindName: "my-project-12000-dind", DindNet: "my-project-12000-net"}
+
+	if err := StartBooth(client, docker.DockerFlags{}, target, true); err != nil {
+		t.Fatalf("StartBooth() returned error: %v", err)
+	}
+	want := []string{
+		"docker network inspect my-project-12000-net",
+		"docker network create my-project-12000-net",
+		"docker start my-project-12000-dind",
+		"docker start --attach --interactive my-project",
+	}
+	if commands := client.Commands(); !reflect.DeepEqual(commands, want) {
+		t.Errorf("Commands() =\n%q\nwant\n%q", commands, want)
+	}
+}
+
+func TestStopBooth_RemovesDindCompanions(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	target := BoothTarget{Info: BoothInfo{Name: "my-project"}, DindName: "my-project-12000-dind", DindNet: "my-project-12000-net"}
+
+	if err := StopBooth(client, docker.DockerFlags{}, target, "5", false); err != nil {
+		t.Fatalf("StopBooth() returned error: %v", err)
+	}
+	want := []string{
+		"docker stop --time 5 my-project",
+		"docker stop --time 5 my-project-12000-dind",
+		"docker rm -f my-project",
+		"docker rm -f my-project-12000-dind",
+		"docker network rm my-project-12000-net",
+	}
+	if commands := client.Commands(); !reflect.DeepEqual(commands, want) {
+		t.Errorf("Commands() =\n%q\nwant\n%q", commands, want)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// LogOptions controls which logs `logs` shows.
+type LogOptions struct {
+	Follow     bool
+	Since      string
+	Phase      string
+	Dind       bool
+	Timestamps bool
+}
+
+// logLine is one line of container output.
+type logLine struct {
+	time   time.Time
+	stamp  string
+	source string
+	text   string
+}
+
+// StreamBoothLogs writes the logs of a booth to writer, filtered to a booth-entry phase when options.Phase is set.
+// With options.Dind, the DinD sidecar logs are interleaved (by timestamp), each line prefixed with its container name.
+func StreamBoothLogs(client docker.DockerClient, flags docker.DockerFlags, target BoothTarget, options LogOptions, writer io.Writer) error {
+	var phaseFilter *PhaseFilter
+	if options.Phase != "" {
+		filter, err := NewPhaseFilter(options.Phase)
+		if err != nil {
+			return err
+		}
+		phaseFilter = filter
+	}
+
+	sources := []string{target.Info.Name}
+	if options.Dind {
+		if !target.HasDind() {
+			return fmt.Errorf("booth '%s' has no DinD sidecar", target.Info.Name)
+		}
+		sources = append(sources, target.DindName)
+	}
+
+	// Timestamps are needed to interleave several containers
+	withTimestamps := options.Timestamps || len(sources) > 1
+	prefixWidth := 0
+	if len(sources) > 1 {
+		for _, source := range sources {
+			prefixWidth = max(prefixWidth, len(source))
+		}
+	}
+
+	var lock sync.Mutex
+	var collected []logLine
+	// Without --follow, lines of several containers are collected then merged by time
+	collect := !options.Follow && len(sources) > 1
+	emit := func(line logLine) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if phaseFilter != nil && line.source == target.Info.Name {
+			for _, text := range phaseFilter.Filter(line.text) {
+				filtered := line
+				filtered.text = text
+				emitLogLine(writer, filtered, collect, &collected, options.Timestamps, prefixWidth)
+			}
+			return
+		}
+		emitLogLine(writer, line, collect, &collected, options.Timestamps, prefixWidth)
+	}
+
+	var streamErr error
+	var group sync.WaitGroup
+	for _, source := range sources {
+		run := func(source string) {
+			err := streamContainerLogs(client, flags, source, options, withTimestamps, emit)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil && streamErr == nil {
+				streamErr = fmt.Errorf("failed to read logs of '%s': %w", source, err)
+			}
+		}
+		if flags.Dryrun {
+			// Keep the printed commands in order
+			run(source)
+			continue
+		}
+		group.Add(1)
+		go func(source string) {
+			defer group.Done()
+			run(source)
+		}(source)
+	}
+	group.Wait()
+
+	if collect {
+		sort.SliceStable(collected, func(i, j int) bool {
+			return collected[i].time.Before(collected[j].time)
+		})
+		for _, line := range collected {
+			writeLogLine(writer, line, options.Timestamps, prefixWidth)
+		}
+	}
+
+	if phaseFilter != nil && !phaseFilter.SeenSection() && !flags.Dryrun && streamErr == nil {
+		fmt.Fprintln(os.Stderr, "Warning: no booth-entry section headers found in the logs.")
+		fmt.Fprintln(os.Stderr, "         Section headers are only printed when the booth runs with --verbose (CB_VERBOSE=true).")
+	}
+	return streamErr
+}
+
+// streamContainerLogs runs `docker logs` for one container, calling emit for every line (stdout and stderr).
+func streamContainerLogs(client docker.DockerClient, flags docker.DockerFlags, container string, options LogOptions, withTimestamps bool, emit func(logLine)) error {
+	logArgs := []string{}
+	if options.Follow {
+		logArgs = append(logArgs, "--follow")
+	}
+	if options.Since != "" {
+		logArgs = append(logArgs, "--since", options.Since)
+	}
+	if withTimestamps {
+		logArgs = append(logArgs, "--timestamps")
+	}
+
+	toLine := func(raw string) {
+		emit(parseLogLine(container, raw, withTimestamps))
+	}
+	stdout := &lineWriter{emit: toLine}
+	stderr := &lineWriter{emit: toLine}
+
+	err := client.Stream(flags, "logs", ilist.NewList(ilist.NewListFromSlice(logArgs), ilist.NewList(container)), stdout, stderr)
+	stdout.Flush()
+	stderr.Flush()
+	return err
+}
+
+// parseLogLine splits the leading `docker logs --timestamps` timestamp (if any) from a raw line.
+func parseLogLine(source string, raw string, withTimestamps bool) logLine {
+	line := logLine{source: source, text: raw}
+	if !withTimestamps {
+		return line
+	}
+
+	stamp, text, found := strings.Cut(raw, " ")
+	if !found {
+		stamp, text = raw, ""
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return line
+	}
+	line.time = parsed
+	line.stamp = stamp
+	line.text = text
+	return line
+}
+
+func emitLogLine(writer io.Writer, line logLine, collect bool, collected *[]logLine, timestamps bool, prefixWidth int) {
+	if collect {
+		*collected = append(*collected, line)
+		return
+	}
+	writeLogLine(writer, line, timestamps, prefixWidth)
+}
+
+// writeLogLine writes a line, prefixed with its container name when several containers are shown.
+func writeLogLine(writer io.Writer, line logLine, timestamps bool, prefixWidth int) {
+	prefix := ""
+	if prefixWidth > 0 {
+		prefix = fmt.Sprintf("%-*s | ", prefixWidth, line.source)
+	}
+	if timestamps && line.stamp != "" {
+		prefix += line.stamp + " "
+	}
+	fmt.Fprintln(writer, prefix+line.text)
+}
+
+// lineWriter is an io.Writer that calls emit for every complete line written to it.
+type lineWriter struct {
+	buffer []byte
+	emit   func(string)
+}
+
+func (writer *lineWriter) Write(data []byte) (int, error) {
+	writer.buffer = append(writer.buffer, data...)
+	for {
+		index := bytes.IndexByte(writer.buffer, '\n')
+		if index < 0 {
+			break
+		}
+		writer.emit(strings.TrimSuffix(string(writer.buffer[:index]), "\r"))
+		writer.buffer = writer.buffer[index+1:]
+	}
+	return len(data), nil
+}
+
+// Flush emits the last line if it has no trailing newline.
+func (writer *lineWriter) Flush() {
+	if len(writer.buffer) > 0 {
+		writer.emit(strings.TrimSuffix(string(writer.buffer), "\r"))
+		writer.buffer = nil
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BoothPhases are the section titles that booth-entry prints (when CB_VERBOSE=true), in order.
+var BoothPhases = []string{
+	"Ensure user/group alignment ...",
+	"Allow sudo access to the user",
+	"Setup user profile/environment",
+	"Prepare the user's home directory",
+	"Run one-time CodingBooth startup hooks",
+	"Execute the requested command",
+}
+
+const (
+	setupLogPrefix  = "[cb-setup] "
+	sectionRuleMark = "[cb-setup] ====="
+)
+
+// PhaseFilter passes through only the log lines of the booth-entry sections matching a phase.
+type PhaseFilter struct {
+	number      int
+	fragment    string
+	pendingRule string
+	inPhase     bool
+	seenSection bool
+}
+
+// NewPhaseFilter creates a filter for a phase given as its 1-based number or a fragment of its title.
+func NewPhaseFilter(phase string) (*PhaseFilter, error) {
+	phase = strings.TrimSpace(phase)
+	if number, err := strconv.Atoi(phase); err == nil {
+		if number < 1 || number > len(BoothPhases) {
+			return nil, fmt.Errorf("unknown phase %d (must be 1-%d):\n%s", number, len(BoothPhases), FormatPhases())
+		}
+		return &PhaseFilter{number: number}, nil
+	}
+
+	fragment := strings.ToLower(phase)
+	for _, title := range BoothPhases {
+		if strings.Contains(strings.ToLower(title), fragment) {
+			return &PhaseFilter{fragment: fragment}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown phase '%s':\n%s", phase, FormatPhases())
+}
+
+// FormatPhases returns the numbered list of phases, one per line.
+func FormatPhases() string {
+	lines := make([]string, 0, len(BoothPhases))
+	for index, title := range BoothPhases {
+		lines = append(lines, fmt.Sprintf("  %d) %s", index+1, title))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SeenSection returns true once any section header has been read.
+func (filter *PhaseFilter) SeenSection() bool {
+	return filter.seenSection
+}
+
+// Filter consumes one log line and returns the lines to output (none, the line itself,
+// or a held-back section rule together with the section title).
+func (filter *PhaseFilter) Filter(line string) []string {
+	if strings.HasPrefix(line, sectionRuleMark) {
+		held := filter.flushRule()
+		filter.pendingRule = line
+		return held
+	}
+
+	if filter.pendingRule != "" && strings.HasPrefix(line, setupLogPrefix) {
+		rule := filter.pendingRule
+		filter.pendingRule = ""
+		filter.seenSection = true
+		filter.inPhase = filter.matches(strings.TrimPrefix(line, setupLogPrefix))
+		if filter.inPhase {
+			return []string{rule, line}
+		}
+		return nil
+	}
+
+	held := filter.flushRule()
+	if filter.inPhase {
+		return append(held, line)
+	}
+	return held
+}
+
+// flushRule releases a held-back rule line that turned out not to start a section.
+func (filter *PhaseFilter) flushRule() []string {
+	if filter.pendingRule == "" {
+		return nil
+	}
+	rule := filter.pendingRule
+	filter.pendingRule = ""
+	if filter.inPhase {
+		return []string{rule}
+	}
+	return nil
+}
+
+func (filter *PhaseFilter) matches(title string) bool {
+	title = strings.TrimSpace(title)
+	if filter.number > 0 {
+		return title == BoothPhases[filter.number-1]
+	}
+	return strings.Contains(strings.ToLower(title), filter.fragment)
+}
@@ -0,0 +1,130 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleRule = "[cb-setup] ===================================================================="
+const sampleDash = "[cb-setup] --------------------------------------------------------------------"
+
+var sampleEntryLog = []string{
+	"",
+	sampleRule,
+	"[cb-setup] Ensure user/group alignment ...",
+	sampleDash,
+	"[cb-setup] 1) Ensure 'coder' group exists",
+	"",
+	sampleRule,
+	"[cb-setup] Allow sudo access to the user",
+	sampleDash,
+	"[cb-setup] 6) Configuring passwordless sudo for 'coder'...",
+	"",
+	sampleRule,
+	"[cb-setup] Execute the requested command",
+	sampleDash,
+	"[cb-setup] 16) Executing the requested command as coder",
+	"hello from the booth",
+}
+
+func filterAll(filter *PhaseFilter, lines []string) []string {
+	var result []string
+	for _, line := range lines {
+		result = append(result, filter.Filter(line)...)
+	}
+	return result
+}
+
+func TestPhaseFilter_ByNumber(t *testing.T) {
+	filter, err := NewPhaseFilter("2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := filterAll(filter, sampleEntryLog)
+	want := []string{
+		sampleRule,
+		"[cb-setup] Allow sudo access to the user",
+		sampleDash,
+		"[cb-setup] 6) Configuring passwordless sudo for 'coder'...",
+		"",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	if !filter.SeenSection() {
+		t.Error("expected sections to be seen")
+	}
+}
+
+func TestPhaseFilter_ByName(t *testing.T) {
+	filter, err := NewPhaseFilter("COMMAND")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := filterAll(filter, sampleEntryLog)
+	want := []string{
+		sampleRule,
+		"[cb-setup] Execute the requested command",
+		sampleDash,
+		"[cb-setup] 16) Executing the requested command as coder",
+		"hello from the booth",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestPhaseFilter_NoSections(t *testing.T) {
+	filter, _ := NewPhaseFilter("1")
+	if got := filterAll(filter, []string{"plain output", "more output"}); len(got) != 0 {
+		t.Errorf("expected nothing, got %q", got)
+	}
+	if filter.SeenSection() {
+		t.Error("expected no section to be seen")
+	}
+}
+
+func TestNewPhaseFilter_Invalid(t *testing.T) {
+	for _, phase := range []string{"0", "7", "no-such-phase"} {
+		if _, err := NewPhaseFilter(phase); err == nil {
+			t.Errorf("expected an error for phase %q", phase)
+		}
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	line := parseLogLine("my-project", "2026-01-02T03:04:05.5Z hello world", true)
+	if line.text != "hello world" || line.stamp != "2026-01-02T03:04:05.5Z" || line.time.IsZero() {
+		t.Errorf("unexpected line: %+v", line)
+	}
+
+	line = parseLogLine("my-project", "not a timestamp", true)
+	if line.text != "not a timestamp" || !line.time.IsZero() {
+		t.Errorf("unexpected line: %+v", line)
+	}
+
+	line = parseLogLine("my-project", "2026-01-02T03:04:05Z kept", false)
+	if line.text != "2026-01-02T03:04:05Z kept" {
+		t.Errorf("unexpected line: %+v", line)
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	writer := &lineWriter{emit: func(line string) { lines = append(lines, line) }}
+
+	writer.Write([]byte("first\r\nsec"))
+	writer.Write([]byte("ond\nthird"))
+	writer.Flush()
+
+	want := []string{"first", "second", "third"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+}
@@ -6,44 +6,123 @@
 package booth
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"slices"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
 	"github.com/nawaman/codingbooth/src/pkg/ilist"
 )
 
 // BoothRunner handles the "run" command for booth operations.
-// It orchestrates the preparation of AppContext and execution of the booth.
+// It orchestrates the preparation of AppContext and execution of the booth through a list of stages.
 type BoothRunner struct {
-	ctx appctx.AppContext
+	ctx    appctx.AppContext
+	stages *StageRegistry
 }
 
-// NewBoothRunner creates a new BoothRunner with the given AppContext.
+// NewBoothRunner creates a new BoothRunner with the given AppContext and the default stages.
 func NewBoothRunner(ctx appctx.AppContext) *BoothRunner {
-	return &BoothRunner{ctx: ctx}
+	return NewBoothRunnerWithStages(ctx, DefaultStageRegistry())
 }
 
-// Run is the main entry point that prepares the context and executes the booth.
+// NewBoothRunnerWithStages creates a new BoothRunner that runs the stages of the given registry.
+func NewBoothRunnerWithStages(ctx appctx.AppContext, stages *StageRegistry) *BoothRunner {
+	return &BoothRunner{ctx: ctx, stages: stages}
+}
+
+// Run is the main entry point that runs all the stages, from preparing the context to running the booth.
+// Each stage is surrounded by its `before-<stage>`/`after-<stage>` hooks (if any); the stages run again
+// after a HostPortTakenError do not run their hooks again.
+// When stages fail, the errors (as StageError) of all the stages that could run are returned together (see errors.Join).
 func (runner *BoothRunner) Run() error {
-	// Prepare arguments and determine run mode (matching booth order)
 	ctx := runner.ctx
-	ctx = ValidateVariant(ctx)
-	ctx = EnsureDockerImage(ctx)
-	ctx = ApplyEnvFile(ctx)
-	ctx = PortDetermination(ctx)
-	ctx = ShowDebugBanner(ctx)
-	ctx = SetupDind(ctx)
-	ctx = PrepareRunMode(ctx)
-	ctx = PrepareCommonArgs(ctx)
-
-	// Create booth with prepared context and run
-	booth := NewBooth(ctx)
-	return booth.Run(ctx.RunMode())
+	hooks, err := resolveStageHooks(ctx.Hooks(), runner.stages)
+	if err != nil {
+		return err
+	}
+
+	// Reserved ports (see PortDetermination) are released whatever the outcome
+	defer func() { releasePorts(ctx.ReservedPorts()) }()
+
+	stages := runner.stages.Stages()
+	var stageErrors []error
+	portStage, portCtx, portAttempts := -1, ctx, 1
+	hooked := map[string]bool{}
+	for index := 0; index < len(stages); index++ {
+		stage := stages[index]
+		failed := len(stageErrors) > 0
+		if failed && !isCheckOnlyStage(stage) {
+			continue
+		}
+		if stage.Skip(ctx) {
+			if ctx.Verbose() {
+				fmt.Printf("⏭️  Stage %s: skipped\n", stage.Name())
+			}
+			continue
+		}
+		if ctx.Verbose() {
+			fmt.Printf("▶️  Stage %s\n", stage.Name())
+		}
+
+		if stage.Name() == StagePortDetermination {
+			portStage, portCtx = index, ctx
+		}
+
+		next, err := runner.runStage(ctx, stage, hooks, !failed, hooked)
+		var taken *HostPortTakenError
+		if errors.As(err, &taken) && !failed && portStage >= 0 && portAttempts < maxPortAttempts {
+			// Determine the ports again (the taken one is not free anymore) and go on from there
+			fmt.Printf("⚠️  Host port %d was taken while the booth was starting; choosing the ports again.\n", taken.Port)
+			releasePorts(ctx.ReservedPorts())
+			ctx, index, portAttempts = portCtx, portStage-1, portAttempts+1
+			continue
+		}
+		if err != nil {
+			if ctx.Verbose() {
+				fmt.Printf("❌ Stage %s failed: %v\n", stage.Name(), err)
+			}
+			stageErrors = append(stageErrors, &StageError{Stage: stage.Name(), Err: err})
+			continue
+		}
+		ctx = next
+	}
+
+	if len(stageErrors) == 1 {
+		return stageErrors[0]
+	}
+	return errors.Join(stageErrors...)
+}
+
+// runStage runs a stage with its hooks; hooks are left out when an earlier stage failed,
+// and the ones already in hooked (by name) are not run again.
+func (runner *BoothRunner) runStage(ctx appctx.AppContext, stage Stage, hooks stageHooks, withHooks bool, hooked map[string]bool) (appctx.AppContext, error) {
+	key := stageKey(stage.Name())
+	before, after := "before-"+stage.Name(), "after-"+stage.Name()
+	if withHooks && !hooked[before] {
+		hooked[before] = true
+		if err := runHook(ctx, before, stage.Name(), hooks.before[key]); err != nil {
+			return ctx, err
+		}
+	}
+
+	next, err := stage.Run(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	if withHooks && !hooked[after] {
+		hooked[after] = true
+		if err := runHook(next, after, stage.Name(), hooks.after[key]); err != nil {
+			return ctx, err
+		}
+	}
+	return next, nil
 }
 
 // PrepareRunMode determines the run mode and stores it in the context.
-func PrepareRunMode(ctx appctx.AppContext) appctx.AppContext {
+func PrepareRunMode(ctx appctx.AppContext) (appctx.AppContext, error) {
 	builder := ctx.ToBuilder()
 
 	if ctx.Daemon() {
@@ -54,14 +133,15 @@ func PrepareRunMode(ctx appctx.AppContext) appctx.AppContext {
 		builder.RunMode = "COMMAND"
 	}
 
-	return builder.Build()
+	return builder.Build(), nil
 }
 
 // SetupDind sets up Docker-in-Docker if enabled and returns updated AppContext.
-func SetupDind(ctx appctx.AppContext) appctx.AppContext {
+// It returns a DindStartError (with a port-conflict diagnostic when one is found) if the sidecar fails to start.
+func SetupDind(ctx appctx.AppContext) (appctx.AppContext, error) {
 	// Early return if DinD is not enabled
 	if !ctx.Dind() {
-		return ctx
+		return ctx, nil
 	}
 
 	builder := ctx.ToBuilder()
@@ -78,25 +158,38 @@ func SetupDind(ctx appctx.AppContext) appctx.AppContext {
 	createdNet := createDindNetwork(ctx, dindNet)
 	builder.CreatedDindNet = createdNet
 
-	// Extract extra port mappings from RunArgs before stripping
-	extraPorts := extractPortFlags(ctx.RunArgs())
+	// Extract extra port mappings from RunArgs before stripping (and the ones of the [ports] table),
+	// published on the bind address
+	address := bindAddress(ctx)
+	extraPorts := []string{}
+	for _, port := range extractPortFlags(ctx.RunArgs()) {
+		extraPorts = append(extraPorts, bindPortMapping(address, port))
+	}
+	for _, port := range configTablePorts(ctx) {
+		if !slices.Contains(extraPorts, port) {
+			extraPorts = append(extraPorts, port)
+		}
+	}
 
 	// Start DinD sidecar if not already running (pass hostPort for port mapping)
 	err := startDindSidecar(ctx, dindName, dindNet, ctx.PortNumber(), extraPorts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to start DinD sidecar.\n\n")
+		// A generated port taken since it was determined: the ports are determined again (see BoothRunner.Run)
+		if port := takenGeneratedPort(ctx); port != 0 && !ctx.Dryrun() {
+			removeCreatedContainer(ctx, dindName)
+			if createdNet {
+				// The ports are determined again, so the next attempt uses a network of another name
+				_, _ = ctx.Docker().Network(docker.DockerFlags{Silent: true}, ilist.NewList(ilist.NewList("rm", dindNet)))
+			}
+			return ctx, &HostPortTakenError{Port: port, Err: err}
+		}
 
 		// Try to diagnose if this is a port conflict
-		port, diagnostic := diagnosePortConflict(err, ctx.PortNumber(), extraPorts)
-		if port != "" {
-			fmt.Fprintf(os.Stderr, "   %s\n", diagnostic)
-		} else {
-			// Generic error - show the original error message
-			fmt.Fprintf(os.Stderr, "   Error: %v\n", err)
-			fmt.Fprintf(os.Stderr, "   Check if any port is already in use.\n")
-			fmt.Fprintf(os.Stderr, "   Use 'lsof -i :<port>' or 'ss -tlnp | grep <port>' to find the process.\n")
+		port, diagnostic := diagnosePortConflict(ctx.Docker(), err, address, ctx.PortNumber(), extraPorts)
+		if port == "" {
+			diagnostic = ""
 		}
-		os.Exit(1)
+		return ctx, &DindStartError{Err: err, Diagnostic: diagnostic}
 	}
 
 	// Wait for DinD to become ready
@@ -108,7 +201,8 @@ func SetupDind(ctx appctx.AppContext) appctx.AppContext {
 	// Use container network mode to share DinD's network namespace
 	// This allows localhost access to DinD's ports from the booth
 	builder.CommonArgs.Append(ilist.NewList[string]("--network", fmt.Sprintf("container:%s", dindName)))
+	builder.CommonArgs.Append(ilist.NewList[string]("--label", LabelDind+"="+dindName))
 	builder.CommonArgs.Append(ilist.NewList[string]("-e", "DOCKER_HOST=tcp://localhost:2375"))
 
-	return builder.Build()
+	return builder.Build(), nil
 }
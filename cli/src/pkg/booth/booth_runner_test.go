@@ -0,0 +1,189 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func TestPortDetermination_Invalid(t *testing.T) {
+	tests := []struct {
+		port        string
+		wantMessage string
+	}{
+		{port: "abc", wantMessage: "Error: --port must be a number (got 'abc')."},
+		{port: "70000", wantMessage: "Error: --port must be between 1 and 65535 (got '70000')."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.port, func(t *testing.T) {
+			builder := &appctx.AppContextBuilder{Cmds: ilist.NewAppendableList[ilist.List[string]]()}
+			builder.Config.Port = test.port
+
+			_, err := PortDetermination(builder.Build())
+
+			var portErr *InvalidPortError
+			if !errors.As(err, &portErr) {
+				t.Fatalf("expected InvalidPortError, got %v", err)
+			}
+			if got := portErr.CliMessage(); got != test.wantMessage {
+				t.Errorf("CliMessage() = %q, want %q", got, test.wantMessage)
+			}
+		})
+	}
+}
+
+func TestPortDetermination_NamedPorts(t *testing.T) {
+	// A port in use, so NEXT has to go past it
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	taken := listener.Addr().(*net.TCPAddr).Port
+	isolatePortReservations(t)
+
+	builder := &appctx.AppContextBuilder{Cmds: ilist.NewAppendableList[ilist.List[string]]()}
+	builder.Config.Port = "12000"
+	builder.Config.Ports = map[string]appctx.PortMapping{
+		"debug": "5005",
+		"dns":   "12000/udp",
+		"web":   appctx.PortMapping("NEXT:" + strconv.Itoa(taken)),
+		"api":   "RANDOM:8080",
+	}
+
+	ctx, err := PortDetermination(builder.Build())
+	if err != nil {
+		t.Fatalf("PortDetermination() returned error: %v", err)
+	}
+	ports := ctx.NamedPorts()
+	if ports["web"] <= taken || ports["api"] < 10000 || ports["api"] == 12000 {
+		t.Errorf("NamedPorts() = %v (port %d is taken)", ports, taken)
+	}
+	if ports["debug"] != 5005 || ports["dns"] != 12000 {
+		t.Errorf("NamedPorts() = %v, want the fixed ports as is", ports)
+	}
+	if !namedPortsGenerated(ctx) {
+		t.Errorf("expected the NEXT and RANDOM ports to show the banner")
+	}
+
+	wantPorts := []string{
+		"127.0.0.1:" + strconv.Itoa(ports["api"]) + ":8080",
+		"127.0.0.1:5005:5005",
+		"127.0.0.1:12000:12000/udp",
+		"127.0.0.1:" + strconv.Itoa(ports["web"]) + ":" + strconv.Itoa(taken),
+	}
+	if got := configTablePorts(ctx); !reflect.DeepEqual(got, wantPorts) {
+		t.Errorf("configTablePorts() = %q, want %q", got, wantPorts)
+	}
+}
+
+func TestPortDetermination_DuplicateHostPort(t *testing.T) {
+	builder := &appctx.AppContextBuilder{Cmds: ilist.NewAppendableList[ilist.List[string]]()}
+	builder.Config.Port = "12000"
+	builder.Config.Ports = map[string]appctx.PortMapping{"web": "12000:3000"}
+
+	_, err := PortDetermination(builder.Build())
+
+	var portErr *DuplicateHostPortError
+	if !errors.As(err, &portErr) {
+		t.Fatalf("expected DuplicateHostPortError, got %v", err)
+	}
+	want := "Error: host port 12000 of ports.web is already used by the booth port (use another port, NEXT or RANDOM)."
+	if got := portErr.CliMessage(); got != want {
+		t.Errorf("CliMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestPortDetermination_DuplicatePortEnv(t *testing.T) {
+	builder := &appctx.AppContextBuilder{Cmds: ilist.NewAppendableList[ilist.List[string]]()}
+	builder.Config.Port = "12000"
+	builder.Config.Ports = map[string]appctx.PortMapping{"dev-server": "13000:3000", "dev_server": "13001:3001"}
+
+	_, err := PortDetermination(builder.Build())
+
+	var envErr *DuplicatePortEnvError
+	if !errors.As(err, &envErr) {
+		t.Fatalf("expected DuplicatePortEnvError, got %v", err)
+	}
+	want := "Error: ports.dev-server and ports.dev_server are both exported as CB_PORT_DEV_SERVER (rename one of them)."
+	if got := envErr.CliMessage(); got != want {
+		t.Errorf("CliMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestNamedPortEnv(t *testing.T) {
+	for name, want := range map[string]string{"web": "CB_PORT_WEB", "dev-server": "CB_PORT_DEV_SERVER"} {
+		if got := namedPortEnv(name); got != want {
+			t.Errorf("namedPortEnv(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestBoothRunner_AggregatesCheckErrors(t *testing.T) {
+	builder := &appctx.AppContextBuilder{
+		CommonArgs: ilist.NewAppendableList[ilist.List[string]](),
+		BuildArgs:  ilist.NewAppendableList[ilist.List[string]](),
+		RunArgs:    ilist.NewAppendableList[ilist.List[string]](),
+		Cmds:       ilist.NewAppendableList[ilist.List[string]](),
+	}
+	builder.Config.Variant = "bogus"
+	builder.Config.EnvFile = filepath.Join(t.TempDir(), "missing.env")
+	builder.Config.Port = "not-a-port"
+	builder.Config.Dryrun = nillable.NewNillableBool(true)
+
+	// The image stage is skipped after the variant fails, so no docker command runs
+	err := NewBoothRunner(builder.Build()).Run()
+
+	var variantErr *UnknownVariantError
+	var envErr *EnvFileMissingError
+	var portErr *InvalidPortError
+	if !errors.As(err, &variantErr) {
+		t.Errorf("expected UnknownVariantError in %v", err)
+	}
+	if !errors.As(err, &envErr) {
+		t.Errorf("expected EnvFileMissingError in %v", err)
+	}
+	if !errors.As(err, &portErr) {
+		t.Errorf("expected InvalidPortError in %v", err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 3 {
+		t.Errorf("expected 3 joined errors, got %v", err)
+	}
+}
+
+func TestBoothRunner_SingleErrorNamesStage(t *testing.T) {
+	builder := &appctx.AppContextBuilder{
+		CommonArgs: ilist.NewAppendableList[ilist.List[string]](),
+		Cmds:       ilist.NewAppendableList[ilist.List[string]](),
+	}
+	builder.Config.Variant = "bogus"
+	builder.Config.EnvFile = "-"
+	builder.Config.Port = "10000"
+
+	err := NewBoothRunner(builder.Build()).Run()
+
+	stageErr, ok := err.(*StageError)
+	if !ok {
+		t.Fatalf("expected *StageError, got %T: %v", err, err)
+	}
+	if stageErr.Stage != StageValidateVariant {
+		t.Errorf("Stage = %q, want %q", stageErr.Stage, StageValidateVariant)
+	}
+	if _, ok := stageErr.Err.(*UnknownVariantError); !ok {
+		t.Errorf("expected *UnknownVariantError, got %T: %v", stageErr.Err, stageErr.Err)
+	}
+}
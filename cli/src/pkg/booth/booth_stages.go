@@ -0,0 +1,203 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+// Names of the default stages of BoothRunner.Run (in order).
+const (
+	StageValidateVariant   = "validate-variant"
+	StageEnsureDockerImage = "ensure-docker-image"
+	StageApplyEnvFile      = "apply-env-file"
+	StagePortDetermination = "port-determination"
+	StageShowPortBanner    = "show-port-banner"
+	StageShowDebugBanner   = "show-debug-banner"
+	StageSetupDind         = "setup-dind"
+	StagePrepareRunMode    = "prepare-run-mode"
+	StagePrepareCommonArgs = "prepare-common-args"
+	StageRun               = "run"
+	StageWaitReady         = "wait-ready"
+)
+
+// Stage is one step of the booth run pipeline.
+type Stage interface {
+	// Name identifies the stage in verbose output, errors and `before-<name>`/`after-<name>` hooks.
+	Name() string
+	// Run performs the stage and returns the updated AppContext.
+	Run(ctx appctx.AppContext) (appctx.AppContext, error)
+	// Skip reports whether the stage (and its hooks) should not run for the given context.
+	Skip(ctx appctx.AppContext) bool
+}
+
+// FuncStage is a Stage made of functions.
+// SkipFunc is optional; a CheckOnly stage does not touch docker, so it still runs after an earlier stage failed.
+type FuncStage struct {
+	StageName string
+	RunFunc   func(appctx.AppContext) (appctx.AppContext, error)
+	SkipFunc  func(appctx.AppContext) bool
+	CheckOnly bool
+}
+
+// NewStage creates a stage with the given name and run function.
+func NewStage(name string, run func(appctx.AppContext) (appctx.AppContext, error)) *FuncStage {
+	return &FuncStage{StageName: name, RunFunc: run}
+}
+
+func (stage *FuncStage) Name() string {
+	return stage.StageName
+}
+
+func (stage *FuncStage) Run(ctx appctx.AppContext) (appctx.AppContext, error) {
+	return stage.RunFunc(ctx)
+}
+
+func (stage *FuncStage) Skip(ctx appctx.AppContext) bool {
+	return stage.SkipFunc != nil && stage.SkipFunc(ctx)
+}
+
+// IsCheckOnly reports whether the stage still runs after an earlier stage failed.
+func (stage *FuncStage) IsCheckOnly() bool {
+	return stage.CheckOnly
+}
+
+// isCheckOnlyStage reports whether a stage declares itself check-only (see FuncStage.CheckOnly).
+func isCheckOnlyStage(stage Stage) bool {
+	checkOnly, ok := stage.(interface{ IsCheckOnly() bool })
+	return ok && checkOnly.IsCheckOnly()
+}
+
+// StageRegistry is the ordered list of stages run by BoothRunner.
+type StageRegistry struct {
+	stages []Stage
+}
+
+// NewStageRegistry creates a registry with the given stages (in order).
+func NewStageRegistry(stages ...Stage) *StageRegistry {
+	return &StageRegistry{stages: append([]Stage{}, stages...)}
+}
+
+// DefaultStageRegistry creates a registry with the standard booth stages.
+func DefaultStageRegistry() *StageRegistry {
+	return NewStageRegistry(
+		&FuncStage{StageName: StageValidateVariant, RunFunc: ValidateVariant, CheckOnly: true},
+		&FuncStage{StageName: StageEnsureDockerImage, RunFunc: EnsureDockerImage},
+		&FuncStage{StageName: StageApplyEnvFile, RunFunc: ApplyEnvFile, CheckOnly: true},
+		&FuncStage{StageName: StagePortDetermination, RunFunc: PortDetermination, CheckOnly: true},
+		&FuncStage{StageName: StageShowPortBanner, RunFunc: ShowPortBanner},
+		&FuncStage{StageName: StageShowDebugBanner, RunFunc: ShowDebugBanner, SkipFunc: notVerbose},
+		&FuncStage{StageName: StageSetupDind, RunFunc: SetupDind, SkipFunc: noDind},
+		&FuncStage{StageName: StagePrepareRunMode, RunFunc: PrepareRunMode},
+		&FuncStage{StageName: StagePrepareCommonArgs, RunFunc: PrepareCommonArgs},
+		&FuncStage{StageName: StageRun, RunFunc: runBooth},
+		&FuncStage{StageName: StageWaitReady, RunFunc: WaitReady, SkipFunc: skipWaitReady},
+	)
+}
+
+func notVerbose(ctx appctx.AppContext) bool { return !ctx.Verbose() }
+func noDind(ctx appctx.AppContext) bool     { return !ctx.Dind() }
+
+// runBooth creates the booth with the prepared context and runs it.
+// It returns a HostPortTakenError when docker failed before the container ran because a generated host port
+// was taken since it was determined (not with DinD: the sidecar publishes the ports, see SetupDind).
+func runBooth(ctx appctx.AppContext) (appctx.AppContext, error) {
+	running := releasePortsWhenRunning(ctx)
+	openWhenReady(ctx)
+	err := NewBooth(ctx).Run(ctx.RunMode())
+	if err != nil && !running.Load() && !ctx.Dind() && isDockerRunFailure(err) {
+		if port := takenGeneratedPort(ctx); port != 0 {
+			removeCreatedContainer(ctx, ctx.Name())
+			return ctx, &HostPortTakenError{Port: port, Err: err}
+		}
+	}
+	return ctx, err
+}
+
+// Stages returns the stages in order.
+func (registry *StageRegistry) Stages() []Stage {
+	return append([]Stage{}, registry.stages...)
+}
+
+// Find returns the stage with the given name.
+func (registry *StageRegistry) Find(name string) (Stage, bool) {
+	index := registry.indexOf(name)
+	if index < 0 {
+		return nil, false
+	}
+	return registry.stages[index], true
+}
+
+// Add appends a stage at the end of the pipeline.
+func (registry *StageRegistry) Add(stage Stage) error {
+	return registry.insertAt(len(registry.stages), stage)
+}
+
+// InsertBefore adds a stage right before the named stage.
+func (registry *StageRegistry) InsertBefore(name string, stage Stage) error {
+	index := registry.indexOf(name)
+	if index < 0 {
+		return fmt.Errorf("unknown stage '%s'", name)
+	}
+	return registry.insertAt(index, stage)
+}
+
+// InsertAfter adds a stage right after the named stage.
+func (registry *StageRegistry) InsertAfter(name string, stage Stage) error {
+	index := registry.indexOf(name)
+	if index < 0 {
+		return fmt.Errorf("unknown stage '%s'", name)
+	}
+	return registry.insertAt(index+1, stage)
+}
+
+// Replace swaps the named stage for another one.
+func (registry *StageRegistry) Replace(name string, stage Stage) error {
+	index := registry.indexOf(name)
+	if index < 0 {
+		return fmt.Errorf("unknown stage '%s'", name)
+	}
+	if other := registry.indexOf(stage.Name()); other >= 0 && other != index {
+		return fmt.Errorf("stage '%s' already exists", stage.Name())
+	}
+	registry.stages[index] = stage
+	return nil
+}
+
+// Remove takes the named stage out of the pipeline.
+func (registry *StageRegistry) Remove(name string) error {
+	index := registry.indexOf(name)
+	if index < 0 {
+		return fmt.Errorf("unknown stage '%s'", name)
+	}
+	registry.stages = append(registry.stages[:index], registry.stages[index+1:]...)
+	return nil
+}
+
+func (registry *StageRegistry) insertAt(index int, stage Stage) error {
+	if registry.indexOf(stage.Name()) >= 0 {
+		return fmt.Errorf("stage '%s' already exists", stage.Name())
+	}
+	registry.stages = append(registry.stages[:index], append([]Stage{stage}, registry.stages[index:]...)...)
+	return nil
+}
+
+func (registry *StageRegistry) indexOf(name string) int {
+	key := stageKey(name)
+	for index, stage := range registry.stages {
+		if stageKey(stage.Name()) == key {
+			return index
+		}
+	}
+	return -1
+}
+
+// stageKey normalizes a stage name so that "prepare-common-args", "PrepareCommonArgs" and "prepare_common_args" match.
+func stageKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
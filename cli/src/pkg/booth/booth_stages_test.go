@@ -0,0 +1,131 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func recordingStage(name string, calls *[]string) *FuncStage {
+	return NewStage(name, func(ctx appctx.AppContext) (appctx.AppContext, error) {
+		*calls = append(*calls, name)
+		return ctx, nil
+	})
+}
+
+func registryNames(registry *StageRegistry) []string {
+	names := []string{}
+	for _, stage := range registry.Stages() {
+		names = append(names, stage.Name())
+	}
+	return names
+}
+
+func TestStageRegistry_Edit(t *testing.T) {
+	var calls []string
+	registry := NewStageRegistry(recordingStage("first", &calls), recordingStage("last", &calls))
+
+	if err := registry.InsertBefore("last", recordingStage("middle", &calls)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.InsertAfter("Last", recordingStage("extra", &calls)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Remove("first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"middle", "last", "extra"}
+	if got := registryNames(registry); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := registry.Add(recordingStage("middle", &calls)); err == nil {
+		t.Error("expected an error for a duplicated stage")
+	}
+	if err := registry.InsertBefore("no-such-stage", recordingStage("other", &calls)); err == nil {
+		t.Error("expected an error for an unknown stage")
+	}
+}
+
+func TestDefaultStageRegistry_Names(t *testing.T) {
+	want := []string{
+		StageValidateVariant, StageEnsureDockerImage, StageApplyEnvFile, StagePortDetermination, StageShowPortBanner,
+		StageShowDebugBanner, StageSetupDind, StagePrepareRunMode, StagePrepareCommonArgs, StageRun, StageWaitReady,
+	}
+	if got := registryNames(DefaultStageRegistry()); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBoothRunner_HooksAndSkip(t *testing.T) {
+	code := t.TempDir()
+	record := filepath.Join(code, "record.txt")
+
+	var calls []string
+	skipped := recordingStage("skipped", &calls)
+	skipped.SkipFunc = func(appctx.AppContext) bool { return true }
+	registry := NewStageRegistry(recordingStage("first", &calls), skipped, recordingStage("second", &calls))
+
+	builder := &appctx.AppContextBuilder{}
+	builder.Config.Code = nillable.NewNillableString(code)
+	builder.Config.Hooks = map[string]string{
+		"before-first":   `echo "$CB_HOOK_NAME" >> record.txt`,
+		"after-Second":   `echo "$CB_HOOK_NAME ($CB_HOOK_STAGE)" >> record.txt`,
+		"before-skipped": `echo skipped >> record.txt`,
+	}
+
+	if err := NewBoothRunnerWithStages(builder.Build(), registry).Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"first", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	content, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatalf("hooks did not run: %v", err)
+	}
+	if got, want := string(content), "before-first\nafter-second (second)\n"; got != want {
+		t.Errorf("hooks output = %q, want %q", got, want)
+	}
+}
+
+func TestBoothRunner_FailingHookStopsTheRun(t *testing.T) {
+	var calls []string
+	registry := NewStageRegistry(recordingStage("first", &calls), recordingStage("second", &calls))
+
+	builder := &appctx.AppContextBuilder{}
+	builder.Config.Code = nillable.NewNillableString(t.TempDir())
+	builder.Config.Hooks = map[string]string{"after-first": "exit 3"}
+
+	err := NewBoothRunnerWithStages(builder.Build(), registry).Run()
+
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Hook != "after-first" {
+		t.Fatalf("expected a HookError for after-first, got %v", err)
+	}
+	if want := []string{"first"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestBoothRunner_UnknownHook(t *testing.T) {
+	builder := &appctx.AppContextBuilder{}
+	builder.Config.Hooks = map[string]string{"before-no-such-stage": "true"}
+
+	err := NewBoothRunner(builder.Build()).Run()
+	if err == nil || !strings.Contains(err.Error(), "unknown stage 'no-such-stage'") {
+		t.Errorf("expected an unknown stage error, got %v", err)
+	}
+}
@@ -9,11 +9,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	boothinit "github.com/nawaman/codingbooth/src/pkg/booth/init"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
 	"github.com/nawaman/codingbooth/src/pkg/ilist"
 	"github.com/nawaman/codingbooth/src/pkg/nillable"
 )
@@ -1050,6 +1054,71 @@ func TestBooth_Run_CommandMode(t *testing.T) {
 	}
 }
 
+// TestBoothRunner_CommandsFromConfigToml runs the whole pipeline (not in dryrun) against a recording docker client.
+func TestBoothRunner_CommandsFromConfigToml(t *testing.T) {
+	code := t.TempDir()
+	outcome := boothinit.RunInitializeAppContext(t, boothinit.TestInput{
+		Args: []string{"--code", code, "--config", ".booth/config.toml"},
+		TomlFiles: []boothinit.TomlFile{{
+			Path: ".booth/config.toml",
+			Content: `
+name     = "from-toml"
+port     = "12000"
+variant  = "base"
+run-args = ["-e", "FOO=bar"]
+`,
+		}},
+		Timezone: "UTC",
+		HostUID:  "1000",
+		HostGID:  "1000",
+	})
+	if outcome.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", outcome.Err)
+	}
+
+	client := docker.NewRecordingDockerClient()
+	builder := outcome.Ctx.ToBuilder()
+	builder.Docker = client
+
+	oldStdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	err := NewBoothRunner(builder.Build()).Run()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	image := "nawaman/codingbooth:base-latest"
+	configFile := filepath.Join(outcome.CodeDir, ".booth", "config.toml")
+	want := []string{
+		"docker image inspect --format {{.Id}} " + image,
+		"docker image inspect " + image,
+		strings.Join([]string{
+			"docker run -i --rm --name from-toml -e HOST_UID=1000 -e HOST_GID=1000",
+			"-v " + code + ":/home/coder/code -w /home/coder/code -p 127.0.0.1:12000:10000",
+			"--label cb.managed=true --label cb.project=" + filepath.Base(code) + " --label cb.variant=base",
+			"--label cb.code-path=" + code + " --label cb.port=12000 --label cb.created-at=<time> --label cb.version=latest",
+			"-e CB_SETUPS=/opt/codingbooth/setups -e CB_CONTAINER_NAME=from-toml -e CB_DAEMON=false -e CB_HOST_PORT=12000",
+			"-e CB_IMAGE_NAME=" + image + " -e CB_RUNMODE=FOREGROUND -e CB_VARIANT_TAG=base -e CB_VERBOSE=false",
+			"-e CB_VERSION_TAG=latest -e CB_CODE_PATH=" + code + " -e CB_CODE_PORT=10000 -e CB_VERSION=latest",
+			"-e CB_CONFIG_FILE=" + configFile + " -e CB_SCRIPT_NAME=booth -e CB_SCRIPT_DIR=" + outcome.CodeDir,
+			"-e CB_LIB_DIR=" + filepath.Join(outcome.CodeDir, "libs") + " -e CB_KEEP_ALIVE=false -e CB_SILENCE_BUILD=false",
+			"-e CB_PULL=false -e CB_DIND=false -e CB_DOCKERFILE= -e CB_PROJECT_NAME=" + filepath.Base(code),
+			"-e CB_TIMEZONE=UTC -e CB_PORT=12000 -e CB_ENV_FILE= -e CB_HOST_UID=1000 -e CB_HOST_GID=1000",
+			"--pull=never -e FOO=bar -e TZ=UTC " + image,
+		}, " "),
+	}
+
+	createdAt := regexp.MustCompile(`cb\.created-at=\S+`)
+	got := []string{}
+	for _, command := range client.Commands() {
+		got = append(got, createdAt.ReplaceAllString(command, "cb.created-at=<time>"))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("docker commands:\n got: %q\nwant: %q", got, want)
+	}
+}
+
 func normalizeOutput(s string) string {
 	return strings.ReplaceAll(s, " \\\n    ", " ")
 }
@@ -0,0 +1,75 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// configTableArgs translates the [[mounts]], [env] and [ports] tables of the config into docker run arguments.
+// Optional mounts whose source does not exist are skipped with a warning.
+// The ports are left out with DinD (they are published by the sidecar; see configTablePorts).
+func configTableArgs(ctx appctx.AppContext, hostHome string) []ilist.List[string] {
+	args := []ilist.List[string]{}
+
+	for _, mount := range ctx.Mounts() {
+		source := mount.Source
+		isVolume := isVolumeName(source)
+		if !isVolume && !filepath.IsAbs(source) {
+			source = filepath.Join(ctx.Code(), source)
+		}
+		if _, err := os.Stat(source); err != nil && mount.Optional && !isVolume {
+			fmt.Printf("⚠️  Skipping optional mount (source not found): %s\n", mount.Source)
+			continue
+		}
+
+		target, mode := mount.MountTarget(source, hostHome)
+		volume := source + ":" + target
+		if mode == "ro" {
+			volume += ":ro"
+		}
+		args = append(args, ilist.NewList[string]("-v", volume))
+	}
+
+	env := ctx.Env()
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		args = append(args, ilist.NewList[string]("-e", name+"="+env[name]))
+	}
+
+	if !ctx.Dind() {
+		for _, port := range configTablePorts(ctx) {
+			args = append(args, ilist.NewList[string]("-p", port))
+		}
+	}
+	return args
+}
+
+// configTablePorts returns the docker -p values of the [ports] table, ordered by name,
+// on the host ports allocated by PortDetermination (for NEXT and RANDOM) and the bind address
+// (unless the entry has its own host address).
+func configTablePorts(ctx appctx.AppContext) []string {
+	ports := ctx.Ports()
+	namedPorts := ctx.NamedPorts()
+	address := bindAddress(ctx)
+	published := []string{}
+	for _, name := range slices.Sorted(maps.Keys(ports)) {
+		published = append(published, bindPortMapping(address, ports[name].WithHostPort(namedPorts[name]).Publish()))
+	}
+	return published
+}
+
+// isVolumeName tells if a mount source is a docker named volume (e.g. "pg-data") instead of a host path.
+// Relative host paths need a "/" (e.g. "./cache") to be resolved from the code folder.
+func isVolumeName(source string) bool {
+	return source != "" && source != "." && source != ".." && !strings.ContainsAny(source, `/\`)
+}
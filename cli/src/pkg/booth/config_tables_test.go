@@ -0,0 +1,67 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+func TestConfigTableArgs(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".claude"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+
+	builder := engineTestContext("docker")
+	// As read from the config (the loader expands ~)
+	builder.Config.Mounts = []appctx.Mount{
+		{Source: home + "/.claude", Seed: true, Optional: true},
+		{Source: home + "/.missing", Seed: true, Optional: true},
+		{Source: "/var/data", Target: "/data", Mode: "ro"},
+		{Source: "./cache", Target: "/cache"},
+		{Source: "pg-data", Target: "/var/lib/postgresql/data", Optional: true},
+	}
+	builder.Config.Env = map[string]string{"EDITOR": "vim", "API_URL": "http://localhost"}
+	builder.Config.Ports = map[string]appctx.PortMapping{"web": "3000", "db": "127.0.0.1:5433:5432"}
+
+	var got []string
+	for _, args := range configTableArgs(builder.Build(), home) {
+		got = append(got, strings.Join(args.Slice(), " "))
+	}
+	want := []string{
+		"-v " + home + "/.claude:/etc/cb-home-seed/.claude:ro",
+		"-v /var/data:/data:ro",
+		"-v /home/user/my-project/cache:/cache",
+		"-v pg-data:/var/lib/postgresql/data",
+		"-e API_URL=http://localhost",
+		"-e EDITOR=vim",
+		"-p 127.0.0.1:5433:5432",
+		"-p 127.0.0.1:3000:3000",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("configTableArgs() =\n%q\nwant\n%q", got, want)
+	}
+
+	// Published on all interfaces on opt-in (the entry with its own address is kept)
+	builder.Config.BindAddress = "0.0.0.0"
+	if got := configTablePorts(builder.Build()); !reflect.DeepEqual(got, []string{"127.0.0.1:5433:5432", "3000:3000"}) {
+		t.Errorf("configTablePorts() with 0.0.0.0 = %q", got)
+	}
+
+	// With DinD, the ports are published by the sidecar
+	builder.Config.Dind = true
+	for _, args := range configTableArgs(builder.Build(), home) {
+		if args.At(0) == "-p" {
+			t.Errorf("unexpected port with DinD: %v", args.Slice())
+		}
+	}
+}
@@ -14,9 +14,9 @@ import (
 )
 
 // ShowDebugBanner prints debug information if verbose mode is enabled.
-func ShowDebugBanner(ctx appctx.AppContext) appctx.AppContext {
+func ShowDebugBanner(ctx appctx.AppContext) (appctx.AppContext, error) {
 	if !ctx.Verbose() {
-		return ctx
+		return ctx, nil
 	}
 
 	fmt.Println()
@@ -61,7 +61,7 @@ func ShowDebugBanner(ctx appctx.AppContext) appctx.AppContext {
 		fmt.Println()
 	}
 
-	return ctx
+	return ctx, nil
 }
 
 // listOfArgsToString converts a list of arguments to a string representation.
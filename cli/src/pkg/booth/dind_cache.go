@@ -0,0 +1,119 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// LabelDindCache is stamped on the DinD cache volumes with their mode (project or shared);
+// the project ones also carry LabelProject.
+const LabelDindCache = "cb.dind-cache"
+
+// dindCacheTarget is where the DinD sidecar keeps its images, containers and volumes.
+const dindCacheTarget = "/var/lib/docker"
+
+// dindCachePrefix is the name of the shared DinD cache volume and the prefix of the project ones.
+const dindCachePrefix = "cb-dind-cache"
+
+var nonVolumeNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// dindCacheMode returns the dind-cache mode of the booth (project by default).
+func dindCacheMode(ctx appctx.AppContext) string {
+	switch mode := ctx.DindCache(); mode {
+	case "shared", "none":
+		return mode
+	}
+	return "project"
+}
+
+// dindCacheVolume returns the volume of the DinD image cache of the booth ("" with dind-cache = "none"):
+// cb-dind-cache-<project> for a project cache, cb-dind-cache for the cache shared by all the projects.
+func dindCacheVolume(ctx appctx.AppContext) string {
+	switch dindCacheMode(ctx) {
+	case "none":
+		return ""
+	case "shared":
+		return dindCachePrefix
+	}
+	return dindCachePrefix + "-" + strings.Trim(nonVolumeNameChars.ReplaceAllString(ctx.ProjectName(), "-"), "-.")
+}
+
+// dindCacheArgs returns the -v arguments mounting the DinD image cache volume on the sidecar's /var/lib/docker,
+// creating the volume (with its labels) the first time, so images pulled in the sidecar survive it.
+// Nothing is mounted with dind-cache = "none" or when a running container already uses the volume:
+// two docker daemons cannot share the same /var/lib/docker.
+func dindCacheArgs(ctx appctx.AppContext) []string {
+	volume := dindCacheVolume(ctx)
+	if volume == "" {
+		return nil
+	}
+
+	client := ctx.Docker()
+	flags := docker.DockerFlags{
+		Dryrun:  ctx.Dryrun(),
+		Verbose: ctx.Verbose(),
+		Silent:  true,
+	}
+
+	output, err := client.Ps(flags, ilist.NewList(ilist.NewList("-q", "--filter", "volume="+volume)))
+	if err == nil && strings.TrimSpace(output) != "" {
+		fmt.Printf("⚠️  The DinD cache '%s' is used by another running sidecar; this one starts without it.\n", volume)
+		return nil
+	}
+
+	output, err = client.Output(flags, "volume", ilist.NewList(ilist.NewList("inspect", "--format", "{{.Name}}", volume)))
+	if err != nil || strings.TrimSpace(output) == "" {
+		mode := dindCacheMode(ctx)
+		create := []string{"create", "--label", LabelManaged + "=true", "--label", LabelDindCache + "=" + mode}
+		if mode == "project" {
+			create = append(create, "--label", LabelProject+"="+ctx.ProjectName())
+		}
+		if _, err := client.Output(flags, "volume", ilist.NewList(ilist.NewListFromSlice(append(create, volume)))); err != nil {
+			fmt.Printf("⚠️  Could not create the DinD cache '%s' (%v); the sidecar starts without it.\n", volume, err)
+			return nil
+		}
+	}
+
+	return []string{"-v", volume + ":" + dindCacheTarget}
+}
+
+// PruneDindCache removes the DinD cache volumes (of all the projects and the shared one, or only the ones of
+// the given project) that no container uses, and returns the removed volumes and the ones kept because
+// a container (e.g. a sidecar kept with --keep-alive) still uses them.
+func PruneDindCache(client docker.DockerClient, flags docker.DockerFlags, project string) ([]string, []string, error) {
+	silentFlags := flags
+	silentFlags.Silent = true
+
+	filters := []string{"ls", "-q", "--filter", "label=" + LabelDindCache}
+	if project != "" {
+		filters = append(filters, "--filter", "label="+LabelProject+"="+project)
+	}
+	output, err := client.Output(silentFlags, "volume", ilist.NewList(ilist.NewListFromSlice(filters)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the DinD cache volumes: %w", err)
+	}
+
+	removed := []string{}
+	inUse := []string{}
+	for _, volume := range strings.Fields(output) {
+		users, err := client.Ps(silentFlags, ilist.NewList(ilist.NewList("-aq", "--filter", "volume="+volume)))
+		if err == nil && strings.TrimSpace(users) != "" {
+			inUse = append(inUse, volume)
+			continue
+		}
+		if err := client.Command(silentFlags, "volume", ilist.NewList(ilist.NewList("rm", volume))); err != nil {
+			return removed, inUse, fmt.Errorf("failed to remove the DinD cache '%s': %w", volume, err)
+		}
+		removed = append(removed, volume)
+	}
+	return removed, inUse, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+)
+
+func TestDindCacheVolume(t *testing.T) {
+	tests := []struct {
+		mode    string
+		project string
+		want    string
+	}{
+		{"", "my-project", "cb-dind-cache-my-project"},
+		{"project", "My Project (2)", "cb-dind-cache-My-Project-2"},
+		{"shared", "my-project", "cb-dind-cache"},
+		{"none", "my-project", ""},
+	}
+	for _, tt := range tests {
+		builder := engineTestContext("docker")
+		builder.Config.DindCache = tt.mode
+		builder.Config.ProjectName = tt.project
+		if got := dindCacheVolume(builder.Build()); got != tt.want {
+			t.Errorf("dindCacheVolume(%q, %q) = %q, want %q", tt.mode, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestDindCacheArgs(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		if call.Subcommand == "volume" && call.Args[0] == "inspect" {
+			return "", errors.New("no such volume")
+		}
+		return "", nil
+	}
+	builder := engineTestContext("docker")
+	builder.Docker = client
+	builder.Config.ProjectName = "my-project"
+
+	args := dindCacheArgs(builder.Build())
+
+	if want := []string{"-v", "cb-dind-cache-my-project:/var/lib/docker"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("dindCacheArgs() = %q, want %q", args, want)
+	}
+	want := []string{
+		"docker ps -q --filter volume=cb-dind-cache-my-project",
+		"docker volume inspect --format {{.Name}} cb-dind-cache-my-project",
+		"docker volume create --label cb.managed=true --label cb.dind-cache=project --label cb.project=my-project cb-dind-cache-my-project",
+	}
+	if commands := client.Commands(); !reflect.DeepEqual(commands, want) {
+		t.Errorf("Commands() =\n%q\nwant\n%q", commands, want)
+	}
+}
+
+func TestDindCacheArgs_InUse(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		if call.Subcommand == "ps" {
+			// Another running sidecar on the shared cache
+			return "0123456789ab\n", nil
+		}
+		return "", nil
+	}
+	builder := engineTestContext("docker")
+	builder.Docker = client
+	builder.Config.DindCache = "shared"
+
+	if args := dindCacheArgs(builder.Build()); args != nil {
+		t.Errorf("expected no cache for a volume in use, got %q", args)
+	}
+	if commands := client.Commands(); len(commands) != 1 {
+		t.Errorf("expected only the usage check, got %q", commands)
+	}
+}
+
+func TestCleanupPreviousBoothInstances_KeepsVolumes(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		switch call.Subcommand {
+		case "ps":
+			return "0123456789ab\n", nil
+		case "network":
+			return "my-project-12000-net\n", nil
+		}
+		return "", nil
+	}
+	builder := engineTestContext("docker")
+	builder.Docker = client
+
+	cleanupPreviousBoothInstances(builder.Build(), "my-project")
+
+	for _, call := range client.Calls() {
+		if call.Subcommand == "volume" || (call.Subcommand == "rm" && (slices.Contains(call.Args, "-v") || slices.Contains(call.Args, "--volumes"))) {
+			t.Errorf("the cleanup must not remove volumes: %s", call)
+		}
+	}
+	if commands := strings.Join(client.Commands(), "\n"); !strings.Contains(commands, "docker rm -f 0123456789ab") {
+		t.Errorf("expected the leftover container to be removed, got:\n%s", commands)
+	}
+}
+
+func TestPruneDindCache(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		switch {
+		case call.Subcommand == "volume" && call.Args[0] == "ls":
+			return "cb-dind-cache-my-project\ncb-dind-cache\n", nil
+		case call.Subcommand == "ps" && slices.Contains(call.Args, "volume=cb-dind-cache"):
+			// A sidecar kept with --keep-alive
+			return "0123456789ab\n", nil
+		}
+		return "", nil
+	}
+
+	removed, inUse, err := PruneDindCache(client, docker.DockerFlags{}, "")
+	if err != nil {
+		t.Fatalf("PruneDindCache() returned error: %v", err)
+	}
+	if want := []string{"cb-dind-cache-my-project"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %q, want %q", removed, want)
+	}
+	if want := []string{"cb-dind-cache"}; !reflect.DeepEqual(inUse, want) {
+		t.Errorf("inUse = %q, want %q", inUse, want)
+	}
+	want := []string{
+		"docker volume ls -q --filter label=cb.dind-cache",
+		"docker ps -aq --filter volume=cb-dind-cache-my-project",
+		"docker volume rm cb-dind-cache-my-project",
+		"docker ps -aq --filter volume=cb-dind-cache",
+	}
+	if commands := client.Commands(); !reflect.DeepEqual(commands, want) {
+		t.Errorf("Commands() =\n%q\nwant\n%q", commands, want)
+	}
+}
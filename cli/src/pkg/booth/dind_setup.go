@@ -18,6 +18,7 @@ import (
 
 // cleanupPreviousBoothInstances cleans up any leftover containers and networks from previous booth runs.
 // This helps prevent port conflicts when restarting the booth.
+// Volumes are never removed (no `rm -v`): the DinD cache volume outlives the sidecars (see dindCacheArgs).
 func cleanupPreviousBoothInstances(ctx appctx.AppContext, projectName string) {
 	if ctx.Dryrun() {
 		return
@@ -30,17 +31,21 @@ func cleanupPreviousBoothInstances(ctx appctx.AppContext, projectName string) {
 		projectName + "-*-dind",        // DinD sidecar containers (e.g., project-10000-dind)
 	}
 
+	// Quiet flags: these checks are not printed even in verbose mode
+	client := ctx.Docker()
+	quiet := docker.DockerFlags{Silent: true}
+
 	for _, pattern := range patterns {
 		// Find containers matching the pattern
-		output, err := exec.Command("docker", "ps", "-aq", "--filter", "name=^"+pattern+"$").Output()
-		if err == nil && len(strings.TrimSpace(string(output))) > 0 {
-			containerIDs := strings.Fields(string(output))
+		output, err := client.Ps(quiet, ilist.NewList(ilist.NewList("-aq", "--filter", "name=^"+pattern+"$")))
+		if err == nil && len(strings.TrimSpace(output)) > 0 {
+			containerIDs := strings.Fields(output)
 			for _, id := range containerIDs {
 				if ctx.Verbose() {
 					fmt.Printf("Stopping leftover container: %s\n", id)
 				}
-				exec.Command("docker", "stop", id).Run()
-				exec.Command("docker", "rm", "-f", id).Run()
+				_ = client.Stop(quiet, ilist.NewList(ilist.NewList(id)))
+				_ = client.Command(quiet, "rm", ilist.NewList(ilist.NewList("-f", id)))
 			}
 		}
 
@@ -48,37 +53,37 @@ func cleanupPreviousBoothInstances(ctx appctx.AppContext, projectName string) {
 		if strings.Contains(pattern, "*") {
 			// Use filter with regex-like matching
 			filterPattern := strings.ReplaceAll(pattern, "*", ".*")
-			output, err = exec.Command("docker", "ps", "-aq", "--filter", "name="+filterPattern).Output()
-			if err == nil && len(strings.TrimSpace(string(output))) > 0 {
-				containerIDs := strings.Fields(string(output))
+			output, err = client.Ps(quiet, ilist.NewList(ilist.NewList("-aq", "--filter", "name="+filterPattern)))
+			if err == nil && len(strings.TrimSpace(output)) > 0 {
+				containerIDs := strings.Fields(output)
 				for _, id := range containerIDs {
 					// Get container name to log it
-					nameOutput, _ := exec.Command("docker", "inspect", "--format", "{{.Name}}", id).Output()
-					containerName := strings.TrimPrefix(strings.TrimSpace(string(nameOutput)), "/")
+					nameOutput, _ := client.Inspect(quiet, ilist.NewList(ilist.NewList("--format", "{{.Name}}", id)))
+					containerName := strings.TrimPrefix(strings.TrimSpace(nameOutput), "/")
 
 					if ctx.Verbose() {
 						fmt.Printf("Stopping leftover container: %s (%s)\n", containerName, id)
 					} else {
 						fmt.Printf("Cleaning up leftover container: %s\n", containerName)
 					}
-					exec.Command("docker", "stop", id).Run()
-					exec.Command("docker", "rm", "-f", id).Run()
+					_ = client.Stop(quiet, ilist.NewList(ilist.NewList(id)))
+					_ = client.Command(quiet, "rm", ilist.NewList(ilist.NewList("-f", id)))
 				}
 			}
 		}
 	}
 
 	// Find and remove any networks matching the project name pattern
-	output, err := exec.Command("docker", "network", "ls", "--filter", "name="+projectName, "--format", "{{.Name}}").Output()
-	if err == nil && len(strings.TrimSpace(string(output))) > 0 {
-		networks := strings.Fields(string(output))
+	output, err := client.Network(quiet, ilist.NewList(ilist.NewList("ls", "--filter", "name="+projectName, "--format", "{{.Name}}")))
+	if err == nil && len(strings.TrimSpace(output)) > 0 {
+		networks := strings.Fields(output)
 		for _, network := range networks {
 			// Only remove networks that look like booth networks (contain -net suffix)
 			if strings.HasSuffix(network, "-net") && strings.HasPrefix(network, projectName) {
 				if ctx.Verbose() {
 					fmt.Printf("Removing leftover network: %s\n", network)
 				}
-				exec.Command("docker", "network", "rm", network).Run()
+				_, _ = client.Network(quiet, ilist.NewList(ilist.NewList("rm", network)))
 			}
 		}
 	}
@@ -93,7 +98,7 @@ func createDindNetwork(ctx appctx.AppContext, networkName string) bool {
 		Verbose: ctx.Verbose(),
 		Silent:  true,
 	}
-	output, err := docker.DockerOutput(flags, "network", ilist.NewList(ilist.NewList("inspect", networkName)))
+	output, err := ctx.Docker().Network(flags, ilist.NewList(ilist.NewList("inspect", networkName)))
 	if err == nil && strings.TrimSpace(output) != "" {
 		// Network already exists (inspect returned data)
 		return false
@@ -105,7 +110,7 @@ func createDindNetwork(ctx appctx.AppContext, networkName string) bool {
 	}
 
 	flags.Silent = false
-	err = docker.Docker(flags, "network", ilist.NewList(ilist.NewList("create", networkName)))
+	err = ctx.Docker().Command(flags, "network", ilist.NewList(ilist.NewList("create", networkName)))
 	if err != nil {
 		fmt.Printf("Warning: failed to create network %s: %v\n", networkName, err)
 		return false
@@ -115,7 +120,7 @@ func createDindNetwork(ctx appctx.AppContext, networkName string) bool {
 }
 
 // startDindSidecar starts the DinD sidecar container if not already running.
-// extraPorts contains additional port mappings (e.g., "8080:8080") from run-args.
+// extraPorts contains additional port mappings (e.g., "127.0.0.1:8080:8080") from run-args and the [ports] table.
 // Returns an error if the sidecar fails to start.
 func startDindSidecar(ctx appctx.AppContext, dindName, dindNet string, hostPort int, extraPorts []string) error {
 	// Check if sidecar is already running
@@ -124,7 +129,7 @@ func startDindSidecar(ctx appctx.AppContext, dindName, dindNet string, hostPort
 		Verbose: ctx.Verbose(),
 		Silent:  true,
 	}
-	output, err := docker.DockerOutput(flags, "ps", ilist.NewList(ilist.NewList("--filter", fmt.Sprintf("name=^/%s$", dindName), "--format", "{{.Names}}")))
+	output, err := ctx.Docker().Ps(flags, ilist.NewList(ilist.NewList("--filter", fmt.Sprintf("name=^/%s$", dindName), "--format", "{{.Names}}")))
 
 	if err == nil && strings.TrimSpace(output) == dindName {
 		// Container is running
@@ -138,31 +143,35 @@ func startDindSidecar(ctx appctx.AppContext, dindName, dindNet string, hostPort
 		fmt.Printf("Starting DinD sidecar: %s\n", dindName)
 	}
 
-	// Detect if running on Docker Desktop
-	isDockerDesktop := isDockerDesktop(ctx)
+	// Detect if running in a desktop VM (Docker Desktop, podman machine, ...)
+	engine := docker.EngineOf(ctx.Engine())
+	isDesktop := engine.IsDesktop(ctx.Docker(), flags)
+
+	// Port mapping for the booth container (since booth shares DinD's network), on the bind address
+	portMapping := bindPortMapping(bindAddress(ctx), fmt.Sprintf("%d:10000", hostPort))
 
-	// Port mapping for the booth container (since booth shares DinD's network)
-	portMapping := fmt.Sprintf("%d:10000", hostPort)
+	// Keep the sidecar (no --rm) together with a kept-alive booth so 'start' can bring both back
+	args := []string{"-d"}
+	args = append(args, prepareKeepAliveArgs(ctx.KeepAlive())...)
 
-	var args []string
-	if isDockerDesktop {
-		// Docker Desktop: skip cgroup flags + /sys/fs/cgroup mount
-		args = []string{
-			"run", "-d", "--rm", "--privileged",
+	if isDesktop || !engine.HostCgroups {
+		// Desktop VM or rootless podman: skip cgroup flags + /sys/fs/cgroup mount
+		args = append(args,
+			"--privileged",
 			"--name", dindName,
 			"--network", dindNet,
 			"-p", portMapping,
-		}
+		)
 	} else {
 		// Native Linux: full flags
-		args = []string{
-			"run", "-d", "--rm", "--privileged",
+		args = append(args,
+			"--privileged",
 			"--cgroupns=host",
 			"-v", "/sys/fs/cgroup:/sys/fs/cgroup:rw",
 			"--name", dindName,
 			"--network", dindNet,
 			"-p", portMapping,
-		}
+		)
 	}
 
 	// Add extra port mappings from run-args
@@ -170,25 +179,20 @@ func startDindSidecar(ctx appctx.AppContext, dindName, dindNet string, hostPort
 		args = append(args, "-p", port)
 	}
 
+	// Keep the pulled images across runs (see dindCacheArgs)
+	args = append(args, dindCacheArgs(ctx)...)
+
 	// Add final args (env and image)
-	args = append(args, "-e", "DOCKER_TLS_CERTDIR=", "docker:dind")
+	args = append(args, "-e", "DOCKER_TLS_CERTDIR=", engine.Image("docker:dind"))
 
 	flags.Silent = false
-	err = docker.Docker(flags, args[0], ilist.NewList(ilist.NewListFromSlice(args[1:])))
+	err = ctx.Docker().Run(flags, ilist.NewList(ilist.NewListFromSlice(args)))
 	if err != nil {
 		return fmt.Errorf("failed to start DinD sidecar: %w", err)
 	}
 	return nil
 }
 
-// isDockerDesktop detects if running on Docker Desktop (macOS/Windows).
-func isDockerDesktop(ctx appctx.AppContext) bool {
-	// Run docker info and check for "Docker Desktop"
-	// This is a simplified version - in production you'd capture output
-	// For now, we'll assume native Linux (can be enhanced later)
-	return false
-}
-
 // waitForDindReady waits for the DinD daemon to become ready.
 func waitForDindReady(ctx appctx.AppContext, dindName, dindNet string) {
 	if ctx.Dryrun() {
@@ -207,7 +211,7 @@ func waitForDindReady(ctx appctx.AppContext, dindName, dindNet string) {
 			Verbose: ctx.Verbose(),
 			Silent:  true,
 		}
-		_, err := docker.DockerOutput(flags, "run", ilist.NewList(ilist.NewList("--rm", "--network", dindNet, "docker:cli",
+		_, err := ctx.Docker().Output(flags, "run", ilist.NewList(ilist.NewList("--rm", "--network", dindNet, docker.EngineOf(ctx.Engine()).Image("docker:cli"),
 			"-H", fmt.Sprintf("tcp://%s:2375", dindName), "version")))
 
 		if err == nil {
@@ -218,7 +222,7 @@ func waitForDindReady(ctx appctx.AppContext, dindName, dindNet string) {
 		time.Sleep(250 * time.Millisecond)
 	}
 
-	fmt.Printf("⚠️  DinD did not become ready. Check: docker logs %s\n", dindName)
+	fmt.Printf("⚠️  DinD did not become ready. Check: %s logs %s\n", docker.EngineOf(ctx.Engine()).Executable, dindName)
 }
 
 // extractPortFlags extracts -p and --publish flags from RunArgs and returns them as a slice of port mappings.
@@ -359,21 +363,21 @@ func parsePortFromMapping(mapping string) string {
 	return mapping // just a single port
 }
 
-// checkPortInUse checks if a port is in use and returns diagnostic information.
+// checkPortInUse checks if a port is in use on the bind address (or on all interfaces) and returns diagnostic information.
 // Returns nil if the port is free.
-func checkPortInUse(port string) *PortConflictError {
+func checkPortInUse(client docker.DockerClient, address string, port string) *PortConflictError {
 	// Try ss command first (more common on modern Linux)
 	output, err := exec.Command("ss", "-tlnp").Output()
 	if err == nil {
 		lines := strings.Split(string(output), "\n")
 		for _, line := range lines {
-			if strings.Contains(line, ":"+port+" ") || strings.Contains(line, ":"+port+"\t") {
+			if listensOn(line, address, port) {
 				processInfo := parseProcessFromSS(line)
 
 				// If ss couldn't identify the process, try to detect Docker
 				if processInfo == "unknown process" {
 					// First check if it's a running Docker container
-					containerName := getDockerContainerUsingPort(port)
+					containerName := getDockerContainerUsingPort(client, port)
 					if containerName != "" {
 						processInfo = fmt.Sprintf("Docker container '%s'", containerName)
 						suggestion := fmt.Sprintf(`This port is used by Docker container '%s'.
@@ -390,7 +394,7 @@ func checkPortInUse(port string) *PortConflictError {
 					}
 
 					// Check if it's an orphaned docker-proxy
-					if isDockerProxy(port) {
+					if isDockerProxy(client, port) {
 						processInfo = "docker-proxy (orphaned)"
 						return &PortConflictError{
 							Port:        port,
@@ -409,13 +413,24 @@ func checkPortInUse(port string) *PortConflictError {
 		}
 	}
 
-	// Fallback to lsof if ss didn't find anything
-	output, err = exec.Command("lsof", "-i", ":"+port).Output()
+	// Fallback to lsof if ss didn't find anything (keeping the header and the listening lines on the same interface;
+	// -P keeps the port numbers, and a client connection to the port is not a conflict)
+	output, err = exec.Command("lsof", "-n", "-P", "-iTCP:"+port, "-sTCP:LISTEN").Output()
 	if err == nil && len(output) > 0 {
-		return &PortConflictError{
-			Port:        port,
-			ProcessInfo: parseProcessFromLsof(string(output)),
-			Suggestion:  getSuggestionForPort(port, string(output)),
+		lines := strings.Split(string(output), "\n")
+		matching := lines[:1]
+		for _, line := range lines[1:] {
+			if strings.Contains(line, "(LISTEN)") && listensOn(line, address, port) {
+				matching = append(matching, line)
+			}
+		}
+		if len(matching) > 1 {
+			found := strings.Join(matching, "\n")
+			return &PortConflictError{
+				Port:        port,
+				ProcessInfo: parseProcessFromLsof(found),
+				Suggestion:  getSuggestionForPort(port, found),
+			}
 		}
 	}
 
@@ -424,11 +439,11 @@ func checkPortInUse(port string) *PortConflictError {
 
 // isDockerProxy checks if a port is held by a docker-proxy process or Docker container.
 // This is useful when ss -tlnp doesn't show process info (requires root).
-func isDockerProxy(port string) bool {
+func isDockerProxy(client docker.DockerClient, port string) bool {
 	// Check if any Docker container has this port mapped
-	output, err := exec.Command("docker", "ps", "--format", "{{.Ports}}").Output()
+	output, err := client.Ps(docker.DockerFlags{Silent: true}, ilist.NewList(ilist.NewList("--format", "{{.Ports}}")))
 	if err == nil {
-		lines := strings.Split(string(output), "\n")
+		lines := strings.Split(output, "\n")
 		for _, line := range lines {
 			// Port mappings look like "0.0.0.0:3000->3000/tcp" or ":::3000->3000/tcp"
 			if strings.Contains(line, ":"+port+"->") {
@@ -438,8 +453,8 @@ func isDockerProxy(port string) bool {
 	}
 
 	// Also check ps output for docker-proxy processes
-	output, err = exec.Command("bash", "-c", "ps aux 2>/dev/null | grep docker-proxy | grep -v grep | grep -E 'host-port[= ]"+port+"'").Output()
-	if err == nil && len(output) > 0 {
+	proxyOutput, err := exec.Command("bash", "-c", "ps aux 2>/dev/null | grep docker-proxy | grep -v grep | grep -E 'host-port[= ]"+port+"'").Output()
+	if err == nil && len(proxyOutput) > 0 {
 		return true
 	}
 
@@ -447,10 +462,10 @@ func isDockerProxy(port string) bool {
 }
 
 // getDockerContainerUsingPort returns the name of the Docker container using a port, or empty string.
-func getDockerContainerUsingPort(port string) string {
-	output, err := exec.Command("docker", "ps", "--format", "{{.Names}}\t{{.Ports}}").Output()
+func getDockerContainerUsingPort(client docker.DockerClient, port string) string {
+	output, err := client.Ps(docker.DockerFlags{Silent: true}, ilist.NewList(ilist.NewList("--format", "{{.Names}}\t{{.Ports}}")))
 	if err == nil {
-		lines := strings.Split(string(output), "\n")
+		lines := strings.Split(output, "\n")
 		for _, line := range lines {
 			if strings.Contains(line, ":"+port+"->") {
 				parts := strings.SplitN(line, "\t", 2)
@@ -525,7 +540,7 @@ func getSuggestionForPort(port string, processInfo string) string {
 // Returns the conflicting port and diagnostic message, or empty strings if no port conflict found.
 // Note: Docker's error message goes to stderr and isn't captured in the error object,
 // so we proactively check all ports rather than parsing the error message.
-func diagnosePortConflict(err error, hostPort int, extraPorts []string) (string, string) {
+func diagnosePortConflict(client docker.DockerClient, err error, address string, hostPort int, extraPorts []string) (string, string) {
 	if err == nil {
 		return "", ""
 	}
@@ -533,12 +548,15 @@ func diagnosePortConflict(err error, hostPort int, extraPorts []string) (string,
 	// Build list of all ports we're trying to bind
 	portsToCheck := []string{fmt.Sprintf("%d", hostPort)}
 	for _, p := range extraPorts {
-		portsToCheck = append(portsToCheck, parsePortFromMapping(p))
+		if port := parsePortFromMapping(p); port != "" {
+			// A single container port ("127.0.0.1::80") is published on a random host port
+			portsToCheck = append(portsToCheck, port)
+		}
 	}
 
-	// Check each port and find the one that's actually in use
+	// Check each port on the bind address and find the one that's actually in use
 	for _, port := range portsToCheck {
-		if conflict := checkPortInUse(port); conflict != nil {
+		if conflict := checkPortInUse(client, address, port); conflict != nil {
 			return port, fmt.Sprintf("Port %s is already in use by: %s\n\n   %s",
 				port, conflict.ProcessInfo, conflict.Suggestion)
 		}
@@ -15,7 +15,9 @@ import (
 )
 
 // EnsureDockerImage ensures the Docker image is available and returns updated AppContext.
-func EnsureDockerImage(ctx appctx.AppContext) appctx.AppContext {
+// Without an image or a Dockerfile, the setups of the config are built into a local image.
+// It returns a DockerfileNotFileError, ImageBuildError, ImagePullError or ImageNotFoundError on failure.
+func EnsureDockerImage(ctx appctx.AppContext) (appctx.AppContext, error) {
 	builder := ctx.ToBuilder()
 
 	// Step 1: Determine image mode
@@ -23,6 +25,7 @@ func EnsureDockerImage(ctx appctx.AppContext) appctx.AppContext {
 		// IMAGE_NAME is explicitly set
 		builder.ImageMode = "EXISTING"
 		builder.LocalBuild = false
+		warnIgnoredSetups(ctx, "--image")
 	} else {
 		// Normalize DOCKER_FILE
 		dockerFile := normalizeDockerFile(ctx)
@@ -31,11 +34,15 @@ func EnsureDockerImage(ctx appctx.AppContext) appctx.AppContext {
 		if dockerFile != "" {
 			// Validate it's a file
 			if !isFile(dockerFile) {
-				fmt.Fprintf(os.Stderr, "DOCKER_FILE (%s) is not a file.\n", dockerFile)
-				os.Exit(1)
+				return ctx, &DockerfileNotFileError{Path: dockerFile}
 			}
 			builder.ImageMode = "LOCAL-BUILD"
 			builder.LocalBuild = true
+			warnIgnoredSetups(ctx, "a Dockerfile (add RUN lines for them to it)")
+		} else if len(ctx.Setups()) > 0 {
+			// The Dockerfile is generated from the setups
+			builder.ImageMode = "SETUPS-BUILD"
+			builder.LocalBuild = true
 		} else {
 			builder.ImageMode = "PREBUILT"
 			builder.LocalBuild = false
@@ -47,7 +54,7 @@ func EnsureDockerImage(ctx appctx.AppContext) appctx.AppContext {
 
 	if ctx.Image() == "" {
 		builder = ctx.ToBuilder()
-		if ctx.ImageMode() == "LOCAL-BUILD" {
+		if ctx.LocalBuild() {
 			builder.Config.Image = fmt.Sprintf("codingbooth-local:%s-%s-%s",
 				ctx.ProjectName(), ctx.Variant(), ctx.Version())
 		} else {
@@ -59,19 +66,34 @@ func EnsureDockerImage(ctx appctx.AppContext) appctx.AppContext {
 	}
 
 	// Step 3: Build local image if needed
-	if ctx.LocalBuild() {
-		buildLocalImage(ctx)
+	if ctx.ImageMode() == "SETUPS-BUILD" {
+		contextDir, dockerfile, err := writeSetupsBuildContext(ctx)
+		if err != nil {
+			return ctx, &ImageBuildError{Image: ctx.Image(), Err: err}
+		}
+		defer os.RemoveAll(contextDir)
+		if err := buildLocalImage(ctx, dockerfile, contextDir, "setups: "+setupsNames(ctx.Setups())); err != nil {
+			return ctx, err
+		}
+	} else if ctx.LocalBuild() {
+		if err := buildLocalImage(ctx, ctx.Dockerfile(), ctx.Code(), ctx.Dockerfile()); err != nil {
+			return ctx, err
+		}
 	}
 
 	// Step 4: Pull image if needed (non-local-build only)
 	if !ctx.LocalBuild() {
-		pullImageIfNeeded(ctx)
+		if err := pullImageIfNeeded(ctx); err != nil {
+			return ctx, err
+		}
 	}
 
 	// Step 5: Final validation
-	validateImageExists(ctx)
+	if err := validateImageExists(ctx); err != nil {
+		return ctx, err
+	}
 
-	return ctx
+	return ctx, nil
 }
 
 // normalizeDockerFile normalizes the DOCKER_FILE path.
@@ -102,11 +124,18 @@ func normalizeDockerFile(ctx appctx.AppContext) string {
 	return ""
 }
 
-// buildLocalImage builds a local Docker image.
-func buildLocalImage(ctx appctx.AppContext) {
+// warnIgnoredSetups warns that the setups of the config are not installed with an image or a Dockerfile.
+func warnIgnoredSetups(ctx appctx.AppContext, reason string) {
+	if setups := ctx.Setups(); len(setups) > 0 {
+		fmt.Printf("⚠️  Ignoring setups (%s) with %s.\n", setupsNames(setups), reason)
+	}
+}
+
+// buildLocalImage builds a local Docker image from the Dockerfile and the build context folder.
+func buildLocalImage(ctx appctx.AppContext, dockerfile string, contextDir string, from string) error {
 	if !ctx.SilenceBuild() {
 		fmt.Fprintf(os.Stderr, "Info: building local image '%s' from '%s'...\n",
-			ctx.Image(), ctx.Dockerfile())
+			ctx.Image(), from)
 	}
 
 	if ctx.Verbose() {
@@ -118,7 +147,7 @@ func buildLocalImage(ctx appctx.AppContext) {
 	// Build arguments
 	args := ilist.NewList[ilist.List[string]]()
 	args = args.ExtendByLists(ilist.NewList(ilist.NewList(
-		"-f", ctx.Dockerfile(),
+		"-f", dockerfile,
 		"-t", ctx.Image(),
 	)))
 	args = args.ExtendByLists(ilist.NewList(ilist.NewList(
@@ -135,7 +164,7 @@ func buildLocalImage(ctx appctx.AppContext) {
 	args = args.ExtendByLists(ctx.BuildArgs())
 
 	// Add context path
-	args = args.ExtendByLists(ilist.NewList(ilist.NewList(ctx.Code())))
+	args = args.ExtendByLists(ilist.NewList(ilist.NewList(contextDir)))
 
 	// Build the image
 	flags := docker.DockerFlags{
@@ -143,15 +172,15 @@ func buildLocalImage(ctx appctx.AppContext) {
 		Verbose: ctx.Verbose(),
 		Silent:  ctx.SilenceBuild(),
 	}
-	err := docker.DockerBuild(flags, args)
+	err := ctx.Docker().Build(flags, args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to build image\n")
-		os.Exit(1)
+		return &ImageBuildError{Image: ctx.Image(), Err: err}
 	}
+	return nil
 }
 
 // pullImageIfNeeded pulls the Docker image if needed.
-func pullImageIfNeeded(ctx appctx.AppContext) {
+func pullImageIfNeeded(ctx appctx.AppContext) error {
 	imageName := ctx.Image()
 
 	if ctx.Pull() {
@@ -168,10 +197,9 @@ func pullImageIfNeeded(ctx appctx.AppContext) {
 			Verbose: ctx.Verbose(),
 			Silent:  true,
 		}
-		err := docker.Docker(flags, "pull", ilist.NewList(ilist.NewList(imageName)))
+		err := ctx.Docker().Pull(flags, imageName)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to pull '%s'\n", imageName)
-			os.Exit(1)
+			return &ImagePullError{Image: imageName, Err: err}
 		}
 
 		if ctx.Verbose() {
@@ -184,7 +212,7 @@ func pullImageIfNeeded(ctx appctx.AppContext) {
 			Verbose: ctx.Verbose(),
 			Silent:  true,
 		}
-		err := docker.Docker(flags, "image", ilist.NewList(ilist.NewList("inspect", "--format", "{{.Id}}", imageName)))
+		err := ctx.Docker().Command(flags, "image", ilist.NewList(ilist.NewList("inspect", "--format", "{{.Id}}", imageName)))
 		if err != nil {
 			// Image not found locally, pull it
 			fmt.Fprintf(os.Stderr, "Info: pulling image '%s' (not found locally)...\n", imageName)
@@ -197,10 +225,9 @@ func pullImageIfNeeded(ctx appctx.AppContext) {
 				Verbose: ctx.Verbose(),
 				Silent:  true,
 			}
-			err = docker.Docker(flags, "pull", ilist.NewList(ilist.NewList(imageName)))
+			err = ctx.Docker().Pull(flags, imageName)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to pull '%s'\n", imageName)
-				os.Exit(1)
+				return &ImagePullError{Image: imageName, Err: err}
 			}
 
 			if ctx.Verbose() {
@@ -208,12 +235,13 @@ func pullImageIfNeeded(ctx appctx.AppContext) {
 			}
 		}
 	}
+	return nil
 }
 
 // validateImageExists ensures the image exists locally.
-func validateImageExists(ctx appctx.AppContext) {
+func validateImageExists(ctx appctx.AppContext) error {
 	if ctx.Dryrun() {
-		return
+		return nil
 	}
 
 	flags := docker.DockerFlags{
@@ -221,12 +249,11 @@ func validateImageExists(ctx appctx.AppContext) {
 		Verbose: ctx.Verbose(),
 		Silent:  true,
 	}
-	err := docker.Docker(flags, "image", ilist.NewList(ilist.NewList("inspect", ctx.Image())))
+	err := ctx.Docker().Command(flags, "image", ilist.NewList(ilist.NewList("inspect", ctx.Image())))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: image '%s' not available locally.\n", ctx.Image())
-		fmt.Fprintln(os.Stderr, "       Use '--pull' if you want to force pulling it.")
-		os.Exit(1)
+		return &ImageNotFoundError{Image: ctx.Image()}
 	}
+	return nil
 }
 
 // isFile checks if a path is a file.
@@ -0,0 +1,321 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// Kinds of ConfigSource.
+const (
+	SourceUnset    = "unset"
+	SourceDefault  = "default"
+	SourceDetected = "detected"
+	SourceEnv      = "env"
+	SourceFile     = "file"
+	SourceCli      = "cli"
+	SourceDerived  = "derived"
+)
+
+// ConfigSource tells where a config value came from (e.g. "env CB_PORT" or "file /code/.booth/config.toml:3").
+type ConfigSource struct {
+	Kind   string
+	Detail string
+}
+
+func (source ConfigSource) String() string {
+	if source.Detail == "" {
+		return source.Kind
+	}
+	return source.Kind + " " + source.Detail
+}
+
+// ConfigValue is a value of a config field and its source.
+type ConfigValue struct {
+	Value  string
+	Source ConfigSource
+}
+
+// ConfigEntry explains one AppConfig field: its effective value and source, and the values it overrode (latest first).
+type ConfigEntry struct {
+	Field      string
+	Key        string
+	Value      string
+	Source     ConfigSource
+	Overridden []ConfigValue
+}
+
+// ConfigExplanation explains where every AppConfig field got its value (see ExplainAppContext).
+type ConfigExplanation struct {
+	Entries []ConfigEntry
+}
+
+// Entry returns the entry of a field, by its Go name (e.g. "Port") or its TOML key (e.g. "port").
+func (explanation ConfigExplanation) Entry(name string) (ConfigEntry, bool) {
+	for _, entry := range explanation.Entries {
+		if entry.Field == name || entry.Key == name {
+			return entry, true
+		}
+	}
+	return ConfigEntry{}, false
+}
+
+// String returns the explanation as a table: key, value and source; overridden values are listed below their key.
+func (explanation ConfigExplanation) String() string {
+	var str strings.Builder
+	writer := tabwriter.NewWriter(&str, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "KEY\tVALUE\tSOURCE")
+	for _, entry := range explanation.Entries {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", entry.Key, entry.Value, entry.Source)
+		for _, overridden := range entry.Overridden {
+			fmt.Fprintf(writer, "\t  overrides %s\t%s\n", overridden.Value, overridden.Source)
+		}
+	}
+	writer.Flush()
+	return str.String()
+}
+
+// ExplainAppContext is InitializeAppContext that also tells where each config value came from.
+func ExplainAppContext(version string, boundary InitializeAppContextBoundary) (appctx.AppContext, ConfigExplanation, error) {
+	tracker := newConfigTracker()
+	ctx, err := initializeAppContext(version, boundary, tracker)
+	return ctx, tracker.explanation(), err
+}
+
+// HasExplainFlag tells if the arguments (before "--") ask for --explain.
+func HasExplainFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--explain" {
+			return true
+		}
+	}
+	return false
+}
+
+// configTracker records the source of each AppConfig field as InitializeAppContext applies its layers.
+// A nil tracker records nothing.
+type configTracker struct {
+	entries []*ConfigEntry
+}
+
+func newConfigTracker() *configTracker {
+	tracker := &configTracker{}
+	configType := reflect.TypeOf(appctx.AppConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		tracker.entries = append(tracker.entries, &ConfigEntry{
+			Field:  field.Name,
+			Key:    tomlKey(field),
+			Value:  formatConfigValue(reflect.ValueOf(appctx.AppConfig{}).Field(i)),
+			Source: ConfigSource{Kind: SourceUnset},
+		})
+	}
+	return tracker
+}
+
+// snapshot returns a copy of the config to compare after a layer is applied.
+func (tracker *configTracker) snapshot(config *appctx.AppConfig) *appctx.AppConfig {
+	if tracker == nil {
+		return nil
+	}
+	return config.Clone()
+}
+
+// record attributes the fields changed from before to after to the source given by sourceOf.
+// sourceOf may also claim unchanged fields (e.g. an env var with the same value as the default).
+func (tracker *configTracker) record(before *appctx.AppConfig, after *appctx.AppConfig, sourceOf func(entry *ConfigEntry, changed bool) (ConfigSource, bool)) {
+	if tracker == nil {
+		return
+	}
+	beforeValue := reflect.ValueOf(*before)
+	afterValue := reflect.ValueOf(*after)
+	for i, entry := range tracker.entries {
+		value := formatConfigValue(afterValue.Field(i))
+		changed := value != formatConfigValue(beforeValue.Field(i))
+		source, ok := sourceOf(entry, changed)
+		if !ok || (source == entry.Source && value == entry.Value) {
+			continue
+		}
+		if entry.Source.Kind != SourceUnset {
+			entry.Overridden = append([]ConfigValue{{Value: entry.Value, Source: entry.Source}}, entry.Overridden...)
+		}
+		entry.Value = value
+		entry.Source = source
+	}
+}
+
+// recordChanged attributes every changed field to the same kind of source.
+func (tracker *configTracker) recordChanged(before *appctx.AppConfig, after *appctx.AppConfig, kind string) {
+	tracker.record(before, after, func(_ *ConfigEntry, changed bool) (ConfigSource, bool) {
+		return ConfigSource{Kind: kind}, changed
+	})
+}
+
+// recordEnv attributes the fields of the env var layer: set env vars, or envconfig defaults.
+func (tracker *configTracker) recordEnv(before *appctx.AppConfig, after *appctx.AppConfig) {
+	if tracker == nil {
+		return
+	}
+	envNames := map[string]string{}
+	defaults := map[string]string{}
+	configType := reflect.TypeOf(appctx.AppConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		envNames[field.Name] = field.Tag.Get("envconfig")
+		if value, ok := field.Tag.Lookup("default"); ok {
+			defaults[field.Name] = value
+			if field.Type.Kind() == reflect.String {
+				defaults[field.Name] = fmt.Sprintf("%q", value)
+			}
+		}
+	}
+
+	tracker.record(before, after, func(entry *ConfigEntry, changed bool) (ConfigSource, bool) {
+		name := envNames[entry.Field]
+		if _, set := os.LookupEnv(name); set && name != "" && (changed || !isPreserved(entry, before)) {
+			// The env var also replaced the default (applied in the same pass)
+			if value, found := defaults[entry.Field]; found && entry.Source.Kind == SourceUnset {
+				entry.Value = value
+				entry.Source = ConfigSource{Kind: SourceDefault}
+			}
+			return ConfigSource{Kind: SourceEnv, Detail: name}, true
+		}
+		return ConfigSource{Kind: SourceDefault}, changed
+	})
+}
+
+// recordToml attributes the fields whose keys are in the config file (or its profile table), with their line.
+func (tracker *configTracker) recordToml(before *appctx.AppConfig, after *appctx.AppConfig, path string, profile string) {
+	if tracker == nil {
+		return
+	}
+	lines := tomlKeyLines(path, profile)
+	tracker.record(before, after, func(entry *ConfigEntry, changed bool) (ConfigSource, bool) {
+		line, found := lines[entry.Key]
+		if !found || (!changed && isPreserved(entry, before)) {
+			return ConfigSource{}, false
+		}
+		detail := fmt.Sprintf("%s:%d", path, line.number)
+		if line.profile != "" {
+			detail += " [profile." + line.profile + "]"
+		}
+		return ConfigSource{Kind: SourceFile, Detail: detail}, true
+	})
+}
+
+// recordArgs attributes the fields set by command-line flags.
+func (tracker *configTracker) recordArgs(before *appctx.AppConfig, after *appctx.AppConfig, args ilist.List[string]) {
+	if tracker == nil {
+		return
+	}
+	given := map[string]bool{}
+	for i := 0; i < args.Length(); i++ {
+		if args.At(i) == "--" {
+			given["--"] = true
+			break
+		}
+		given[args.At(i)] = true
+	}
+	tracker.record(before, after, func(entry *ConfigEntry, changed bool) (ConfigSource, bool) {
+		flag := cliFlagOf(entry)
+		if !changed && !given[flag] {
+			return ConfigSource{}, false
+		}
+		return ConfigSource{Kind: SourceCli, Detail: flag}, true
+	})
+}
+
+// recordFirstPass attributes --config, --code, --verbose and --dryrun (read before the other layers).
+func (tracker *configTracker) recordFirstPass(before *appctx.AppConfig, after *appctx.AppConfig) {
+	tracker.record(before, after, func(entry *ConfigEntry, changed bool) (ConfigSource, bool) {
+		return ConfigSource{Kind: SourceCli, Detail: cliFlagOf(entry)}, changed
+	})
+}
+
+func (tracker *configTracker) explanation() ConfigExplanation {
+	explanation := ConfigExplanation{}
+	for _, entry := range tracker.entries {
+		explanation.Entries = append(explanation.Entries, *entry)
+	}
+	return explanation
+}
+
+// isPreserved tells if the layers cannot change the field (code and config once set; see runPreserveCodeAndConfig).
+func isPreserved(entry *ConfigEntry, before *appctx.AppConfig) bool {
+	return (entry.Field == "Code" && before.Code.IsSet()) || (entry.Field == "Config" && before.Config.IsSet())
+}
+
+// cliFlagOf returns the command-line flag of a field (e.g. "--port").
+func cliFlagOf(entry *ConfigEntry) string {
+	switch entry.Field {
+	case "RunArgs":
+		return "<run-args>"
+	case "BuildArgs":
+		return "--build-arg"
+	case "Cmds":
+		return "--"
+	}
+	return "--" + entry.Key
+}
+
+func tomlKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+	if key == "" || key == "-" {
+		return strings.ToLower(field.Name)
+	}
+	return key
+}
+
+func formatConfigValue(value reflect.Value) string {
+	if value.Kind() == reflect.String {
+		return fmt.Sprintf("%q", value.String())
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// tomlKeyLine is the line of a key in a TOML file and the profile table it is in (if any).
+type tomlKeyLine struct {
+	number  int
+	profile string
+}
+
+// tomlKeyLines returns the line of each top-level key (and table) of a TOML file.
+// The keys of the `[profile.<profile>]` table take the place of the top-level ones.
+func tomlKeyLines(path string, profile string) map[string]tomlKeyLine {
+	lines := map[string]tomlKeyLine{}
+	profilePrefix := "profile." + profile + "."
+	for key, number := range appctx.TomlKeyLines(path) {
+		line := tomlKeyLine{number: number}
+		name, _, _ := strings.Cut(key, ".")
+		if sub, found := strings.CutPrefix(key, profilePrefix); found && profile != "" {
+			name, _, _ = strings.Cut(sub, ".")
+			line.profile = profile
+		} else if name == "profile" {
+			continue
+		}
+		existing, found := lines[name]
+		switch {
+		case !found:
+		case line.profile != "" && existing.profile == "":
+			// The profile keys win
+		case line.profile == existing.profile && number < existing.number:
+			// The first line of the key (or table)
+		default:
+			continue
+		}
+		lines[name] = line
+	}
+	return lines
+}
@@ -0,0 +1,84 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplainAppContext_Sources(t *testing.T) {
+	input := TestInput{
+		EnvMap: map[string]string{"CB_PORT": "11000", "CB_IMAGE": "from-env"},
+		Args:   []string{"--port", "13000", "--dind", "--dryrun"},
+		TomlFiles: []TomlFile{{
+			Path:    ".booth/config.toml",
+			Content: "# booth\nvariant = \"codeserver\"\n\nport = \"12000\"\n",
+		}},
+		Timezone: "UTC",
+		HostUID:  "1000",
+		HostGID:  "1000",
+	}
+	// Sets up the env vars, the config file and the current directory
+	res := RunInitializeAppContext(t, input)
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+
+	ctx, explanation, err := ExplainAppContext("latest", input)
+	if err != nil {
+		t.Fatalf("ExplainAppContext() returned error: %v", err)
+	}
+	if ctx.Port() != "13000" {
+		t.Errorf("expected port 13000, got %q", ctx.Port())
+	}
+
+	port, _ := explanation.Entry("port")
+	wantPort := ConfigEntry{
+		Field:  "Port",
+		Key:    "port",
+		Value:  `"13000"`,
+		Source: ConfigSource{Kind: SourceCli, Detail: "--port"},
+		Overridden: []ConfigValue{
+			{Value: `"12000"`, Source: ConfigSource{Kind: SourceFile, Detail: ".booth/config.toml:4"}},
+			{Value: `"11000"`, Source: ConfigSource{Kind: SourceEnv, Detail: "CB_PORT"}},
+			{Value: `"NEXT"`, Source: ConfigSource{Kind: SourceDefault}},
+		},
+	}
+	if !reflect.DeepEqual(port, wantPort) {
+		t.Errorf("port entry = %+v\nwant %+v", port, wantPort)
+	}
+
+	sources := map[string]string{
+		"variant":  "file .booth/config.toml:2",
+		"image":    "env CB_IMAGE",
+		"dind":     "cli --dind",
+		"dryrun":   "cli --dryrun",
+		"timezone": "detected",
+		"name":     "derived",
+		"startup":  "unset",
+	}
+	for key, want := range sources {
+		entry, found := explanation.Entry(key)
+		if !found || entry.Source.String() != want {
+			t.Errorf("%s: source = %q, want %q", key, entry.Source, want)
+		}
+	}
+
+	table := explanation.String()
+	if !strings.Contains(table, "overrides \"12000\"") || !strings.HasPrefix(table, "KEY") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
+
+func TestHasExplainFlag(t *testing.T) {
+	if !HasExplainFlag([]string{"--port", "10000", "--explain"}) {
+		t.Error("expected --explain to be found")
+	}
+	if HasExplainFlag([]string{"--", "grep", "--explain"}) {
+		t.Error("--explain after -- belongs to the command")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+func TestConfigLayers_UserProjectLocal(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		EnvMap:     map[string]string{"CB_RUN_ARGS": "-e;FROM_ENV=1"},
+		UserConfig: "user/config.toml",
+		TomlFiles: []TomlFile{{
+			Path: "user/config.toml",
+			Content: `variant = "from-user"
+timezone = "Asia/Bangkok"
+run-args = ["-v", "/host/.claude:/etc/cb-home-seed/.claude:ro"]
+[hooks]
+before-run = "echo user"
+after-run = "echo user"
+`,
+		}, {
+			Path: ".booth/config.toml",
+			Content: `variant = "from-project"
+run-args = ["-p", "8080:8080"]
+dockerfile = ".booth/Dockerfile"
+build-args = ["--no-cache"]
+[hooks]
+after-run = "echo project"
+`,
+		}, {
+			Path: ".booth/config.local.toml",
+			Content: `port = "12000"
+run-args = ["-e", "LOCAL=1"]
+`,
+		}},
+	})
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+
+	if got := res.Ctx.Variant(); got != "from-project" {
+		t.Errorf("expected the project variant to win over the user one, got %q", got)
+	}
+	if got := res.Ctx.Timezone(); got != "Asia/Bangkok" {
+		t.Errorf("expected the user timezone, got %q", got)
+	}
+	if got := res.Ctx.Port(); got != "12000" {
+		t.Errorf("expected the local port, got %q", got)
+	}
+
+	wantRunArgs := []string{"-v", "/host/.claude:/etc/cb-home-seed/.claude:ro", "-p", "8080:8080", "-e", "LOCAL=1"}
+	if got := flattenArgs(res.Ctx.RunArgs()); !reflect.DeepEqual(got, wantRunArgs) {
+		t.Errorf("run-args = %q, want %q (env run-args are replaced by the first config file)", got, wantRunArgs)
+	}
+	if got := flattenArgs(res.Ctx.BuildArgs()); !reflect.DeepEqual(got, []string{"--no-cache"}) {
+		t.Errorf("build-args = %q", got)
+	}
+
+	wantHooks := map[string]string{"before-run": "echo user", "after-run": "echo project"}
+	if got := res.Ctx.Hooks(); !reflect.DeepEqual(got, wantHooks) {
+		t.Errorf("hooks = %v, want %v", got, wantHooks)
+	}
+}
+
+func TestConfigLayers_Replace(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		UserConfig: "user/config.toml",
+		TomlFiles: []TomlFile{{
+			Path:    "user/config.toml",
+			Content: `run-args = ["-v", "/host/.claude:/etc/cb-home-seed/.claude:ro"]`,
+		}, {
+			Path: ".booth/config.local.toml",
+			Content: `replace = ["run-args"]
+run-args = ["-e", "ONLY=1"]
+`,
+		}},
+	})
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+
+	if got := flattenArgs(res.Ctx.RunArgs()); !reflect.DeepEqual(got, []string{"-e", "ONLY=1"}) {
+		t.Errorf("run-args = %q, want only the local ones", got)
+	}
+}
+
+func TestConfigLayers_Tables(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		UserConfig: "user/config.toml",
+		TomlFiles: []TomlFile{{
+			Path: "user/config.toml",
+			Content: `setups = ["gh", "go@1.22"]
+
+[[mounts]]
+source = "~/.claude"
+seed = true
+optional = true
+
+[ports]
+web = 3000
+
+[env]
+EDITOR = "vim"
+`,
+		}, {
+			Path: ".booth/config.toml",
+			Content: `setups = ["go@1.23", "nodejs@22"]
+
+[[mounts]]
+source = "/var/data"
+target = "/data"
+mode = "ro"
+
+[ports]
+web = "8080:3000"
+db = 5432
+
+[env]
+APP_ENV = "dev"
+`,
+		}},
+	})
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+
+	wantSetups := []appctx.SetupSpec{{Name: "gh"}, {Name: "go", Version: "1.23"}, {Name: "nodejs", Version: "22"}}
+	if got := res.Ctx.Setups(); !reflect.DeepEqual(got, wantSetups) {
+		t.Errorf("setups = %v, want %v (appended, the later version wins)", got, wantSetups)
+	}
+	wantMounts := []appctx.Mount{
+		{Source: os.Getenv("HOME") + "/.claude", Seed: true, Optional: true},
+		{Source: "/var/data", Target: "/data", Mode: "ro"},
+	}
+	if got := res.Ctx.Mounts(); !reflect.DeepEqual(got, wantMounts) {
+		t.Errorf("mounts = %+v, want %+v (appended)", got, wantMounts)
+	}
+	wantPorts := map[string]appctx.PortMapping{"web": "8080:3000", "db": "5432"}
+	if got := res.Ctx.Ports(); !reflect.DeepEqual(got, wantPorts) {
+		t.Errorf("ports = %v, want %v (merged by name)", got, wantPorts)
+	}
+	wantEnv := map[string]string{"EDITOR": "vim", "APP_ENV": "dev"}
+	if got := res.Ctx.Env(); !reflect.DeepEqual(got, wantEnv) {
+		t.Errorf("env = %v, want %v (merged by name)", got, wantEnv)
+	}
+}
+
+func TestConfigLayers_MissingUserConfigIsSkipped(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		UserConfig: "user/missing.toml",
+		TomlFiles: []TomlFile{{
+			Path:    ".booth/config.toml",
+			Content: `variant = "from-project"`,
+		}},
+	})
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+	if got := res.Ctx.Variant(); got != "from-project" {
+		t.Errorf("expected Variant %q, got %q", "from-project", got)
+	}
+}
+
+func flattenArgs(args ilist.List[ilist.List[string]]) []string {
+	flattened := []string{}
+	args.Range(func(_ int, group ilist.List[string]) bool {
+		flattened = append(flattened, group.Slice()...)
+		return true
+	})
+	return flattened
+}
@@ -0,0 +1,226 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+// legacyBoothDir is the folder of the project files before .booth (e.g. .ws/config.toml and .ws/Dockerfile).
+const legacyBoothDir = ".ws"
+
+// diffContext is the number of unchanged lines around the changes in a ConfigFileMigration diff.
+const diffContext = 2
+
+// ConfigFileMigration is the migration of a config file (see appctx.MigrateToml)
+// or of a file of the legacy .ws folder to move to .booth.
+type ConfigFileMigration struct {
+	// From is where the file is; To is where it goes (the same when it stays).
+	From string
+	To   string
+	// Before and After are the content of a config file (both empty for the other files).
+	Before string
+	After  string
+	// Changes are the changes of the content, and the ones to make by hand.
+	Changes []appctx.ConfigProblem
+}
+
+// Moved tells if the file moves.
+func (migration ConfigFileMigration) Moved() bool {
+	return migration.From != migration.To
+}
+
+// Diff returns the unified diff of the content (empty if it does not change).
+// The migrations keep the lines (see appctx.MigrateToml), so the lines are compared one for one.
+func (migration ConfigFileMigration) Diff() string {
+	if migration.Before == migration.After {
+		return ""
+	}
+	before, after := contentLines(migration.Before), contentLines(migration.After)
+	if len(before) != len(after) {
+		return fmt.Sprintf("--- %s\n+++ %s\n@@ -1,%d +1,%d @@\n%s\n%s\n", migration.From, migration.To, len(before), len(after),
+			prefixLines("-", before), prefixLines("+", after))
+	}
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- %s\n+++ %s\n", migration.From, migration.To)
+	for start := 0; start < len(before); start++ {
+		if before[start] == after[start] {
+			continue
+		}
+		// A hunk: the changed lines and the context, merged with the next changes within the context
+		end := start
+		for next := start; next < len(before) && next <= end+2*diffContext; next++ {
+			if before[next] != after[next] {
+				end = next
+			}
+		}
+		first, last := max(start-diffContext, 0), min(end+diffContext, len(before)-1)
+		fmt.Fprintf(&diff, "@@ -%d,%d +%d,%d @@\n", first+1, last-first+1, first+1, last-first+1)
+		for i := first; i <= last; i++ {
+			if before[i] == after[i] {
+				diff.WriteString(" " + before[i] + "\n")
+				continue
+			}
+			run := i
+			for run <= last && before[run] != after[run] {
+				run++
+			}
+			diff.WriteString(prefixLines("-", before[i:run]) + "\n" + prefixLines("+", after[i:run]) + "\n")
+			i = run - 1
+		}
+		start = last
+	}
+	return diff.String()
+}
+
+// contentLines returns the lines of a content (without the empty one after the last newline).
+func contentLines(content string) []string {
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+func prefixLines(prefix string, lines []string) string {
+	return prefix + strings.Join(lines, "\n"+prefix)
+}
+
+// MigrateConfig returns the migrations of the config files (user, project and local) found with the run options
+// and of the files of the legacy .ws folder of the code (moved to .booth), in that order.
+// Only the files that change are returned; nothing is written (see ApplyConfigMigrations).
+func MigrateConfig(boundary InitializeAppContextBoundary) ([]ConfigFileMigration, error) {
+	context := appctx.AppContextBuilder{}
+	configExplicitlySet, err := readCodeAndConfigFile(boundary, &context, nil)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := existingConfigLayers(boundary, &context, configExplicitlySet)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := []ConfigFileMigration{}
+	for _, layer := range layers {
+		migration, err := migrateConfigFile(layer.path, layer.path)
+		if err != nil {
+			return nil, err
+		}
+		if len(migration.Changes) > 0 {
+			migrations = append(migrations, migration)
+		}
+	}
+
+	legacy, err := legacyLayoutMigrations(context.Config.Code.ValueOr(""))
+	if err != nil {
+		return nil, err
+	}
+	return append(migrations, legacy...), nil
+}
+
+// migrateConfigFile returns the migration of the config file at from (moving to to).
+func migrateConfigFile(from string, to string) (ConfigFileMigration, error) {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return ConfigFileMigration{}, err
+	}
+	migration := ConfigFileMigration{From: from, To: to, Before: string(data)}
+	migration.After, migration.Changes = appctx.MigrateToml(migration.Before)
+	for i := range migration.Changes {
+		migration.Changes[i].File = to
+	}
+	return migration, nil
+}
+
+// legacyLayoutMigrations returns the moves of the files of <code>/.ws to <code>/.booth (with their config files migrated).
+// A file that exists in both is left in .ws with a change to merge it by hand.
+func legacyLayoutMigrations(code string) ([]ConfigFileMigration, error) {
+	legacyDir := filepath.Join(code, legacyBoothDir)
+	if info, err := os.Stat(legacyDir); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	migrations := []ConfigFileMigration{}
+	err := filepath.WalkDir(legacyDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relative, err := filepath.Rel(legacyDir, path)
+		if err != nil {
+			return err
+		}
+		to := filepath.Join(code, ".booth", relative)
+		if _, err := os.Lstat(to); err == nil {
+			migrations = append(migrations, ConfigFileMigration{From: path, To: path, Changes: []appctx.ConfigProblem{{
+				File:    path,
+				Message: fmt.Sprintf("%s exists too, merge this file into it by hand", to),
+			}}})
+			return nil
+		}
+		if relative != "config.toml" && relative != "config.local.toml" {
+			migrations = append(migrations, ConfigFileMigration{From: path, To: to})
+			return nil
+		}
+		migration, err := migrateConfigFile(path, to)
+		if err == nil {
+			migrations = append(migrations, migration)
+		}
+		return err
+	})
+	return migrations, err
+}
+
+// ApplyConfigMigrations writes the migrations: it moves the files and writes the migrated content.
+// The folders left empty by the moves (e.g. .ws) are removed.
+func ApplyConfigMigrations(migrations []ConfigFileMigration) error {
+	emptied := []string{}
+	for _, migration := range migrations {
+		if migration.Moved() {
+			if err := os.MkdirAll(filepath.Dir(migration.To), 0o755); err != nil {
+				return err
+			}
+			if err := os.Rename(migration.From, migration.To); err != nil {
+				return err
+			}
+			emptied = append(emptied, filepath.Dir(migration.From))
+		}
+		if migration.Before != migration.After {
+			info, err := os.Stat(migration.To)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(migration.To, []byte(migration.After), info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The deepest first, then their parents up to the legacy folder (os.Remove fails on the ones that are not empty)
+	slices.SortFunc(emptied, func(a, b string) int { return len(b) - len(a) })
+	for _, dir := range emptied {
+		for os.Remove(dir) == nil && filepath.Base(dir) != legacyBoothDir {
+			dir = filepath.Dir(dir)
+		}
+	}
+	return nil
+}
+
+// warnLegacyConfig warns about the deprecated keys of the config layers and about a legacy .ws/config.toml
+// (not read anymore), pointing to `config migrate`.
+func warnLegacyConfig(context *appctx.AppContextBuilder, layers []configLayer) {
+	for _, layer := range layers {
+		for _, deprecated := range appctx.DeprecatedKeysOf(layer.path) {
+			fmt.Fprintf(os.Stderr, "⚠️  %s (see 'coding-booth config migrate')\n", deprecated)
+		}
+	}
+	legacyConfig := filepath.Join(context.Config.Code.ValueOr(""), legacyBoothDir, "config.toml")
+	if !context.Config.Config.IsSet() && fileExists(legacyConfig) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s is not read anymore: the project files are in .booth now (see 'coding-booth config migrate')\n", legacyConfig)
+	}
+}
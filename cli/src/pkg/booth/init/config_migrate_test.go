@@ -0,0 +1,101 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+func TestMigrateConfig_LegacyLayout(t *testing.T) {
+	input := TestInput{
+		TomlFiles: []TomlFile{
+			{Path: ".ws/config.toml", Content: "# ours\ndockerfile = \".ws/Dockerfile\"\nvariant = \"base\"\n"},
+			{Path: ".ws/Dockerfile", Content: "FROM scratch\n"},
+			{Path: ".ws/home/.bashrc", Content: "alias ll='ls -l'\n"},
+			{Path: ".booth/config.local.toml", Content: "run-args = \"-e;A=1\"\n"},
+		},
+	}
+	// The code is the current folder (the paths are relative to it)
+	RunInitializeAppContext(t, input)
+
+	migrations, err := MigrateConfig(input)
+	if err != nil {
+		t.Fatalf("MigrateConfig() returned error: %v", err)
+	}
+	if len(migrations) != 4 {
+		t.Fatalf("expected the local config and the 3 files of .ws, got %+v", migrations)
+	}
+
+	local := migrations[0]
+	if local.Moved() || local.After != "run-args = [\"-e\", \"A=1\"]\n" {
+		t.Errorf("local config migration = %+v", local)
+	}
+	config := migrations[2]
+	wantDiff := "--- .ws/config.toml\n" +
+		"+++ .booth/config.toml\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" # ours\n" +
+		"-dockerfile = \".ws/Dockerfile\"\n" +
+		"+dockerfile = \".booth/Dockerfile\"\n" +
+		" variant = \"base\"\n"
+	if diff := config.Diff(); diff != wantDiff {
+		t.Errorf("Diff() =\n%s\nwant\n%s", diff, wantDiff)
+	}
+
+	if err := ApplyConfigMigrations(migrations); err != nil {
+		t.Fatalf("ApplyConfigMigrations() returned error: %v", err)
+	}
+	if _, err := os.Stat(".ws"); !os.IsNotExist(err) {
+		t.Errorf("expected .ws to be removed, got %v", err)
+	}
+	for _, name := range []string{"Dockerfile", "home/.bashrc"} {
+		if _, err := os.Stat(filepath.Join(".booth", name)); err != nil {
+			t.Errorf("expected .booth/%s: %v", name, err)
+		}
+	}
+	if content, _ := os.ReadFile(".booth/config.toml"); !strings.HasPrefix(string(content), "# ours\ndockerfile = ") {
+		t.Errorf("config.toml =\n%s", content)
+	}
+
+	if migrations, err := MigrateConfig(input); err != nil || len(migrations) != 0 {
+		t.Errorf("expected nothing more to migrate, got %+v, %v", migrations, err)
+	}
+}
+
+func TestWarnLegacyConfig_DeprecatedKeys(t *testing.T) {
+	// No key is renamed yet
+	saved := appctx.DeprecatedKeys
+	appctx.DeprecatedKeys = map[string]string{"image-name": "image"}
+	defer func() { appctx.DeprecatedKeys = saved }()
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("variant = \"base\"\nimage-name = \"my-image\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Capture stderr
+	oldStderr := os.Stderr
+	reader, writer, _ := os.Pipe()
+	os.Stderr = writer
+
+	warnLegacyConfig(&appctx.AppContextBuilder{}, []configLayer{{path: path}})
+
+	writer.Close()
+	os.Stderr = oldStderr
+	var buf bytes.Buffer
+	io.Copy(&buf, reader)
+
+	want := "⚠️  " + path + ":2: image-name: deprecated, use 'image' (see 'coding-booth config migrate')\n"
+	if got := buf.String(); got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
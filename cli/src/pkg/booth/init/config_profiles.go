@@ -0,0 +1,77 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+// ConfigProfile is a `[profile.<name>]` table and the config files that define it.
+type ConfigProfile struct {
+	Name   string
+	Files  []string
+	Active bool
+}
+
+// ConfigProfiles lists the profiles of the config files (user, project and local) in name order.
+// The profile selected with --profile or CB_PROFILE is marked active; it does not have to exist.
+func ConfigProfiles(boundary InitializeAppContextBoundary) ([]ConfigProfile, error) {
+	context := appctx.AppContextBuilder{}
+	configExplicitlySet, err := readCodeAndConfigFile(boundary, &context, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := readFromEnvVars(boundary, &context); err != nil {
+		return nil, err
+	}
+	active := context.Config.Profile
+	if profile, found := profileFromArgs(boundary.ArgList()); found {
+		active = profile
+	}
+
+	layers, err := existingConfigLayers(boundary, &context, configExplicitlySet)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []ConfigProfile{}
+	for _, layer := range layers {
+		names, err := appctx.ProfilesOf(layer.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read toml config %s: %w", layer.path, err)
+		}
+		for _, name := range names {
+			index := slices.IndexFunc(profiles, func(profile ConfigProfile) bool { return profile.Name == name })
+			if index == -1 {
+				profiles = append(profiles, ConfigProfile{Name: name, Active: name == active})
+				index = len(profiles) - 1
+			}
+			profiles[index].Files = append(profiles[index].Files, layer.path)
+		}
+	}
+	slices.SortFunc(profiles, func(a, b ConfigProfile) int { return strings.Compare(a.Name, b.Name) })
+	return profiles, nil
+}
+
+// FormatConfigProfiles returns the profiles as a table (the active one is marked with '*').
+func FormatConfigProfiles(profiles []ConfigProfile) string {
+	var str strings.Builder
+	writer := tabwriter.NewWriter(&str, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "  PROFILE\tFILES")
+	for _, profile := range profiles {
+		mark := " "
+		if profile.Active {
+			mark = "*"
+		}
+		fmt.Fprintf(writer, "%s %s\t%s\n", mark, profile.Name, strings.Join(profile.Files, ", "))
+	}
+	writer.Flush()
+	return str.String()
+}
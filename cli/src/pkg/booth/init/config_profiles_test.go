@@ -0,0 +1,157 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const profilesConfig = `variant = "base"
+run-args = ["-e", "SHARED=1"]
+
+[profile.desktop-xfce]
+variant = "xfce"
+run-args = ["-v", "/data:/data"]
+
+[profile.ci]
+port = "RANDOM"
+replace = ["run-args"]
+run-args = ["-e", "CI=1"]
+`
+
+func TestConfigProfiles_Select(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		args        []string
+		wantVariant string
+		wantRunArgs []string
+	}{
+		{
+			name:        "no profile",
+			wantVariant: "base",
+			wantRunArgs: []string{"-e", "SHARED=1"},
+		},
+		{
+			name:        "--profile",
+			args:        []string{"--profile", "desktop-xfce"},
+			wantVariant: "xfce",
+			wantRunArgs: []string{"-e", "SHARED=1", "-v", "/data:/data"},
+		},
+		{
+			name:        "CB_PROFILE",
+			env:         map[string]string{"CB_PROFILE": "ci"},
+			wantVariant: "base",
+			wantRunArgs: []string{"-e", "CI=1"},
+		},
+		{
+			name:        "--profile wins over CB_PROFILE and CLI flags win over the profile",
+			env:         map[string]string{"CB_PROFILE": "ci"},
+			args:        []string{"--profile", "desktop-xfce", "--variant", "codeserver"},
+			wantVariant: "codeserver",
+			wantRunArgs: []string{"-e", "SHARED=1", "-v", "/data:/data"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := RunInitializeAppContext(t, TestInput{
+				EnvMap:    tt.env,
+				Args:      tt.args,
+				TomlFiles: []TomlFile{{Path: ".booth/config.toml", Content: profilesConfig}},
+			})
+			if res.Err != nil {
+				t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+			}
+			if got := res.Ctx.Variant(); got != tt.wantVariant {
+				t.Errorf("Variant() = %q, want %q", got, tt.wantVariant)
+			}
+			if got := flattenArgs(res.Ctx.RunArgs()); !reflect.DeepEqual(got, tt.wantRunArgs) {
+				t.Errorf("RunArgs() = %q, want %q", got, tt.wantRunArgs)
+			}
+		})
+	}
+}
+
+func TestConfigProfiles_AcrossLayers(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		Args:       []string{"--profile", "desktop-xfce"},
+		UserConfig: "user/config.toml",
+		TomlFiles: []TomlFile{{
+			Path:    "user/config.toml",
+			Content: "[profile.desktop-xfce]\nport = \"13000\"\n",
+		}, {
+			Path:    ".booth/config.toml",
+			Content: profilesConfig,
+		}},
+	})
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+	if res.Ctx.Port() != "13000" || res.Ctx.Variant() != "xfce" || res.Ctx.Profile() != "desktop-xfce" {
+		t.Errorf("port = %q, variant = %q, profile = %q", res.Ctx.Port(), res.Ctx.Variant(), res.Ctx.Profile())
+	}
+}
+
+func TestConfigProfiles_Unknown(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		Args:      []string{"--profile", "gpu"},
+		TomlFiles: []TomlFile{{Path: ".booth/config.toml", Content: profilesConfig}},
+	})
+	if res.Err == nil || !strings.Contains(res.Err.Error(), "unknown profile 'gpu' (profiles: ci, desktop-xfce)") {
+		t.Errorf("expected an unknown profile error, got %v", res.Err)
+	}
+}
+
+func TestConfigProfiles_List(t *testing.T) {
+	input := TestInput{
+		EnvMap:    map[string]string{"CB_PROFILE": "ci"},
+		TomlFiles: []TomlFile{{Path: ".booth/config.toml", Content: profilesConfig}},
+	}
+	res := RunInitializeAppContext(t, input)
+	if res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+
+	profiles, err := ConfigProfiles(input)
+	if err != nil {
+		t.Fatalf("ConfigProfiles() returned error: %v", err)
+	}
+	config := res.Ctx.ConfigFile()
+	want := []ConfigProfile{
+		{Name: "ci", Files: []string{config}, Active: true},
+		{Name: "desktop-xfce", Files: []string{config}},
+	}
+	if !reflect.DeepEqual(profiles, want) {
+		t.Errorf("ConfigProfiles() = %+v, want %+v", profiles, want)
+	}
+	if table := FormatConfigProfiles(profiles); !strings.Contains(table, "* ci") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
+
+func TestExplainAppContext_Profile(t *testing.T) {
+	input := TestInput{
+		Args:      []string{"--profile", "desktop-xfce"},
+		TomlFiles: []TomlFile{{Path: ".booth/config.toml", Content: profilesConfig}},
+	}
+	if res := RunInitializeAppContext(t, input); res.Err != nil {
+		t.Fatalf("InitializeAppContext() returned error: %v", res.Err)
+	}
+
+	_, explanation, err := ExplainAppContext("latest", input)
+	if err != nil {
+		t.Fatalf("ExplainAppContext() returned error: %v", err)
+	}
+	variant, _ := explanation.Entry("variant")
+	if !strings.HasSuffix(variant.Source.Detail, "config.toml:5 [profile.desktop-xfce]") {
+		t.Errorf("variant source = %q", variant.Source)
+	}
+	profile, _ := explanation.Entry("profile")
+	if profile.Source.String() != "cli --profile" {
+		t.Errorf("profile source = %q", profile.Source)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"errors"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+// ConfigValidation is the result of ValidateConfig: the config files checked and the problems found.
+type ConfigValidation struct {
+	Files    []string
+	Problems []appctx.ConfigProblem
+}
+
+// ValidateConfig checks the config files (user, project and local) and the resolved config as a run with the same
+// options would, without any Docker call. Problems are returned in the ConfigValidation; other failures as an error.
+func ValidateConfig(version string, boundary InitializeAppContextBoundary) (ConfigValidation, error) {
+	validation := ConfigValidation{}
+
+	context := appctx.AppContextBuilder{}
+	configExplicitlySet, err := readCodeAndConfigFile(boundary, &context, nil)
+	if err != nil {
+		return validation, err
+	}
+	layers, err := existingConfigLayers(boundary, &context, configExplicitlySet)
+	if err != nil {
+		return validation, err
+	}
+	for _, layer := range layers {
+		validation.Files = append(validation.Files, layer.path)
+	}
+
+	_, err = InitializeAppContext(version, boundary)
+	var validationErr *appctx.ConfigValidationError
+	if errors.As(err, &validationErr) {
+		validation.Problems = validationErr.Problems
+		return validation, nil
+	}
+	return validation, err
+}
@@ -0,0 +1,71 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package init
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+func TestInitializeAppContext_InvalidConfigFiles(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		UserConfig: "user/config.toml",
+		TomlFiles: []TomlFile{{
+			Path:    "user/config.toml",
+			Content: "keep_alive = true\n",
+		}, {
+			Path:    ".booth/config.toml",
+			Content: "variant = \"base\"\nport = 10000\n",
+		}},
+	})
+
+	var validationErr *appctx.ConfigValidationError
+	if !errors.As(res.Err, &validationErr) {
+		t.Fatalf("expected a ConfigValidationError, got %v", res.Err)
+	}
+	if len(validationErr.Problems) != 2 {
+		t.Fatalf("expected the problems of both files, got %v", validationErr.Problems)
+	}
+	user, project := validationErr.Problems[0], validationErr.Problems[1]
+	if !strings.HasSuffix(user.File, "user/config.toml") || user.Line != 1 || user.Key != "keep_alive" {
+		t.Errorf("user problem = %+v", user)
+	}
+	if !strings.HasSuffix(project.File, ".booth/config.toml") || project.Line != 2 || !strings.Contains(project.Message, "incompatible types") {
+		t.Errorf("project problem = %+v", project)
+	}
+}
+
+func TestInitializeAppContext_InvalidResolvedConfig(t *testing.T) {
+	res := RunInitializeAppContext(t, TestInput{
+		EnvMap: map[string]string{"CB_PORT": "NEXT"},
+		Args:   []string{"--port", "http", "--build-arg", "A=1"},
+	})
+
+	var validationErr *appctx.ConfigValidationError
+	if !errors.As(res.Err, &validationErr) || len(validationErr.Problems) != 2 {
+		t.Fatalf("expected port and build-args problems, got %v", res.Err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	input := TestInput{
+		TomlFiles: []TomlFile{{Path: ".booth/config.toml", Content: "variant = \"base\"\nprot = \"NEXT\"\n"}},
+	}
+	RunInitializeAppContext(t, input)
+
+	validation, err := ValidateConfig("latest", input)
+	if err != nil {
+		t.Fatalf("ValidateConfig() returned error: %v", err)
+	}
+	if len(validation.Files) != 1 || !strings.HasSuffix(validation.Files[0], ".booth/config.toml") {
+		t.Errorf("Files = %v", validation.Files)
+	}
+	if len(validation.Problems) != 1 || validation.Problems[0].Message != "unknown key (did you mean 'port'?)" {
+		t.Errorf("Problems = %v", validation.Problems)
+	}
+}
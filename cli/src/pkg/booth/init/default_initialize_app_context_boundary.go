@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"path/filepath"
 	"runtime"
 	"time"
 
@@ -56,17 +57,17 @@ func (DefaultInitializeAppContextBoundary) DetectTimezone() string {
 	return "UTC"
 }
 
-func (DefaultInitializeAppContextBoundary) GetCurrentPath() string {
+func (DefaultInitializeAppContextBoundary) GetCurrentPath() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		panic(fmt.Errorf("Error getting current directory: %v", err))
+		return "", fmt.Errorf("error getting current directory: %w", err)
 	}
 
 	if runtime.GOOS == "windows" {
-		return cwd
+		return cwd, nil
 	}
 
-	return cwd
+	return cwd, nil
 }
 
 func (DefaultInitializeAppContextBoundary) GetHostUID() string {
@@ -92,3 +93,22 @@ func (DefaultInitializeAppContextBoundary) GetHostGID() string {
 	}
 	return u.Gid
 }
+
+func (DefaultInitializeAppContextBoundary) GetUserConfigFile() string {
+	if path, ok := os.LookupEnv("CB_USER_CONFIG"); ok {
+		if path == "none" {
+			return ""
+		}
+		return path
+	}
+
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "codingbooth", "config.toml")
+}
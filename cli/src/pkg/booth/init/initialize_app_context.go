@@ -5,18 +5,27 @@
 package init
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
 	"github.com/nawaman/codingbooth/src/pkg/ilist"
 	"github.com/nawaman/codingbooth/src/pkg/nillable"
 )
 
 // InitializeAppContext creates an AppContext with default values matching booth Main()
-func InitializeAppContext(version string, boundary InitializeAppContextBoundary) appctx.AppContext {
+// It returns an error for invalid arguments, environment variables or config file.
+func InitializeAppContext(version string, boundary InitializeAppContextBoundary) (appctx.AppContext, error) {
+	return initializeAppContext(version, boundary, nil)
+}
+
+// initializeAppContext is InitializeAppContext that records the source of the config values in the tracker (if not nil).
+func initializeAppContext(version string, boundary InitializeAppContextBoundary, tracker *configTracker) (appctx.AppContext, error) {
 	// Initialize config and context
 	config := appctx.AppConfig{}
 	context := appctx.AppContextBuilder{
@@ -32,37 +41,53 @@ func InitializeAppContext(version string, boundary InitializeAppContextBoundary)
 	context.ScriptName = getScriptName(args)
 	context.ScriptDir = getScriptDir(args)
 	context.Version = context.Config.Version.ValueOr(context.CbVersion)
+	before := tracker.snapshot(&context.Config)
 	context.Config.HostUID = boundary.GetHostUID()
 	context.Config.HostGID = boundary.GetHostGID()
 	context.Config.Timezone = boundary.DetectTimezone()
-
-	// First pass to read important flags and value: --dryrun, --verbose, --config and --code
-	configExplicitlySet := false
-	readVerboseDryrunConfigFileAndCode(boundary, &context, &configExplicitlySet)
+	tracker.recordChanged(before, &context.Config, SourceDetected)
 
 	// Set additional values that is derived from other values
 	context.LibDir = filepath.Join(context.ScriptDir, "libs")
-	if !context.Config.Code.IsSet() {
-		context.Config.Code = nillable.NewNillableString(boundary.GetCurrentPath())
+
+	configExplicitlySet, err := readCodeAndConfigFile(boundary, &context, tracker)
+	if err != nil {
+		return appctx.AppContext{}, err
 	}
-	if !context.Config.Config.IsSet() {
-		codePath := context.Config.Code.ValueOr("")
-		configFile := filepath.Join(codePath, ".booth", "config.toml")
-		if fileExists(configFile) {
-			context.Config.Config = nillable.NewNillableString(configFile)
-		}
+
+	before = tracker.snapshot(&context.Config)
+	if err := readFromEnvVars(boundary, &context); err != nil {
+		return appctx.AppContext{}, err
+	}
+	tracker.recordEnv(before, &context.Config)
+
+	// The profile selects the [profile.<name>] tables, so --profile is needed before reading the config files
+	if profile, found := profileFromArgs(args); found {
+		context.Config.Profile = profile
+	}
+	if err := readFromToml(boundary, &context, configExplicitlySet, tracker); err != nil {
+		return appctx.AppContext{}, err
 	}
 
-	readFromEnvVars(boundary, &context)
-	readFromToml(boundary, &context, configExplicitlySet)
-	readFromArgs(boundary, &context, ilist.NewListFromSlice(args.Slice()[1:]))
+	before = tracker.snapshot(&context.Config)
+	cliArgs := ilist.NewListFromSlice(args.Slice()[1:])
+	if err := readFromArgs(boundary, &context, cliArgs); err != nil {
+		return appctx.AppContext{}, err
+	}
+	tracker.recordArgs(before, &context.Config, cliArgs)
 
+	before = tracker.snapshot(&context.Config)
 	if context.Config.ProjectName == "" {
 		context.Config.ProjectName = getProjectName(context.Config.Code.ValueOr("."))
 	}
 	if context.Config.Name == "" {
 		context.Config.Name = context.Config.ProjectName
 	}
+	tracker.recordChanged(before, &context.Config, SourceDerived)
+
+	if problems := context.Config.Validate(); len(problems) > 0 {
+		return appctx.AppContext{}, &appctx.ConfigValidationError{Problems: problems}
+	}
 
 	// Sync list fields from Config to Builder
 	// We wrap the flat string list from Config into a single group in the nested list structure
@@ -95,7 +120,45 @@ func InitializeAppContext(version string, boundary InitializeAppContextBoundary)
 		context.BuildArgs = ilist.NewAppendableList[ilist.List[string]]()
 	}
 
-	return context.Build()
+	client, err := docker.NewDockerClient(context.Config.Engine)
+	if err != nil {
+		return appctx.AppContext{}, err
+	}
+	context.Docker = client
+
+	return context.Build(), nil
+}
+
+// readCodeAndConfigFile reads --dryrun, --verbose, --config and --code (first pass),
+// then defaults the code to the current path and the config to <code>/.booth/config.toml (when it exists).
+// It returns true if --config was provided by the user.
+func readCodeAndConfigFile(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, tracker *configTracker) (bool, error) {
+	configExplicitlySet := false
+	before := tracker.snapshot(&context.Config)
+	if err := readVerboseDryrunConfigFileAndCode(boundary, context, &configExplicitlySet); err != nil {
+		return false, err
+	}
+	tracker.recordFirstPass(before, &context.Config)
+
+	before = tracker.snapshot(&context.Config)
+	if !context.Config.Code.IsSet() {
+		currentPath, err := boundary.GetCurrentPath()
+		if err != nil {
+			return false, err
+		}
+		context.Config.Code = nillable.NewNillableString(currentPath)
+	}
+	tracker.recordChanged(before, &context.Config, SourceDetected)
+	before = tracker.snapshot(&context.Config)
+	if !context.Config.Config.IsSet() {
+		codePath := context.Config.Code.ValueOr("")
+		configFile := filepath.Join(codePath, ".booth", "config.toml")
+		if fileExists(configFile) {
+			context.Config.Config = nillable.NewNillableString(configFile)
+		}
+	}
+	tracker.recordChanged(before, &context.Config, SourceDefault)
+	return configExplicitlySet, nil
 }
 
 // getProjectName extracts a sanitized project name from the code path
@@ -196,6 +259,8 @@ func parseArgs(args ilist.List[string], cfg *appctx.AppConfig) error {
 			i++
 		case "--dryrun":
 			i++
+		case "--explain":
+			i++
 
 		// Simple flags
 		case "--daemon":
@@ -214,6 +279,14 @@ func parseArgs(args ilist.List[string], cfg *appctx.AppConfig) error {
 			cfg.Pull = true
 			i++
 
+		case "--open":
+			cfg.Open = true
+			i++
+
+		case "--wait":
+			cfg.Wait = true
+			i++
+
 		case "--silence-build":
 			cfg.SilenceBuild = true
 			i++
@@ -227,6 +300,22 @@ func parseArgs(args ilist.List[string], cfg *appctx.AppConfig) error {
 			cfg.Image = v
 			i += 2
 
+		case "--engine":
+			v, err := needValue(args, i, arg)
+			if err != nil {
+				return err
+			}
+			cfg.Engine = v
+			i += 2
+
+		case "--profile":
+			v, err := needValue(args, i, arg)
+			if err != nil {
+				return err
+			}
+			cfg.Profile = v
+			i += 2
+
 		case "--variant":
 			v, err := needValue(args, i, arg)
 			if err != nil {
@@ -278,6 +367,22 @@ func parseArgs(args ilist.List[string], cfg *appctx.AppConfig) error {
 			cfg.Port = v
 			i += 2
 
+		case "--bind-address":
+			v, err := needValue(args, i, arg)
+			if err != nil {
+				return err
+			}
+			cfg.BindAddress = v
+			i += 2
+
+		case "--dind-cache":
+			v, err := needValue(args, i, arg)
+			if err != nil {
+				return err
+			}
+			cfg.DindCache = v
+			i += 2
+
 		case "--env-file":
 			v, err := needValue(args, i, arg)
 			if err != nil {
@@ -317,52 +422,141 @@ func parseArgs(args ilist.List[string], cfg *appctx.AppConfig) error {
 
 // readFromArgs parses command-line arguments and populates the config (overriding existing values).
 // It preserves verbose, dryrun, code, and config.
-func readFromArgs(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, args ilist.List[string]) {
-	runPreserveCodeAndConfig(context, func() {
+func readFromArgs(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, args ilist.List[string]) error {
+	return runPreserveCodeAndConfig(context, func() error {
 		if err := parseArgs(args, &context.Config); err != nil {
-			panic(fmt.Errorf("failed to parse args: %w", err))
+			return fmt.Errorf("failed to parse args: %w", err)
 		}
+		return nil
 	})
 }
 
 // readFromEnvVars reads configuration from environment variables and populates the config (overriding existing values).
 // The function preserve the verbose, dryrun and config values.
-func readFromEnvVars(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder) {
-	runPreserveCodeAndConfig(context, func() {
+func readFromEnvVars(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder) error {
+	return runPreserveCodeAndConfig(context, func() error {
 		if err := boundary.PopulateAppConfigFromEnvVars(&context.Config); err != nil {
-			panic(fmt.Errorf("failed to populate app config from env vars: %w", err))
+			return fmt.Errorf("failed to populate app config from env vars: %w", err)
 		}
+		return nil
 	})
 }
 
-// readFromToml reads configuration from a TOML file and populates the config (overriding existing values).
-// It preserves verbose, dryrun, code, and config.
-// If configExplicitlySet is true, the config file must exist. Otherwise, it's optional.
-func readFromToml(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, configExplicitlySet bool) {
-	if !context.Config.Config.IsSet() {
-		return
+// configLayer is a config file read by readFromToml.
+type configLayer struct {
+	path     string
+	required bool
+}
+
+// configLayers returns the config files in the order they are applied (later ones win):
+// the user config, the project config (--config or <code>/.booth/config.toml) and the local config (<code>/.booth/config.local.toml).
+func configLayers(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, configExplicitlySet bool) []configLayer {
+	layers := []configLayer{}
+	if userConfig := boundary.GetUserConfigFile(); userConfig != "" {
+		layers = append(layers, configLayer{path: userConfig})
+	}
+	if context.Config.Config.IsSet() {
+		layers = append(layers, configLayer{path: context.Config.Config.ValueOrPanic(), required: configExplicitlySet})
+	}
+	if context.Config.Code.IsSet() {
+		layers = append(layers, configLayer{path: filepath.Join(context.Config.Code.ValueOrPanic(), ".booth", "config.local.toml")})
+	}
+	return layers
+}
+
+// existingConfigLayers returns the config layers whose file exists.
+// It returns an error if a required one (the --config file) does not.
+func existingConfigLayers(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, configExplicitlySet bool) ([]configLayer, error) {
+	layers := []configLayer{}
+	for _, layer := range configLayers(boundary, context, configExplicitlySet) {
+		if _, err := os.Stat(layer.path); os.IsNotExist(err) {
+			// Only fail if the config file was explicitly set by the user
+			if layer.required {
+				return nil, fmt.Errorf("config file %s does not exist", layer.path)
+			}
+			continue
+		}
+		layers = append(layers, layer)
 	}
+	return layers, nil
+}
 
-	runPreserveCodeAndConfig(context, func() {
-		cfgFile := context.Config.Config.ValueOrPanic()
-		if _, err := os.Stat(cfgFile); os.IsNotExist(err) {
-			// Only panic if the config file was explicitly set by the user
-			if configExplicitlySet {
-				panic(fmt.Errorf("config file %s does not exist", cfgFile))
+// readFromToml reads the config layers (see configLayers) and populates the config (overriding existing values).
+// run-args, build-args and common-args of a layer append to the ones of the lower layers (see appctx.MergeFromToml).
+// The `[profile.<name>]` table of the selected profile is applied over each layer; the profile must be defined in one of them.
+// The files are validated first (see appctx.ValidateToml) and their problems returned as a ConfigValidationError.
+// It preserves verbose, dryrun, code, and config.
+// If configExplicitlySet is true, the project config file must exist. Otherwise, missing layers are skipped.
+func readFromToml(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, configExplicitlySet bool, tracker *configTracker) error {
+	layers, err := existingConfigLayers(boundary, context, configExplicitlySet)
+	if err != nil {
+		return err
+	}
+	// Fail fast (before any merge) with the problems of all the files
+	problems := []appctx.ConfigProblem{}
+	for _, layer := range layers {
+		problems = append(problems, appctx.ValidateToml(layer.path)...)
+	}
+	if len(problems) > 0 {
+		return &appctx.ConfigValidationError{Problems: problems}
+	}
+	warnLegacyConfig(context, layers)
+
+	profile := context.Config.Profile
+	profileFound := false
+	profiles := []string{}
+	fileLists := map[string]bool{}
+	for _, layer := range layers {
+		before := tracker.snapshot(&context.Config)
+		err := runPreserveCodeAndConfig(context, func() error {
+			defined, err := appctx.MergeFromToml(layer.path, &context.Config, profile, fileLists)
+			var validationErr *appctx.ConfigValidationError
+			if errors.As(err, &validationErr) {
+				// Missing required variables (with the file and line)
+				return err
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read toml config %s: %w", layer.path, err)
 			}
-			// If it's the default config file, just skip reading it
-			return
+			for key := range defined {
+				fileLists[key] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		tracker.recordToml(before, &context.Config, layer.path, profile)
+
+		layerProfiles, _ := appctx.ProfilesOf(layer.path)
+		profileFound = profileFound || slices.Contains(layerProfiles, profile)
+		profiles = append(profiles, layerProfiles...)
+	}
+
+	if profile != "" && !profileFound {
+		slices.Sort(profiles)
+		return fmt.Errorf("unknown profile '%s' (profiles: %s)", profile, strings.Join(slices.Compact(profiles), ", "))
+	}
+	return nil
+}
+
+// profileFromArgs returns the value of --profile (before "--"), if given.
+func profileFromArgs(args ilist.List[string]) (string, bool) {
+	for i := 0; i < args.Length()-1; i++ {
+		if args.At(i) == "--" {
+			break
 		}
-		if err := appctx.ReadFromToml(cfgFile, &context.Config); err != nil {
-			panic(fmt.Errorf("failed to read toml config: %w", err))
+		if args.At(i) == "--profile" {
+			return args.At(i + 1), true
 		}
-	})
+	}
+	return "", false
 }
 
 // readVerboseDryrunConfigFileAndCode parses arguments looking for config file and verbosity settings.
 // This allows loading configuration before full argument parsing.
 // It sets configExplicitlySet to true if --config was provided by the user.
-func readVerboseDryrunConfigFileAndCode(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, configExplicitlySet *bool) {
+func readVerboseDryrunConfigFileAndCode(boundary InitializeAppContextBoundary, context *appctx.AppContextBuilder, configExplicitlySet *bool) error {
 	args := boundary.ArgList()
 	for i := 0; i < args.Length(); {
 		arg := args.At(i)
@@ -370,7 +564,7 @@ func readVerboseDryrunConfigFileAndCode(boundary InitializeAppContextBoundary, c
 		case "--config":
 			value, err := needValue(args, i, arg)
 			if err != nil {
-				panic(fmt.Errorf("error parsing --config: %w", err))
+				return fmt.Errorf("error parsing --config: %w", err)
 			}
 			// Resolve relative paths to absolute paths
 			if !filepath.IsAbs(value) {
@@ -385,7 +579,7 @@ func readVerboseDryrunConfigFileAndCode(boundary InitializeAppContextBoundary, c
 		case "--code":
 			value, err := needValue(args, i, arg)
 			if err != nil {
-				panic(fmt.Errorf("error parsing --code: %w", err))
+				return fmt.Errorf("error parsing --code: %w", err)
 			}
 			context.Config.Code = nillable.NewNillableString(value)
 			i += 2
@@ -402,13 +596,14 @@ func readVerboseDryrunConfigFileAndCode(boundary InitializeAppContextBoundary, c
 			i++
 		}
 	}
+	return nil
 }
 
-func runPreserveCodeAndConfig(context *appctx.AppContextBuilder, fn func()) {
+func runPreserveCodeAndConfig(context *appctx.AppContextBuilder, fn func() error) error {
 	configFile := context.Config.Config
 	code := context.Config.Code
 
-	fn()
+	err := fn()
 
 	if configFile.IsSet() {
 		context.Config.Config = configFile
@@ -416,6 +611,7 @@ func runPreserveCodeAndConfig(context *appctx.AppContextBuilder, fn func()) {
 	if code.IsSet() {
 		context.Config.Code = code
 	}
+	return err
 }
 
 // fileExists checks if a file exists and is not a directory
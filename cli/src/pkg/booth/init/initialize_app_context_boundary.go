@@ -19,11 +19,14 @@ type InitializeAppContextBoundary interface {
 	DetectTimezone() string
 
 	// getCurrentPath returns the current working directory, handling MSYS/Git Bash on Windows
-	GetCurrentPath() string
+	GetCurrentPath() (string, error)
 
 	// getHostUID returns the current user's UID as a string
 	GetHostUID() string
 
 	// getHostGID returns the current user's GID as a string
 	GetHostGID() string
+
+	// GetUserConfigFile returns the user-level config file (e.g. ~/.config/codingbooth/config.toml); "" for none
+	GetUserConfigFile() string
 }
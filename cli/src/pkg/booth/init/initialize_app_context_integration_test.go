@@ -54,15 +54,9 @@ func TestIntegration_InitializeAppContext_ScenarioC_DefaultConfigAndCliConfig_Cl
 	}
 }
 
-// Scenario D — With CLI config but the file does not exist, should panic.
+// Scenario D — With CLI config but the file does not exist, should fail.
 func TestIntegration_InitializeAppContext_ScenarioD_DefaultConfigAndCliConfig_CliConfigWins(t *testing.T) {
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatal("expected panic when CLI-specified config file doesn't exist, but didn't panic")
-		}
-	}()
-
-	_ = RunInitializeAppContext(t, TestInput{
+	res := RunInitializeAppContext(t, TestInput{
 		EnvMap: map[string]string{},
 		Args: []string{
 			"--config", "sub-folder-Cli/.booth/config.toml",
@@ -72,23 +66,25 @@ func TestIntegration_InitializeAppContext_ScenarioD_DefaultConfigAndCliConfig_Cl
 			Content: `variant = "from-default-config"`,
 		}},
 	})
+
+	if res.Err == nil {
+		t.Fatal("expected an error when CLI-specified config file doesn't exist, but got none")
+	}
 }
 
-// Scenario E — With CLI config but the file does not exist, should panic.
+// Scenario E — With CLI config but the file does not exist, should fail.
 func TestIntegration_InitializeAppContext_ScenarioE_DefaultConfigAndCliConfig_CliConfigWins(t *testing.T) {
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatal("expected panic when CLI-specified config file doesn't exist, but didn't panic")
-		}
-	}()
-
-	_ = RunInitializeAppContext(t, TestInput{
+	res := RunInitializeAppContext(t, TestInput{
 		EnvMap: map[string]string{},
 		Args: []string{
 			"--config", "sub-folder-Cli/.booth/config.toml",
 		},
 		TomlFiles: []TomlFile{},
 	})
+
+	if res.Err == nil {
+		t.Fatal("expected an error when CLI-specified config file doesn't exist, but got none")
+	}
 }
 
 // Scenario D — With default and ENV config, the default config should win
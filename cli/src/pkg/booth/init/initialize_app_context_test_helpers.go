@@ -46,12 +46,17 @@ type TestInput struct {
 
 	// HostGID to use for the test
 	HostGID string
+
+	// UserConfig is the user-level config file (relative to the temp directory); "" for none.
+	// Write it with TomlFiles.
+	UserConfig string
 }
 
 // TestOutcome is what the helper returns.
 type TestOutcome struct {
 	CodeDir     string            // the temp dir the helper chdir'd into
 	Ctx         appctx.AppContext // result of InitializeAppContext
+	Err         error             // error returned by InitializeAppContext
 	FinalConfig appctx.AppConfig  // snapshot of bootstrap booth/config (best effort)
 }
 
@@ -198,7 +203,7 @@ func RunInitializeAppContext(test *testing.T, input TestInput) TestOutcome {
 	}
 
 	// Run
-	ctx := InitializeAppContext("latest", input)
+	ctx, err := InitializeAppContext("latest", input)
 
 	// Best-effort snapshot (bootstrap workspace/config inferred)
 	final := appctx.AppConfig{}
@@ -208,6 +213,7 @@ func RunInitializeAppContext(test *testing.T, input TestInput) TestOutcome {
 	return TestOutcome{
 		CodeDir:     booth,
 		Ctx:         ctx,
+		Err:         err,
 		FinalConfig: final,
 	}
 }
@@ -228,8 +234,8 @@ func (input TestInput) DetectTimezone() string {
 	return input.Timezone
 }
 
-func (input TestInput) GetCurrentPath() string {
-	return input.CurrentPath
+func (input TestInput) GetCurrentPath() (string, error) {
+	return input.CurrentPath, nil
 }
 
 func (input TestInput) GetHostUID() string {
@@ -239,3 +245,7 @@ func (input TestInput) GetHostUID() string {
 func (input TestInput) GetHostGID() string {
 	return input.HostGID
 }
+
+func (input TestInput) GetUserConfigFile() string {
+	return input.UserConfig
+}
@@ -6,18 +6,34 @@ package booth
 
 import (
 	"fmt"
+	"maps"
 	"math/rand"
 	"net"
-	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
 )
 
-// PortDetermination determines the host port and returns updated AppContext.
-func PortDetermination(ctx appctx.AppContext) appctx.AppContext {
+// PortDetermination determines the host port and the host ports of the [ports] of the config and returns updated AppContext.
+// The generated (NEXT and RANDOM) ports are reserved until the booth runs (see portAllocator).
+// It returns an InvalidPortError for a malformed port, an InvalidBindAddressError for a bind-address that is not an IP,
+// a PortUnavailableError when no free port is found and a DuplicateHostPortError when two ports are published on the same host port.
+func PortDetermination(ctx appctx.AppContext) (appctx.AppContext, error) {
+	if address := bindAddress(ctx); net.ParseIP(address) == nil {
+		return ctx, &InvalidBindAddressError{Value: address}
+	} else if !isLocalAddress(address) {
+		if !ctx.Dryrun() && ctx.Cmds().Length() == 0 {
+			return ctx, &InvalidBindAddressError{Value: address, NotLocal: true}
+		}
+		// Reported only: the ports are then checked on all interfaces (see newPortAllocator)
+		fmt.Printf("⚠️  The bind-address %s is not an address of this machine: docker cannot publish the booth ports on it.\n", address)
+	}
+
 	builder := ctx.ToBuilder()
+	allocator := newPortAllocator(ctx)
 
 	boothPort := ctx.Port()
 	upperPort := strings.ToUpper(boothPort)
@@ -27,53 +43,164 @@ func PortDetermination(ctx appctx.AppContext) appctx.AppContext {
 	switch upperPort {
 	case "RANDOM":
 		// Generate random ports in increments of 1000 (10000, 11000, 12000, etc.)
-		portNumber, portGenerated = findRandomPort()
+		portNumber, portGenerated = findRandomPort(allocator.isAvailable)
 		if !portGenerated {
-			fmt.Fprintln(os.Stderr, "Error: unable to find a free RANDOM port above 10000.")
-			os.Exit(1)
+			allocator.release()
+			return ctx, &PortUnavailableError{Mode: "RANDOM"}
 		}
 
 	case "NEXT":
 		// Find next available port starting from 10000 in increments of 1000
-		portNumber, portGenerated = findNextPort()
+		portNumber, portGenerated = findNextPort(allocator.isAvailable)
 		if !portGenerated {
-			fmt.Fprintln(os.Stderr, "Error: unable to find the NEXT free port above 10000.")
-			os.Exit(1)
+			allocator.release()
+			return ctx, &PortUnavailableError{Mode: "NEXT"}
 		}
 
 	default:
 		// User-specified port: validate it
 		port, err := strconv.Atoi(boothPort)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: --port must be a number (got '%s').\n", boothPort)
-			os.Exit(1)
+			return ctx, &InvalidPortError{Value: boothPort}
 		}
 		if port < 1 || port > 65535 {
-			fmt.Fprintf(os.Stderr, "Error: --port must be between 1 and 65535 (got '%s').\n", boothPort)
-			os.Exit(1)
+			return ctx, &InvalidPortError{Value: boothPort, OutOfRange: true}
 		}
 		portNumber = port
 		portGenerated = false
 	}
 
+	namedPorts, err := allocateNamedPorts(ctx.Ports(), portNumber, allocator)
+	if err != nil {
+		allocator.release()
+		return ctx, err
+	}
+
 	builder.PortNumber = portNumber
 	builder.PortGenerated = portGenerated
+	builder.NamedPorts = namedPorts
+	builder.ReservedPorts = allocator.reserved
+
+	return builder.Build(), nil
+}
+
+// ShowPortBanner prints the port selection banner when a port was generated (or in verbose mode)
+// and warns when the ports are published on all interfaces.
+// It is a separate stage so nothing is printed when an earlier check failed.
+func ShowPortBanner(ctx appctx.AppContext) (appctx.AppContext, error) {
+	if ctx.Cmds().Length() != 0 {
+		return ctx, nil
+	}
+	if ctx.PortGenerated() || namedPortsGenerated(ctx) || ctx.Verbose() {
+		printPortBanner(ctx)
+	}
+	if address := bindAddress(ctx); onAllInterfaces(address) {
+		fmt.Printf("⚠️  The booth ports are published on all interfaces (bind-address = %s): anyone on your network can reach the booth.\n", address)
+	}
+	return ctx, nil
+}
+
+// hostPortKey identifies a published host port (the same port can be published for tcp and udp).
+type hostPortKey struct {
+	port     int
+	protocol string
+}
+
+// allocateNamedPorts returns the host ports of the [ports] of the config by name, allocated in name order:
+// NEXT is the first free port from the container port up, RANDOM is a random free port above 10000
+// and a fixed host port (or a single port, published on the same host port) is used as is.
+// A host port cannot be used twice (nor be the booth port), nor two entries be exported as the same variable (see namedPortEnv).
+func allocateNamedPorts(ports map[string]appctx.PortMapping, boothPort int, allocator *portAllocator) (map[string]int, error) {
+	allocated := map[string]int{}
+	usedBy := map[hostPortKey]string{{boothPort, "tcp"}: "the booth port"}
+	exportedBy := map[string]string{}
+	isAvailable := func(protocol string) func(int) bool {
+		return func(port int) bool {
+			_, taken := usedBy[hostPortKey{port, protocol}]
+			return !taken && allocator.isAvailable(port)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(ports)) {
+		env := namedPortEnv(name)
+		if other, exported := exportedBy[env]; exported {
+			return nil, &DuplicatePortEnvError{Name: name, Other: other, Env: env}
+		}
+		exportedBy[env] = name
+
+		port := ports[name]
+		protocol := port.Protocol()
+		mode := port.HostPort()
+
+		var hostPort int
+		switch mode {
+		case "NEXT":
+			hostPort = findNextPortFrom(port.ContainerPort(), isAvailable(protocol))
+		case "RANDOM":
+			hostPort = findRandomNamedPort(isAvailable(protocol))
+		case "":
+			hostPort = port.ContainerPort()
+		default:
+			hostPort, _ = strconv.Atoi(mode)
+		}
+		if hostPort == 0 {
+			return nil, &PortUnavailableError{Mode: mode, Name: name}
+		}
+		if other, taken := usedBy[hostPortKey{hostPort, protocol}]; taken {
+			return nil, &DuplicateHostPortError{Name: name, Port: hostPort, UsedBy: other}
+		}
+		usedBy[hostPortKey{hostPort, protocol}] = "ports." + name
+		allocated[name] = hostPort
+	}
+	return allocated, nil
+}
+
+// findNextPortFrom finds the first available port from the given one up (0 if none).
+func findNextPortFrom(from int, isAvailable func(int) bool) int {
+	for port := from; port >= 1 && port <= 65535; port++ {
+		if isAvailable(port) {
+			return port
+		}
+	}
+	return 0
+}
+
+// findRandomNamedPort finds a random available port from 10000 up (0 if none).
+func findRandomNamedPort(isAvailable func(int) bool) int {
+	for i := 0; i < 200; i++ {
+		port := 10000 + rand.Intn(65535-10000+1)
+		if isAvailable(port) {
+			return port
+		}
+	}
+	return 0
+}
 
-	if (portGenerated || ctx.Verbose()) && ctx.Cmds().Length() == 0 {
-		printPortBanner(portNumber)
+// namedPortsGenerated tells if a host port of the [ports] was allocated (NEXT or RANDOM).
+func namedPortsGenerated(ctx appctx.AppContext) bool {
+	for _, port := range ctx.Ports() {
+		if mode := port.HostPort(); mode == "NEXT" || mode == "RANDOM" {
+			return true
+		}
 	}
+	return false
+}
+
+var nonEnvNameChars = regexp.MustCompile(`[^A-Z0-9_]`)
 
-	return builder.Build()
+// namedPortEnv returns the environment variable of the host port of a [ports] entry (e.g. CB_PORT_DEV_SERVER for dev-server).
+func namedPortEnv(name string) string {
+	return "CB_PORT_" + nonEnvNameChars.ReplaceAllString(strings.ToUpper(name), "_")
 }
 
-// findRandomPort finds a random free port in increments of 1000.
-func findRandomPort() (int, bool) {
+// findRandomPort finds a random available port in increments of 1000.
+func findRandomPort(isAvailable func(int) bool) (int, bool) {
 	numSlots := (65000-10000)/1000 + 1 // 56 slots
 
 	for i := 0; i < 200; i++ {
 		slot := rand.Intn(numSlots)
 		port := 10000 + (slot * 1000)
-		if isPortFree(port) {
+		if isAvailable(port) {
 			return port, true
 		}
 	}
@@ -81,20 +208,24 @@ func findRandomPort() (int, bool) {
 	return 0, false
 }
 
-// findNextPort finds the next free port starting from 10000 in increments of 1000.
-func findNextPort() (int, bool) {
+// findNextPort finds the next available port starting from 10000 in increments of 1000.
+func findNextPort(isAvailable func(int) bool) (int, bool) {
 	for port := 10000; port <= 65535; port += 1000 {
-		if isPortFree(port) {
+		if isAvailable(port) {
 			return port, true
 		}
 	}
 	return 0, false
 }
 
-// isPortFree checks if a port is available.
-func isPortFree(port int) bool {
+// isPortFree checks if a port is available on the bind address (on all interfaces for 0.0.0.0 or ::).
+func isPortFree(address string, port int) bool {
+	if onAllInterfaces(address) {
+		address = ""
+	}
+
 	// Try to listen on the port
-	addr := fmt.Sprintf(":%d", port)
+	addr := net.JoinHostPort(address, strconv.Itoa(port))
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		// Port is in use
@@ -104,14 +235,21 @@ func isPortFree(port int) bool {
 	return true
 }
 
-// printPortBanner prints the port selection banner.
-func printPortBanner(portNumber int) {
+// printPortBanner prints the port selection banner (with the host ports of the [ports] of the config).
+func printPortBanner(ctx appctx.AppContext) {
+	portNumber := ctx.PortNumber()
 	fmt.Println()
 	fmt.Println("============================================================")
 	fmt.Println("🚀 BOOTH PORT SELECTED")
 	fmt.Println("============================================================")
 	fmt.Printf("🔌 Using host port: \033[1;32m%d\033[0m -> container: \033[1;34m10000\033[0m\n", portNumber)
-	fmt.Printf("🌐 Open: http://localhost:%d\n", portNumber)
+	fmt.Printf("🌐 Open: %s\n", boothURL(ctx))
+	ports := ctx.Ports()
+	namedPorts := ctx.NamedPorts()
+	for _, name := range slices.Sorted(maps.Keys(namedPorts)) {
+		fmt.Printf("🔌 %s: host port \033[1;32m%d\033[0m -> container: \033[1;34m%d\033[0m (%s)\n",
+			name, namedPorts[name], ports[name].ContainerPort(), namedPortEnv(name))
+	}
 	fmt.Println("============================================================")
 	fmt.Println()
 }
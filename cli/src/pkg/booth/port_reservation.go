@@ -0,0 +1,236 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// portReservationTTL is how long a port reservation holds when its launch did not release it (e.g. it was killed).
+const portReservationTTL = 2 * time.Minute
+
+// maxPortAttempts is how many times the stages from the port determination run when a generated host port
+// is taken between its determination and `docker run` (see HostPortTakenError).
+const maxPortAttempts = 3
+
+// portAllocator picks the available host ports of a launch: free, not claimed by a booth container
+// (running or stopped, through its labels) and not reserved by a concurrent launch.
+// Outside dryrun, the ports it picks are reserved (see reservePort) until released.
+type portAllocator struct {
+	address  string
+	claimed  map[int]bool
+	reserve  bool
+	reserved []int
+}
+
+// newPortAllocator creates the port allocator of a launch.
+// The booth containers are only looked up when a port is generated (NEXT or RANDOM).
+func newPortAllocator(ctx appctx.AppContext) *portAllocator {
+	allocator := &portAllocator{address: bindAddress(ctx), claimed: map[int]bool{}, reserve: !ctx.Dryrun()}
+	if !isLocalAddress(allocator.address) {
+		// Nothing is free on an address of another machine (reported by PortDetermination)
+		allocator.address = "0.0.0.0"
+	}
+	if mode := strings.ToUpper(ctx.Port()); mode == "NEXT" || mode == "RANDOM" || namedPortsGenerated(ctx) {
+		allocator.claimed = claimedBoothPorts(ctx.Docker())
+	}
+	return allocator
+}
+
+// isAvailable tells if a port can be used by the launch on its bind address (and reserves it if so).
+func (allocator *portAllocator) isAvailable(port int) bool {
+	if allocator.claimed[port] || !isPortFree(allocator.address, port) {
+		return false
+	}
+	if allocator.reserve {
+		if !reservePort(port) {
+			return false
+		}
+		allocator.reserved = append(allocator.reserved, port)
+	}
+	return true
+}
+
+// release releases the ports reserved by the allocator.
+func (allocator *portAllocator) release() {
+	releasePorts(allocator.reserved)
+	allocator.reserved = nil
+}
+
+// portReservationDir returns the folder of the port reservations (in the user cache folder).
+func portReservationDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "codingbooth", "ports")
+}
+
+// portReservationFile returns the lock file of a port reservation.
+func portReservationFile(port int) string {
+	return filepath.Join(portReservationDir(), strconv.Itoa(port)+".lock")
+}
+
+// reservePort creates the lock file of a port (holding the process id), so concurrent launches do not pick it.
+// It returns false when another launch holds the port; a reservation older than portReservationTTL is stale and taken over.
+// Reservations are best effort: when the lock file cannot be written at all, the port is considered reserved.
+func reservePort(port int) bool {
+	if err := os.MkdirAll(portReservationDir(), 0o755); err != nil {
+		return true
+	}
+
+	path := portReservationFile(port)
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			fmt.Fprintf(file, "%d\n", os.Getpid())
+			file.Close()
+			return true
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return true
+		}
+
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// Released meanwhile
+		case err != nil || time.Since(info.ModTime()) < portReservationTTL:
+			return false
+		default:
+			// Stale: its launch did not release it
+			os.Remove(path)
+		}
+	}
+	return false
+}
+
+// releasePorts removes the port reservations of this process (the ones of other launches are left alone).
+func releasePorts(ports []int) {
+	pid := strconv.Itoa(os.Getpid())
+	for _, port := range ports {
+		path := portReservationFile(port)
+		if content, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(content)) == pid {
+			os.Remove(path)
+		}
+	}
+}
+
+// releasePortsWhenRunning releases the port reservations of the launch once its container is running
+// (docker binds the ports when the container starts), giving up after portReservationTTL.
+// The returned flag tells if the container was seen running.
+// The reservations are released anyway when the run ends (see BoothRunner.Run).
+func releasePortsWhenRunning(ctx appctx.AppContext) *atomic.Bool {
+	running := &atomic.Bool{}
+	ports := ctx.ReservedPorts()
+	if len(ports) == 0 || ctx.Dryrun() {
+		return running
+	}
+
+	go func() {
+		flags := docker.DockerFlags{Silent: true}
+		args := ilist.NewList(ilist.NewList("--format", "{{.State.Running}}", ctx.Name()))
+		for deadline := time.Now().Add(portReservationTTL); time.Now().Before(deadline); time.Sleep(500 * time.Millisecond) {
+			if output, err := ctx.Docker().Inspect(flags, args); err == nil && strings.TrimSpace(output) == "true" {
+				running.Store(true)
+				break
+			}
+		}
+		releasePorts(ports)
+	}()
+	return running
+}
+
+// isDockerRunFailure tells if a run failed in docker itself (exit code 125) rather than in the booth.
+func isDockerRunFailure(err error) bool {
+	var exitErr *docker.DockerExitError
+	var silentErr *SilentExitError
+	return (errors.As(err, &exitErr) && exitErr.ExitCode == 125) || (errors.As(err, &silentErr) && silentErr.ExitCode == 125)
+}
+
+// claimedBoothPorts returns the host ports of the booth containers, running or stopped (from their labels),
+// so a stopped booth kept with --keep-alive gets its ports back when started again.
+// Nothing is claimed when docker cannot be reached.
+func claimedBoothPorts(client docker.DockerClient) map[int]bool {
+	claimed := map[int]bool{}
+	output, err := client.Ps(docker.DockerFlags{Silent: true}, ilist.NewList(ilist.NewList(
+		"-a",
+		"--filter", "label="+LabelManaged+"=true",
+		"--format", `{{.Label "`+LabelPort+`"}}`+"\t"+`{{.Label "`+LabelPorts+`"}}`,
+	)))
+	if err != nil {
+		return claimed
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if port, err := strconv.Atoi(fields[0]); err == nil {
+			claimed[port] = true
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		for _, entry := range strings.Split(fields[1], ",") {
+			_, value, _ := strings.Cut(entry, "=")
+			if port, err := strconv.Atoi(value); err == nil {
+				claimed[port] = true
+			}
+		}
+	}
+	return claimed
+}
+
+// generatedPorts returns the host ports of the launch that were generated (NEXT or RANDOM),
+// the ones that can be determined again when they are taken.
+func generatedPorts(ctx appctx.AppContext) []int {
+	ports := []int{}
+	if ctx.PortGenerated() {
+		ports = append(ports, ctx.PortNumber())
+	}
+	configPorts := ctx.Ports()
+	for name, hostPort := range ctx.NamedPorts() {
+		if mode := configPorts[name].HostPort(); mode == "NEXT" || mode == "RANDOM" {
+			ports = append(ports, hostPort)
+		}
+	}
+	return ports
+}
+
+// takenGeneratedPort returns a generated host port of the launch that is not free anymore (0 if none):
+// after `docker run` failed, it is the port another launch took in the meantime.
+func takenGeneratedPort(ctx appctx.AppContext) int {
+	address := bindAddress(ctx)
+	if !isLocalAddress(address) {
+		return 0
+	}
+	for _, port := range generatedPorts(ctx) {
+		if !isPortFree(address, port) {
+			return port
+		}
+	}
+	return 0
+}
+
+// removeCreatedContainer removes the container of a failed `docker run` (created but never started), if any,
+// so the run can be retried with the same name.
+func removeCreatedContainer(ctx appctx.AppContext, name string) {
+	flags := docker.DockerFlags{Silent: true}
+	output, err := ctx.Docker().Ps(flags, ilist.NewList(ilist.NewList("-aq", "--filter", "name=^/"+name+"$", "--filter", "status=created")))
+	if err != nil || strings.TrimSpace(output) == "" {
+		return
+	}
+	_ = ctx.Docker().Command(flags, "rm", ilist.NewList(ilist.NewList("-f", name)))
+}
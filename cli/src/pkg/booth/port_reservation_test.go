@@ -0,0 +1,248 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+// isolatePortReservations keeps the port reservations of a test in its own cache folder.
+func isolatePortReservations(t *testing.T) {
+	t.Helper()
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+}
+
+// freePort returns a port that is free for now.
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestReservePort(t *testing.T) {
+	isolatePortReservations(t)
+	port := freePort(t)
+
+	if !reservePort(port) {
+		t.Fatalf("expected port %d to be reserved", port)
+	}
+	if reservePort(port) {
+		t.Errorf("expected port %d to be held by the first reservation", port)
+	}
+	releasePorts([]int{port})
+	if !reservePort(port) {
+		t.Errorf("expected port %d to be reserved again once released", port)
+	}
+
+	// The reservation of another launch is left alone, unless it is stale
+	other := portReservationFile(port)
+	if err := os.WriteFile(other, []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	releasePorts([]int{port})
+	if reservePort(port) {
+		t.Errorf("expected port %d to be held by another launch", port)
+	}
+	old := time.Now().Add(-2 * portReservationTTL)
+	if err := os.Chtimes(other, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if !reservePort(port) {
+		t.Errorf("expected the stale reservation of port %d to be taken over", port)
+	}
+}
+
+func TestClaimedBoothPorts(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		return "12000\t\n13000\tdebug=5005,web=3001\n\t\n", nil
+	}
+
+	claimed := claimedBoothPorts(client)
+
+	want := map[int]bool{12000: true, 13000: true, 5005: true, 3001: true}
+	if !reflect.DeepEqual(claimed, want) {
+		t.Errorf("claimedBoothPorts() = %v, want %v", claimed, want)
+	}
+	if commands := client.Commands(); len(commands) != 1 || !strings.Contains(commands[0], "ps -a --filter label=cb.managed=true") {
+		t.Errorf("commands = %q", commands)
+	}
+}
+
+func TestPortDetermination_SkipsClaimedAndReservedPorts(t *testing.T) {
+	isolatePortReservations(t)
+	web := freePort(t)
+
+	// web is claimed by a stopped booth and web+1 reserved by a concurrent launch
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		return "0\tweb=" + strconv.Itoa(web) + "\n", nil
+	}
+	if err := os.MkdirAll(portReservationDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(portReservationFile(web+1), []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := &appctx.AppContextBuilder{Cmds: ilist.NewAppendableList[ilist.List[string]](), Docker: client}
+	builder.Config.Port = "10000"
+	builder.Config.Ports = map[string]appctx.PortMapping{"web": appctx.PortMapping("NEXT:" + strconv.Itoa(web))}
+
+	ctx, err := PortDetermination(builder.Build())
+	if err != nil {
+		t.Fatalf("PortDetermination() returned error: %v", err)
+	}
+	hostPort := ctx.NamedPorts()["web"]
+	if hostPort <= web+1 {
+		t.Errorf("expected a host port after %d and %d, got %d", web, web+1, hostPort)
+	}
+	if reserved := ctx.ReservedPorts(); !reflect.DeepEqual(reserved, []int{hostPort}) {
+		t.Errorf("ReservedPorts() = %v, want [%d]", reserved, hostPort)
+	}
+	if reservePort(hostPort) {
+		t.Errorf("expected port %d to be reserved", hostPort)
+	}
+
+	releasePorts(ctx.ReservedPorts())
+	if _, err := os.Stat(portReservationFile(hostPort)); !os.IsNotExist(err) {
+		t.Errorf("expected the reservation of port %d to be released, got %v", hostPort, err)
+	}
+}
+
+func TestBoothRunner_RetriesTakenPort(t *testing.T) {
+	determinations := 0
+	runs := 0
+	runner := NewBoothRunnerWithStages(engineTestContext("docker").Build(), NewStageRegistry(
+		NewStage("before-ports", func(ctx appctx.AppContext) (appctx.AppContext, error) { return ctx, nil }),
+		NewStage(StagePortDetermination, func(ctx appctx.AppContext) (appctx.AppContext, error) {
+			determinations++
+			return ctx, nil
+		}),
+		NewStage(StageRun, func(ctx appctx.AppContext) (appctx.AppContext, error) {
+			runs++
+			if runs == 1 {
+				return ctx, &HostPortTakenError{Port: 12000, Err: errors.New("bind failed")}
+			}
+			return ctx, nil
+		}),
+	))
+
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	if determinations != 2 || runs != 2 {
+		t.Errorf("determinations = %d, runs = %d, want 2 and 2", determinations, runs)
+	}
+}
+
+func TestBoothRunner_RetryRunsHooksOnce(t *testing.T) {
+	code := t.TempDir()
+	runs := 0
+	builder := engineTestContext("docker")
+	builder.Config.Code = nillable.NewNillableString(code)
+	builder.Config.Hooks = map[string]string{
+		"before-port-determination": `echo "$CB_HOOK_NAME" >> record.txt`,
+		"after-port-determination":  `echo "$CB_HOOK_NAME" >> record.txt`,
+		"before-run":                `echo "$CB_HOOK_NAME" >> record.txt`,
+		"after-run":                 `echo "$CB_HOOK_NAME" >> record.txt`,
+	}
+	runner := NewBoothRunnerWithStages(builder.Build(), NewStageRegistry(
+		NewStage(StagePortDetermination, func(ctx appctx.AppContext) (appctx.AppContext, error) { return ctx, nil }),
+		NewStage(StageRun, func(ctx appctx.AppContext) (appctx.AppContext, error) {
+			runs++
+			if runs == 1 {
+				return ctx, &HostPortTakenError{Port: 12000, Err: errors.New("bind failed")}
+			}
+			return ctx, nil
+		}),
+	))
+
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(code, "record.txt"))
+	if err != nil {
+		t.Fatalf("hooks did not run: %v", err)
+	}
+	want := "before-port-determination\nafter-port-determination\nbefore-run\nafter-run\n"
+	if got := string(content); got != want {
+		t.Errorf("hooks output = %q, want %q", got, want)
+	}
+}
+
+func TestSetupDind_TakenPortRemovesCreatedNetwork(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	client := docker.NewRecordingDockerClient()
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		switch {
+		case call.Subcommand == "network" && call.Args[0] == "inspect":
+			return "", errors.New("no such network")
+		case call.Subcommand == "run":
+			return "", errors.New("port is already allocated")
+		}
+		return "", nil
+	}
+	builder := engineTestContext("docker")
+	builder.Docker = client
+	builder.Config.Dind = true
+	builder.Config.DindCache = "none"
+	builder.PortNumber = port
+	builder.PortGenerated = true
+
+	var taken *HostPortTakenError
+	if _, err := SetupDind(builder.Build()); !errors.As(err, &taken) {
+		t.Fatalf("expected HostPortTakenError, got %v", err)
+	}
+	network := "my-project-" + strconv.Itoa(port) + "-net"
+	commands := client.Commands()
+	if last := commands[len(commands)-1]; last != "docker network rm "+network {
+		t.Errorf("expected the created network to be removed, got:\n%s", strings.Join(commands, "\n"))
+	}
+}
+
+func TestBoothRunner_GivesUpOnTakenPort(t *testing.T) {
+	runs := 0
+	runner := NewBoothRunnerWithStages(engineTestContext("docker").Build(), NewStageRegistry(
+		NewStage(StagePortDetermination, func(ctx appctx.AppContext) (appctx.AppContext, error) { return ctx, nil }),
+		NewStage(StageRun, func(ctx appctx.AppContext) (appctx.AppContext, error) {
+			runs++
+			return ctx, &HostPortTakenError{Port: 12000, Err: errors.New("bind failed")}
+		}),
+	))
+
+	var taken *HostPortTakenError
+	if err := runner.Run(); !errors.As(err, &taken) {
+		t.Fatalf("expected HostPortTakenError, got %v", err)
+	}
+	if runs != maxPortAttempts {
+		t.Errorf("runs = %d, want %d", runs, maxPortAttempts)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"golang.org/x/term"
+)
+
+// readinessTimeout is how long the booth UI has to answer once the container runs.
+const readinessTimeout = 2 * time.Minute
+
+// readinessInterval is the time between two polls of the booth UI.
+const readinessInterval = 500 * time.Millisecond
+
+// spinnerFrames are the frames of the spinner shown while waiting for the booth UI (on a terminal).
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// WaitReady waits (with a spinner) for the booth UI to answer after a daemon run with --wait or --open,
+// then shows its URL (not shown by the daemon run then) and opens it in the host browser with --open.
+// It returns a ServiceNotReadyError when the UI does not answer within readinessTimeout.
+func WaitReady(ctx appctx.AppContext) (appctx.AppContext, error) {
+	service := boothService(ctx)
+	if service == "" {
+		fmt.Printf("⚠️  The %s variant has no web UI to wait for.\n", ctx.Variant())
+		return ctx, nil
+	}
+
+	url := boothURL(ctx)
+	if !waitForService(url, service, readinessTimeout, readinessInterval, term.IsTerminal(int(os.Stdout.Fd()))) {
+		return ctx, &ServiceNotReadyError{Service: service, URL: url, Timeout: readinessTimeout}
+	}
+	fmt.Printf("👉 Visit '%s'\n", url)
+	if ctx.Open() {
+		openBrowser(url, false)
+	}
+	return ctx, nil
+}
+
+// skipWaitReady tells if the WaitReady stage is skipped: it only runs for a daemon run with --wait or --open
+// (in the foreground, --open is handled while the booth runs; see openWhenReady).
+func skipWaitReady(ctx appctx.AppContext) bool {
+	return ctx.Dryrun() || ctx.RunMode() != "DAEMON" || !(ctx.Wait() || ctx.Open())
+}
+
+// openWhenReady opens the booth UI in the host browser once it answers, while a foreground booth runs (with --open).
+// Nothing is printed while waiting: the terminal belongs to the booth.
+func openWhenReady(ctx appctx.AppContext) {
+	service := boothService(ctx)
+	if !ctx.Open() || ctx.Dryrun() || ctx.RunMode() != "FOREGROUND" || service == "" {
+		return
+	}
+
+	go func() {
+		url := boothURL(ctx)
+		if pollService(url, readinessTimeout, readinessInterval, nil) {
+			openBrowser(url, true)
+		}
+	}()
+}
+
+// boothService returns the name of the web UI of the variant ("" for a variant without one).
+func boothService(ctx appctx.AppContext) string {
+	switch {
+	case ctx.HasDesktop():
+		return "noVNC desktop"
+	case ctx.HasVscode():
+		return "code-server"
+	case ctx.HasNotebook():
+		return "Jupyter notebook"
+	}
+	return ""
+}
+
+// boothURL returns the URL of the booth UI on the host, at the address its port is published on (see reachableHost).
+func boothURL(ctx appctx.AppContext) string {
+	return "http://" + net.JoinHostPort(reachableHost(bindAddress(ctx)), strconv.Itoa(ctx.PortNumber()))
+}
+
+// waitForService polls the service until it answers (true) or the timeout (false), showing a spinner on a terminal.
+func waitForService(url string, service string, timeout time.Duration, interval time.Duration, spinner bool) bool {
+	if !spinner {
+		fmt.Printf("⏳ Waiting for %s at %s ...\n", service, url)
+	}
+
+	frame := 0
+	ready := pollService(url, timeout, interval, func(elapsed time.Duration) {
+		if spinner {
+			fmt.Printf("\r%s Waiting for %s at %s (%ds)", spinnerFrames[frame%len(spinnerFrames)], service, url, int(elapsed.Seconds()))
+			frame++
+		}
+	})
+	if spinner {
+		// Clear the spinner line
+		fmt.Print("\r\033[K")
+	}
+
+	if ready {
+		fmt.Printf("✅ %s is ready.\n", service)
+	} else {
+		fmt.Printf("❌ %s did not answer at %s within %s.\n", service, url, timeout)
+	}
+	return ready
+}
+
+// pollService polls the URL until it answers with any response below 500 (true) or the timeout (false);
+// tick (optional) is called before each poll with the time spent so far.
+func pollService(url string, timeout time.Duration, interval time.Duration, tick func(time.Duration)) bool {
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+		// A redirect (e.g. to the login page) is an answer
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	start := time.Now()
+	for time.Since(start) < timeout {
+		if tick != nil {
+			tick(time.Since(start))
+		}
+		if response, err := client.Get(url); err == nil {
+			response.Body.Close()
+			if response.StatusCode < 500 {
+				return true
+			}
+		}
+		time.Sleep(interval)
+	}
+	return false
+}
+
+// waitsForUI tells if the WaitReady stage waits for the booth UI (which then shows its URL).
+func waitsForUI(ctx appctx.AppContext) bool {
+	return !skipWaitReady(ctx) && boothService(ctx) != ""
+}
+
+// openBrowser opens the URL in the host browser (best effort: a failure is only reported).
+// When quiet (while a foreground booth owns the terminal), nothing is printed.
+func openBrowser(url string, quiet bool) {
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", url)
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		command = exec.Command("xdg-open", url)
+	}
+
+	if err := command.Start(); err != nil {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not open the browser (%v); open %s\n", err, url)
+		}
+		return
+	}
+	go command.Wait()
+	if !quiet {
+		fmt.Printf("🌐 Opened %s in the browser.\n", url)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func TestPollService(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls++
+		if calls < 3 {
+			// Still starting
+			writer.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		http.Redirect(writer, request, "/login", http.StatusFound)
+	}))
+	defer server.Close()
+
+	if !pollService(server.URL, 5*time.Second, 10*time.Millisecond, nil) {
+		t.Fatalf("expected the service to be ready")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (the redirect is an answer)", calls)
+	}
+
+	server.Close()
+	if pollService(server.URL, 100*time.Millisecond, 10*time.Millisecond, nil) {
+		t.Errorf("expected a closed service not to be ready")
+	}
+}
+
+func TestWaitReady_Daemon(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverURL.Port())
+
+	builder := engineTestContext("docker")
+	builder.Config.Wait = true
+	builder.RunMode = "DAEMON"
+	builder.PortNumber = port
+	builder.HasNotebook = true
+	builder.HasVscode = true
+	ctx := builder.Build()
+
+	if skipWaitReady(ctx) {
+		t.Fatalf("expected the wait-ready stage to run for a daemon with --wait")
+	}
+	if service := boothService(ctx); service != "code-server" {
+		t.Errorf("boothService() = %q, want code-server", service)
+	}
+
+	// Capture stdout
+	oldStdout := os.Stdout
+	reader, writer, _ := os.Pipe()
+	os.Stdout = writer
+
+	_, err := WaitReady(ctx)
+
+	writer.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, reader)
+
+	if err != nil {
+		t.Errorf("WaitReady() returned error: %v", err)
+	}
+	// The URL is shown once the UI is ready (besides the waiting line)
+	_, ready, _ := strings.Cut(buf.String(), "✅")
+	if visit := "http://localhost:" + serverURL.Port(); strings.Count(ready, visit) != 1 || !strings.Contains(ready, "👉 Visit '"+visit+"'") {
+		t.Errorf("expected the Visit URL once after the ready line, got:\n%s", buf.String())
+	}
+}
+
+func TestSkipWaitReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		runMode string
+		wait    bool
+		open    bool
+		skip    bool
+	}{
+		{"daemon", "DAEMON", false, false, true},
+		{"daemon with --wait", "DAEMON", true, false, false},
+		{"daemon with --open", "DAEMON", false, true, false},
+		{"foreground with --open", "FOREGROUND", false, true, true},
+		{"command with --wait", "COMMAND", true, false, true},
+	}
+	for _, tt := range tests {
+		builder := &appctx.AppContextBuilder{RunMode: tt.runMode}
+		builder.Config.Wait = tt.wait
+		builder.Config.Open = tt.open
+		if got := skipWaitReady(builder.Build()); got != tt.skip {
+			t.Errorf("%s: skipWaitReady() = %t, want %t", tt.name, got, tt.skip)
+		}
+	}
+}
+
+func TestWaitsForUI(t *testing.T) {
+	builder := engineTestContext("docker")
+	builder.RunMode = "DAEMON"
+	builder.Config.Wait = true
+	if waitsForUI(builder.Build()) {
+		t.Errorf("expected no wait for a variant without a web UI (the daemon run shows the URL)")
+	}
+
+	builder.HasVscode = true
+	if !waitsForUI(builder.Build()) {
+		t.Errorf("expected the wait-ready stage to show the URL of code-server")
+	}
+
+	builder.Config.Dryrun = nillable.NewNillableBool(true)
+	if waitsForUI(builder.Build()) {
+		t.Errorf("expected no wait in dryrun (the daemon run shows the URL)")
+	}
+}
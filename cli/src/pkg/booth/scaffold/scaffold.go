@@ -0,0 +1,277 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+// Package scaffold creates the .booth folder of a project (Dockerfile and config.toml) from a template.
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed all:templates
+var builtinFiles embed.FS
+
+// commonTemplate holds the files used by the templates that do not have their own (e.g. the Dockerfile).
+const commonTemplate = "_common"
+
+// metadataFile is the file of a template folder with its description, variant, args and setups.
+const metadataFile = "template.toml"
+
+// BuiltinSource is the Source of the templates embedded in the binary.
+const BuiltinSource = "built-in"
+
+// Template is a template of the .booth folder: its files are rendered with TemplateData.
+type Template struct {
+	Name        string
+	Description string   `toml:"description"`
+	Variant     string   `toml:"variant"`
+	Args        []string `toml:"args"`
+	Setups      []string `toml:"setups"`
+	// Source is BuiltinSource or the folder of a local template.
+	Source string `toml:"-"`
+
+	files fs.FS
+}
+
+// TemplateData is the data the template files are rendered with.
+type TemplateData struct {
+	Template string
+	Project  string
+	Variant  string
+	Version  string
+	// PinVersion writes the version in config.toml (when given with --version).
+	PinVersion bool
+	Args       []string
+	Setups     []string
+}
+
+// UnknownTemplateError is returned by FindTemplate for a template that does not exist.
+type UnknownTemplateError struct {
+	Name      string
+	Available []string
+}
+
+func (e *UnknownTemplateError) Error() string {
+	return fmt.Sprintf("unknown template '%s' (templates: %s)", e.Name, strings.Join(e.Available, ", "))
+}
+
+// ExistingFilesError is returned by Scaffold when files of the template already exist (and force is not set).
+type ExistingFilesError struct {
+	Paths []string
+}
+
+func (e *ExistingFilesError) Error() string {
+	return fmt.Sprintf("not overwriting existing files (use --force): %s", strings.Join(e.Paths, ", "))
+}
+
+// Templates returns the built-in templates and the ones in localDir (if not empty), sorted by name.
+// A local template replaces the built-in one with the same name.
+func Templates(localDir string) ([]Template, error) {
+	builtin, err := fs.Sub(builtinFiles, "templates")
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]Template{}
+	if err := readTemplates(builtin, BuiltinSource, byName); err != nil {
+		return nil, err
+	}
+	if localDir != "" {
+		if info, err := os.Stat(localDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("template folder '%s' does not exist", localDir)
+		}
+		if err := readTemplates(os.DirFS(localDir), localDir, byName); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := []Template{}
+	for _, name := range sortedNames(byName) {
+		templates = append(templates, byName[name])
+	}
+	return templates, nil
+}
+
+// FindTemplate returns the template with the name (see Templates).
+func FindTemplate(name string, localDir string) (Template, error) {
+	templates, err := Templates(localDir)
+	if err != nil {
+		return Template{}, err
+	}
+	names := []string{}
+	for _, found := range templates {
+		if found.Name == name {
+			return found, nil
+		}
+		names = append(names, found.Name)
+	}
+	return Template{}, &UnknownTemplateError{Name: name, Available: names}
+}
+
+// readTemplates reads the template folders of root (one per folder, except _common).
+func readTemplates(root fs.FS, source string, byName map[string]Template) error {
+	entries, err := fs.ReadDir(root, ".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == commonTemplate || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		files, err := fs.Sub(root, entry.Name())
+		if err != nil {
+			return err
+		}
+		found := Template{Name: entry.Name(), Source: source, files: files}
+		if data, err := fs.ReadFile(files, metadataFile); err == nil {
+			if err := toml.Unmarshal(data, &found); err != nil {
+				return fmt.Errorf("template %s: %s: %w", entry.Name(), metadataFile, err)
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if found.Variant == "" {
+			found.Variant = "base"
+		}
+		byName[found.Name] = found
+	}
+	return nil
+}
+
+// Render returns the rendered files of the template by their path in the .booth folder.
+// The files of _common (the Dockerfile and config.toml) are used when the template does not have its own.
+func (tmpl Template) Render(data TemplateData) (map[string][]byte, error) {
+	sources := map[string]fs.FS{}
+	common, err := fs.Sub(builtinFiles, "templates/"+commonTemplate)
+	if err != nil {
+		return nil, err
+	}
+	for _, files := range []fs.FS{common, tmpl.files} {
+		err := fs.WalkDir(files, ".", func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || path == metadataFile {
+				return err
+			}
+			sources[path] = files
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rendered := map[string][]byte{}
+	for path, files := range sources {
+		text, err := fs.ReadFile(files, path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := template.New(path).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", tmpl.Name, err)
+		}
+		var out bytes.Buffer
+		if err := parsed.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("template %s: %w", tmpl.Name, err)
+		}
+		rendered[path] = out.Bytes()
+	}
+	return rendered, nil
+}
+
+// Scaffold renders the template into the .booth folder of code and returns the written files (sorted).
+// It returns an ExistingFilesError (and writes nothing) when some of the files exist, unless force is set.
+func Scaffold(code string, tmpl Template, data TemplateData, force bool) ([]string, error) {
+	rendered, err := tmpl.Render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	boothDir := filepath.Join(code, ".booth")
+	paths := []string{}
+	existing := []string{}
+	for _, name := range sortedNames(rendered) {
+		path := filepath.Join(boothDir, filepath.FromSlash(name))
+		paths = append(paths, path)
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	if len(existing) > 0 && !force {
+		return nil, &ExistingFilesError{Paths: existing}
+	}
+
+	for _, name := range sortedNames(rendered) {
+		path := filepath.Join(boothDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		mode := os.FileMode(0o644)
+		if strings.HasSuffix(name, ".sh") {
+			mode = 0o755
+		}
+		if err := os.WriteFile(path, rendered[name], mode); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func sortedNames[V any](values map[string]V) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// PickTemplate asks for a template (by number or name) on in, listing the templates on out.
+func PickTemplate(templates []Template, in io.Reader, out io.Writer) (Template, error) {
+	fmt.Fprintln(out, "Templates:")
+	WriteTemplateList(out, templates)
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprintf(out, "Template [1-%d or name]: ", len(templates))
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer != "" {
+			for i, found := range templates {
+				if answer == found.Name || answer == strconv.Itoa(i+1) {
+					return found, nil
+				}
+			}
+			fmt.Fprintf(out, "No template '%s'.\n", answer)
+		}
+		if err != nil {
+			return Template{}, errors.New("no template selected")
+		}
+	}
+}
+
+// WriteTemplateList writes the numbered list of the templates: name, variant, description and source (if local).
+func WriteTemplateList(out io.Writer, templates []Template) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, found := range templates {
+		description := found.Description
+		if found.Source != BuiltinSource {
+			description += " (" + found.Source + ")"
+		}
+		fmt.Fprintf(writer, "  %d)\t%s\t%s\t%s\n", i+1, found.Name, found.Variant, description)
+	}
+	writer.Flush()
+}
@@ -0,0 +1,148 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+func templateData(tmpl Template) TemplateData {
+	return TemplateData{
+		Template: tmpl.Name,
+		Project:  "my-project",
+		Variant:  tmpl.Variant,
+		Version:  "latest",
+		Args:     tmpl.Args,
+		Setups:   tmpl.Setups,
+	}
+}
+
+func TestTemplates_BuiltinAreValid(t *testing.T) {
+	templates, err := Templates("")
+	if err != nil {
+		t.Fatalf("Templates() returned error: %v", err)
+	}
+	for _, name := range []string{"base", "go", "python", "java", "js", "dind", "kind"} {
+		if _, err := FindTemplate(name, ""); err != nil {
+			t.Errorf("FindTemplate(%q) returned error: %v", name, err)
+		}
+	}
+
+	for _, tmpl := range templates {
+		code := t.TempDir()
+		written, err := Scaffold(code, tmpl, templateData(tmpl), false)
+		if err != nil {
+			t.Fatalf("%s: Scaffold() returned error: %v", tmpl.Name, err)
+		}
+		if len(written) != 2 {
+			t.Errorf("%s: written = %q, want the Dockerfile and config.toml", tmpl.Name, written)
+		}
+
+		dockerfile, _ := os.ReadFile(filepath.Join(code, ".booth", "Dockerfile"))
+		if !strings.Contains(string(dockerfile), "ARG CB_VARIANT_TAG="+tmpl.Variant) {
+			t.Errorf("%s: the Dockerfile does not default to the variant:\n%s", tmpl.Name, dockerfile)
+		}
+		for _, setup := range tmpl.Setups {
+			if !strings.Contains(string(dockerfile), "RUN ./"+setup+"\n") {
+				t.Errorf("%s: the Dockerfile does not run %q:\n%s", tmpl.Name, setup, dockerfile)
+			}
+		}
+		if problems := appctx.ValidateToml(filepath.Join(code, ".booth", "config.toml")); len(problems) > 0 {
+			t.Errorf("%s: config.toml is not valid: %v", tmpl.Name, problems)
+		}
+	}
+}
+
+func TestScaffold_DoesNotOverwrite(t *testing.T) {
+	tmpl, _ := FindTemplate("base", "")
+	code := t.TempDir()
+	configPath := filepath.Join(code, ".booth", "config.toml")
+	os.MkdirAll(filepath.Dir(configPath), 0o755)
+	os.WriteFile(configPath, []byte("variant = \"notebook\"\n"), 0o644)
+
+	_, err := Scaffold(code, tmpl, templateData(tmpl), false)
+	var existing *ExistingFilesError
+	if !errors.As(err, &existing) || len(existing.Paths) != 1 || existing.Paths[0] != configPath {
+		t.Fatalf("Scaffold() error = %v, want an ExistingFilesError for %s", err, configPath)
+	}
+	if _, err := os.Stat(filepath.Join(code, ".booth", "Dockerfile")); err == nil {
+		t.Errorf("nothing should be written when a file exists")
+	}
+
+	if _, err := Scaffold(code, tmpl, templateData(tmpl), true); err != nil {
+		t.Fatalf("Scaffold() with force returned error: %v", err)
+	}
+	if content, _ := os.ReadFile(configPath); !strings.Contains(string(content), `variant = "base"`) {
+		t.Errorf("expected config.toml to be overwritten with force, got:\n%s", content)
+	}
+}
+
+func TestTemplates_LocalDir(t *testing.T) {
+	local := t.TempDir()
+	os.MkdirAll(filepath.Join(local, "team-go", "home-seed"), 0o755)
+	os.WriteFile(filepath.Join(local, "team-go", "template.toml"), []byte(`description = "Our Go booth"
+variant = "codeserver"
+setups = ["go--setup.sh"]
+`), 0o644)
+	os.WriteFile(filepath.Join(local, "team-go", "home-seed", "init.sh"), []byte("echo {{.Project}}\n"), 0o644)
+	os.MkdirAll(filepath.Join(local, "base"), 0o755)
+	os.WriteFile(filepath.Join(local, "base", "config.toml"), []byte("variant = \"{{.Variant}}\" # ours\n"), 0o644)
+
+	tmpl, err := FindTemplate("team-go", local)
+	if err != nil {
+		t.Fatalf("FindTemplate() returned error: %v", err)
+	}
+	if tmpl.Source != local || tmpl.Variant != "codeserver" {
+		t.Errorf("template = %+v", tmpl)
+	}
+	files, err := tmpl.Render(templateData(tmpl))
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+	if got := string(files["home-seed/init.sh"]); got != "echo my-project\n" {
+		t.Errorf("home-seed/init.sh = %q", got)
+	}
+	if !strings.Contains(string(files["Dockerfile"]), "RUN ./go--setup.sh") {
+		t.Errorf("expected the common Dockerfile with the setups, got:\n%s", files["Dockerfile"])
+	}
+
+	base, _ := FindTemplate("base", local)
+	files, _ = base.Render(templateData(base))
+	if got := string(files["config.toml"]); got != "variant = \"base\" # ours\n" {
+		t.Errorf("expected the local base template to replace the built-in one, got %q", got)
+	}
+
+	if _, err := Templates(filepath.Join(local, "missing")); err == nil {
+		t.Errorf("expected an error for a missing template folder")
+	}
+}
+
+func TestPickTemplate(t *testing.T) {
+	templates, _ := Templates("")
+	var out strings.Builder
+
+	picked, err := PickTemplate(templates, strings.NewReader("nope\n3\n"), &out)
+	if err != nil || picked.Name != templates[2].Name {
+		t.Errorf("PickTemplate() = %q, %v, want %q", picked.Name, err, templates[2].Name)
+	}
+	if !strings.Contains(out.String(), "No template 'nope'.") {
+		t.Errorf("expected a message for an unknown answer, got:\n%s", out.String())
+	}
+
+	picked, err = PickTemplate(templates, strings.NewReader("python"), &out)
+	if err != nil || picked.Name != "python" {
+		t.Errorf("PickTemplate() = %q, %v, want python", picked.Name, err)
+	}
+
+	if _, err := PickTemplate(templates, strings.NewReader(""), &out); err == nil {
+		t.Errorf("expected an error without an answer")
+	}
+}
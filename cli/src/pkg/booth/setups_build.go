@@ -0,0 +1,69 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/appctx"
+)
+
+// setupsDockerfile returns the Dockerfile of an image with the setups installed over the prebuilt image.
+// Each setup is its own layer, in the order of the config, so adding a setup at the end (or changing one version)
+// reuses the cached layers of the setups before it.
+func setupsDockerfile(prebuildRepo string, setups []appctx.SetupSpec) string {
+	var str strings.Builder
+	str.WriteString("# syntax=docker/dockerfile:1.7\n")
+	str.WriteString("# Generated by CodingBooth from the setups of the config (no Dockerfile).\n")
+	str.WriteString("ARG CB_VARIANT_TAG=base\n")
+	str.WriteString("ARG CB_VERSION_TAG=latest\n")
+	fmt.Fprintf(&str, "FROM %s:${CB_VARIANT_TAG}-${CB_VERSION_TAG}\n\n", prebuildRepo)
+	str.WriteString("SHELL [\"/bin/bash\",\"-o\",\"pipefail\",\"-lc\"]\n")
+	str.WriteString("USER root\n\n")
+	str.WriteString("ARG CB_SETUPS=/opt/codingbooth/setups\n")
+	str.WriteString("WORKDIR ${CB_SETUPS}\n\n")
+	for _, setup := range setups {
+		// Fail with a clear message for a setup the image does not have (e.g. an older version)
+		fmt.Fprintf(&str, "RUN test -x ./%s || { echo \"Setup '%s' is not in this image (%s)\" >&2; exit 1; }", setup.Script(), setup.Name, setup.Script())
+		fmt.Fprintf(&str, " && ./%s", setup.Script())
+		if setup.Version != "" {
+			fmt.Fprintf(&str, " %s", setup.Version)
+		}
+		str.WriteString("\n")
+	}
+	str.WriteString("\nWORKDIR /home/coder/code\n")
+	return str.String()
+}
+
+// writeSetupsBuildContext writes the Dockerfile of the setups in a new temporary folder (the build context)
+// and returns the folder and the Dockerfile; the caller removes the folder.
+func writeSetupsBuildContext(ctx appctx.AppContext) (string, string, error) {
+	dir, err := os.MkdirTemp("", "codingbooth-setups-")
+	if err != nil {
+		return "", "", err
+	}
+	dockerfile := filepath.Join(dir, "Dockerfile")
+	content := setupsDockerfile(ctx.PrebuildRepo(), ctx.Setups())
+	if err := os.WriteFile(dockerfile, []byte(content), 0o644); err != nil {
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+	if ctx.Verbose() {
+		fmt.Printf("Generated Dockerfile (setups):\n%s\n", content)
+	}
+	return dir, dockerfile, nil
+}
+
+// setupsNames returns the setups as they are written in the config (e.g. "go@1.23, gh").
+func setupsNames(setups []appctx.SetupSpec) string {
+	names := []string{}
+	for _, setup := range setups {
+		names = append(names, setup.String())
+	}
+	return strings.Join(names, ", ")
+}
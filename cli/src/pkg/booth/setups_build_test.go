@@ -0,0 +1,97 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package booth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+	"github.com/nawaman/codingbooth/src/pkg/nillable"
+)
+
+func TestEnsureDockerImage_Setups(t *testing.T) {
+	client := docker.NewRecordingDockerClient()
+	dockerfile := ""
+	contextDir := ""
+	client.Respond = func(call docker.DockerCall) (string, error) {
+		if call.Subcommand == "build" {
+			content, err := os.ReadFile(call.Args[1])
+			if err != nil {
+				t.Errorf("cannot read the generated Dockerfile: %v", err)
+			}
+			dockerfile = string(content)
+			contextDir = call.Args[len(call.Args)-1]
+		}
+		return "", nil
+	}
+
+	builder := engineTestContext("docker")
+	builder.Config.Code = nillable.NewNillableString(t.TempDir())
+	builder.Config.Variant = "base"
+	builder.Config.ProjectName = "my-project"
+	builder.Version = "0.13.0"
+	builder.PrebuildRepo = "nawaman/codingbooth"
+	builder.Config.Setups.List = ilist.NewList("go@1.23", "nodejs@22", "gh")
+	builder.Docker = client
+
+	ctx, err := EnsureDockerImage(builder.Build())
+	if err != nil {
+		t.Fatalf("EnsureDockerImage() returned error: %v", err)
+	}
+	if ctx.ImageMode() != "SETUPS-BUILD" || ctx.Image() != "codingbooth-local:my-project-base-0.13.0" {
+		t.Errorf("ImageMode() = %q, Image() = %q", ctx.ImageMode(), ctx.Image())
+	}
+	if ctx.Dockerfile() != "" {
+		t.Errorf("the generated Dockerfile should not be the config dockerfile: %q", ctx.Dockerfile())
+	}
+
+	wantRuns := []string{
+		"&& ./go--setup.sh 1.23\n",
+		"&& ./nodejs--setup.sh 22\n",
+		"&& ./gh--setup.sh\n",
+	}
+	previous := -1
+	for _, run := range wantRuns {
+		index := strings.Index(dockerfile, run)
+		if index <= previous {
+			t.Errorf("expected %q after the previous setups in:\n%s", run, dockerfile)
+		}
+		previous = index
+	}
+	if !strings.Contains(dockerfile, "FROM nawaman/codingbooth:${CB_VARIANT_TAG}-${CB_VERSION_TAG}") {
+		t.Errorf("expected the prebuilt image as the base:\n%s", dockerfile)
+	}
+	if _, err := os.Stat(contextDir); !os.IsNotExist(err) || filepath.Dir(contextDir) != filepath.Clean(os.TempDir()) {
+		t.Errorf("expected the temporary build context %q to be removed", contextDir)
+	}
+}
+
+func TestEnsureDockerImage_SetupsIgnoredWithDockerfile(t *testing.T) {
+	code := t.TempDir()
+	os.MkdirAll(filepath.Join(code, ".booth"), 0o755)
+	os.WriteFile(filepath.Join(code, ".booth", "Dockerfile"), []byte("FROM alpine\n"), 0o644)
+
+	client := docker.NewRecordingDockerClient()
+	builder := engineTestContext("docker")
+	builder.Config.Code = nillable.NewNillableString(code)
+	builder.Config.Setups.List = ilist.NewList("gh")
+	builder.Docker = client
+
+	ctx, err := EnsureDockerImage(builder.Build())
+	if err != nil {
+		t.Fatalf("EnsureDockerImage() returned error: %v", err)
+	}
+	if ctx.ImageMode() != "LOCAL-BUILD" {
+		t.Errorf("ImageMode() = %q, want LOCAL-BUILD", ctx.ImageMode())
+	}
+	build := client.Commands()[0]
+	if !strings.Contains(build, "-f "+filepath.Join(code, ".booth", "Dockerfile")) || !strings.HasSuffix(build, " "+code) {
+		t.Errorf("expected the project Dockerfile and the code as the context: %s", build)
+	}
+}
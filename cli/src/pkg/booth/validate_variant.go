@@ -5,34 +5,21 @@
 package booth
 
 import (
-	"fmt"
-	"os"
 	"strings"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
 )
 
 // ValidateVariant validates and normalizes the variant and returns updated AppContext.
-func ValidateVariant(ctx appctx.AppContext) appctx.AppContext {
+// It returns an UnknownVariantError for an unknown variant.
+func ValidateVariant(ctx appctx.AppContext) (appctx.AppContext, error) {
 	builder := ctx.ToBuilder()
 	variant := ctx.Variant()
 
 	// Step 1: Normalize variant aliases
-	switch variant {
-	case "base", "notebook", "codeserver", "desktop-xfce", "desktop-kde":
-		// Valid variants, no change needed
-	case "default", "console":
-		variant = "base"
-	case "ide":
-		variant = "codeserver"
-	case "desktop":
-		variant = "desktop-xfce"
-	case "xfce", "kde":
-		variant = "desktop-" + variant
-	default:
-		fmt.Fprintf(os.Stderr, "Error: unknown --variant '%s' (valid: base|notebook|codeserver|desktop-xfce|desktop-kde;\n", variant)
-		fmt.Fprintln(os.Stderr, "       aliases: console|ide|desktop|xfce|kde)")
-		os.Exit(1)
+	variant, ok := NormalizeVariant(variant)
+	if !ok {
+		return ctx, &UnknownVariantError{Variant: ctx.Variant()}
 	}
 
 	builder.Config.Variant = variant
@@ -56,9 +43,26 @@ func ValidateVariant(ctx appctx.AppContext) appctx.AppContext {
 		builder.HasVscode = true
 		builder.HasDesktop = true
 	default:
-		fmt.Fprintf(os.Stderr, "Error: unknown variant '%s'.\n", variant)
-		os.Exit(1)
+		return ctx, &UnknownVariantError{Variant: variant}
 	}
 
-	return builder.Build()
+	return builder.Build(), nil
+}
+
+// NormalizeVariant returns the variant of a variant name or alias (e.g. "desktop-xfce" for "xfce"),
+// and false if it is unknown.
+func NormalizeVariant(variant string) (string, bool) {
+	switch variant {
+	case "base", "notebook", "codeserver", "desktop-xfce", "desktop-kde":
+		return variant, true
+	case "default", "console":
+		return "base", true
+	case "ide":
+		return "codeserver", true
+	case "desktop":
+		return "desktop-xfce", true
+	case "xfce", "kde":
+		return "desktop-" + variant, true
+	}
+	return variant, false
 }
@@ -5,6 +5,7 @@
 package booth
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/nawaman/codingbooth/src/pkg/appctx"
@@ -126,7 +127,10 @@ func TestValidateVariant(t *testing.T) {
 			ctx := builder.Build()
 
 			// Execute
-			gotCtx := ValidateVariant(ctx)
+			gotCtx, err := ValidateVariant(ctx)
+			if err != nil {
+				t.Fatalf("ValidateVariant() returned error: %v", err)
+			}
 
 			// Assert Variant
 			if gotCtx.Variant() != tt.wantVariant {
@@ -135,3 +139,15 @@ func TestValidateVariant(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateVariant_Unknown(t *testing.T) {
+	builder := &appctx.AppContextBuilder{}
+	builder.Config.Variant = "bogus"
+
+	_, err := ValidateVariant(builder.Build())
+
+	var variantErr *UnknownVariantError
+	if !errors.As(err, &variantErr) || variantErr.Variant != "bogus" {
+		t.Fatalf("expected UnknownVariantError for 'bogus', got %v", err)
+	}
+}
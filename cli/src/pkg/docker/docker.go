@@ -2,7 +2,7 @@
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
 
-// Package docker provides Docker CLI execution with verbose and dryrun support.
+// Package docker provides Docker CLI (or podman/nerdctl) execution with verbose and dryrun support.
 package docker
 
 import (
@@ -25,8 +25,12 @@ var (
 
 // hasBuildKitSupport checks if Docker BuildKit is available.
 // BuildKit supports the --progress flag; the legacy builder does not.
+// nerdctl always builds with BuildKit and podman (buildah) has no --progress.
 // This function caches the result for efficiency.
-func hasBuildKitSupport() bool {
+func hasBuildKitSupport(executable string) bool {
+	if executable != "docker" {
+		return executable == "nerdctl"
+	}
 	buildKitOnce.Do(func() {
 		// Check if DOCKER_BUILDKIT=1 is explicitly set
 		if os.Getenv("DOCKER_BUILDKIT") == "1" {
@@ -65,7 +69,13 @@ func (e *DockerExitError) Error() string {
 
 // Docker executes a docker command with the given subcommand and arguments.
 // If silent is true, suppresses all stdout/stderr from the docker process.
+// For run and exec, -i is always added and -t only when stdin and stdout are terminals.
 func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) error {
+	return runDocker("docker", flags, subcommand, args)
+}
+
+// runDocker is Docker running the given CLI (docker, podman or nerdctl).
+func runDocker(executable string, flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) error {
 	// Preserve current behavior: print the command for dry-run or verbose,
 	// even if silent is true (matches "contract" of showing what would run).
 	if flags.Dryrun || flags.Verbose {
@@ -73,7 +83,7 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 		printingArgs = append(printingArgs, []string{subcommand})
 
 		// For build commands, add --progress=auto if BuildKit is available and not already set
-		if subcommand == "build" && hasBuildKitSupport() {
+		if subcommand == "build" && hasBuildKitSupport(executable) {
 			hasProgress := false
 			args.Range(func(_ int, group ilist.List[string]) bool {
 				group.Range(func(_ int, arg string) bool {
@@ -92,7 +102,7 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 
 		// - Always add -i (interactive, keeps stdin open)
 		// - Add -t only when we have a TTY (allocates a pseudo-TTY)
-		if subcommand == "run" {
+		if subcommand == "run" || subcommand == "exec" {
 			runFlags := []string{"-i"}
 			if HasInteractiveTTY() {
 				runFlags = append(runFlags, "-t")
@@ -109,7 +119,7 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 			return true
 		})
 
-		printCmd("docker", printingArgs...)
+		printCmd(executable, printingArgs...)
 	}
 
 	if flags.Dryrun {
@@ -121,7 +131,7 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 
 	// - Always add -i (interactive, keeps stdin open)
 	// - Add -t only when we have a TTY (allocates a pseudo-TTY)
-	if subcommand == "run" {
+	if subcommand == "run" || subcommand == "exec" {
 		cmdArgs = append(cmdArgs, "-i")
 		if HasInteractiveTTY() {
 			cmdArgs = append(cmdArgs, "-t")
@@ -129,7 +139,7 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 	}
 
 	// For build commands, add --progress=auto if BuildKit is available and not already set
-	if subcommand == "build" && hasBuildKitSupport() {
+	if subcommand == "build" && hasBuildKitSupport(executable) {
 		hasProgress := false
 		args.Range(func(_ int, group ilist.List[string]) bool {
 			group.Range(func(_ int, arg string) bool {
@@ -151,7 +161,7 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 		return true
 	})
 
-	cmd := exec.Command("docker", cmdArgs...)
+	cmd := exec.Command(executable, cmdArgs...)
 
 	// Set environment for Windows path compatibility and color output
 	env := append(os.Environ(), "MSYS_NO_PATHCONV=1")
@@ -207,13 +217,18 @@ func Docker(flags DockerFlags, subcommand string, args ilist.List[ilist.List[str
 // This is useful for commands like "docker ps" where we need to check the output.
 // The function respects Dryrun and Verbose flags for printing, but always captures output when not in dryrun mode.
 func DockerOutput(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error) {
+	return runDockerOutput("docker", flags, subcommand, args)
+}
+
+// runDockerOutput is DockerOutput running the given CLI (docker, podman or nerdctl).
+func runDockerOutput(executable string, flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error) {
 	// Print command if dryrun or verbose (same as Docker function)
 	if flags.Dryrun || flags.Verbose {
 		var printingArgs [][]string
 		printingArgs = append(printingArgs, []string{subcommand})
 
 		// For build commands, add --progress=auto if BuildKit is available and not already set
-		if subcommand == "build" && hasBuildKitSupport() {
+		if subcommand == "build" && hasBuildKitSupport(executable) {
 			hasProgress := false
 			args.Range(func(_ int, group ilist.List[string]) bool {
 				group.Range(func(_ int, arg string) bool {
@@ -248,7 +263,7 @@ func DockerOutput(flags DockerFlags, subcommand string, args ilist.List[ilist.Li
 			return true
 		})
 
-		printCmd("docker", printingArgs...)
+		printCmd(executable, printingArgs...)
 	}
 
 	if flags.Dryrun {
@@ -268,7 +283,7 @@ func DockerOutput(flags DockerFlags, subcommand string, args ilist.List[ilist.Li
 	}
 
 	// For build commands, add --progress=auto if BuildKit is available and not already set
-	if subcommand == "build" && hasBuildKitSupport() {
+	if subcommand == "build" && hasBuildKitSupport(executable) {
 		hasProgress := false
 		args.Range(func(_ int, group ilist.List[string]) bool {
 			group.Range(func(_ int, arg string) bool {
@@ -290,7 +305,7 @@ func DockerOutput(flags DockerFlags, subcommand string, args ilist.List[ilist.Li
 		return true
 	})
 
-	cmd := exec.Command("docker", cmdArgs...)
+	cmd := exec.Command(executable, cmdArgs...)
 
 	// Set environment for Windows path compatibility and color output
 	env := append(os.Environ(), "MSYS_NO_PATHCONV=1")
@@ -17,9 +17,14 @@ import (
 // DockerBuild executes a docker build command with optional silent mode.
 // When SilenceBuild is enabled, it captures stderr and only displays it on failure.
 func DockerBuild(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return runDockerBuild("docker", flags, args)
+}
+
+// runDockerBuild is DockerBuild running the given CLI (docker, podman or nerdctl).
+func runDockerBuild(executable string, flags DockerFlags, args ilist.List[ilist.List[string]]) error {
 	// If not in silent mode, just call Docker build normally
 	if !flags.Silent {
-		return Docker(flags, "build", args)
+		return runDocker(executable, flags, "build", args)
 	}
 
 	// Silent mode: capture stderr and only show on failure
@@ -38,14 +43,14 @@ func DockerBuild(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
 			printingArgs = append(printingArgs, group.Slice())
 			return true
 		})
-		printCmd("docker", printingArgs...)
+		printCmd(executable, printingArgs...)
 	}
 
 	if flags.Dryrun {
 		return nil
 	}
 
-	cmd := exec.Command("docker", cmdArgs...)
+	cmd := exec.Command(executable, cmdArgs...)
 
 	// Set environment (same as Docker function)
 	env := append(os.Environ(), "MSYS_NO_PATHCONV=1")
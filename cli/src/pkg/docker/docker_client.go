@@ -0,0 +1,93 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"io"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// DockerClient runs docker commands.
+// The args are grouped like the docker CLI arguments (same as Docker and DockerOutput).
+// CliDockerClient (the default) shells out to the docker CLI; RecordingDockerClient records the commands for tests.
+type DockerClient interface {
+	// Run runs `docker run` with the output going to the terminal.
+	Run(flags DockerFlags, args ilist.List[ilist.List[string]]) error
+	// Build runs `docker build` (output is only shown on failure when flags.Silent).
+	Build(flags DockerFlags, args ilist.List[ilist.List[string]]) error
+	// Pull runs `docker pull <image>`.
+	Pull(flags DockerFlags, image string) error
+	// Inspect runs `docker inspect` and returns its output.
+	Inspect(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error)
+	// Network runs `docker network <args>` and returns its output.
+	Network(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error)
+	// Ps runs `docker ps` and returns its output.
+	Ps(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error)
+	// Stop runs `docker stop`.
+	Stop(flags DockerFlags, args ilist.List[ilist.List[string]]) error
+
+	// Command runs any other docker subcommand with the output going to the terminal.
+	Command(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) error
+	// Output runs any other docker subcommand and returns its output.
+	Output(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error)
+	// Stream runs any other docker subcommand with its output going to the given writers.
+	Stream(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]], stdout io.Writer, stderr io.Writer) error
+}
+
+// CliDockerClient is the DockerClient that runs the docker CLI (see Docker, DockerOutput, DockerBuild and DockerStream)
+// or, with Executable, another CLI with the same commands (podman or nerdctl).
+type CliDockerClient struct {
+	// Executable is the CLI to run ("" means docker).
+	Executable string
+}
+
+// executable returns the CLI run by the client.
+func (client CliDockerClient) executable() string {
+	if client.Executable == "" {
+		return "docker"
+	}
+	return client.Executable
+}
+
+func (client CliDockerClient) Run(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return runDocker(client.executable(), flags, "run", args)
+}
+
+func (client CliDockerClient) Build(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return runDockerBuild(client.executable(), flags, args)
+}
+
+func (client CliDockerClient) Pull(flags DockerFlags, image string) error {
+	return runDocker(client.executable(), flags, "pull", ilist.NewList(ilist.NewList(image)))
+}
+
+func (client CliDockerClient) Inspect(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return runDockerOutput(client.executable(), flags, "inspect", args)
+}
+
+func (client CliDockerClient) Network(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return runDockerOutput(client.executable(), flags, "network", args)
+}
+
+func (client CliDockerClient) Ps(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return runDockerOutput(client.executable(), flags, "ps", args)
+}
+
+func (client CliDockerClient) Stop(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return runDocker(client.executable(), flags, "stop", args)
+}
+
+func (client CliDockerClient) Command(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) error {
+	return runDocker(client.executable(), flags, subcommand, args)
+}
+
+func (client CliDockerClient) Output(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error) {
+	return runDockerOutput(client.executable(), flags, subcommand, args)
+}
+
+func (client CliDockerClient) Stream(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]], stdout io.Writer, stderr io.Writer) error {
+	return runDockerStream(client.executable(), flags, subcommand, args, stdout, stderr)
+}
@@ -0,0 +1,122 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+const (
+	// EngineCli runs the docker CLI (the default).
+	EngineCli = "cli"
+	// EngineApi talks to the Docker Engine API directly.
+	EngineApi = "api"
+	// EngineDocker is the same as EngineCli.
+	EngineDocker = "docker"
+	// EnginePodman runs the podman CLI (rootless friendly).
+	EnginePodman = "podman"
+	// EngineNerdctl runs the nerdctl CLI (containerd).
+	EngineNerdctl = "nerdctl"
+)
+
+// Engine describes a container engine CLI and how it differs from docker for the booth.
+type Engine struct {
+	// Name is the engine name (docker, podman or nerdctl).
+	Name string
+	// Executable is the CLI to run.
+	Executable string
+	// PullNever is the `run` flag that forbids pulling the image.
+	PullNever []string
+	// KeepID maps the host user into the container with --userns=keep-id (instead of remapping the coder user to the host UID/GID).
+	KeepID bool
+	// HostCgroups tells if the DinD sidecar can share the host cgroups (not with rootless podman).
+	HostCgroups bool
+	// QualifyImages tells if Docker Hub images must be fully qualified (podman has no default registry).
+	QualifyImages bool
+	// DesktopInfo is the `info --format` template that tells if the engine runs in a desktop VM and desktopMarker its value then.
+	DesktopInfo   string
+	desktopMarker string
+}
+
+var engines = map[string]Engine{
+	EngineDocker: {
+		Name:          EngineDocker,
+		Executable:    "docker",
+		PullNever:     []string{"--pull=never"},
+		HostCgroups:   true,
+		DesktopInfo:   "{{.OperatingSystem}}",
+		desktopMarker: "Docker Desktop",
+	},
+	EnginePodman: {
+		Name:          EnginePodman,
+		Executable:    "podman",
+		PullNever:     []string{"--pull=never"},
+		KeepID:        true,
+		QualifyImages: true,
+		DesktopInfo:   "{{.Host.ServiceIsRemote}}",
+		desktopMarker: "true",
+	},
+	EngineNerdctl: {
+		Name:          EngineNerdctl,
+		Executable:    "nerdctl",
+		PullNever:     []string{"--pull", "never"},
+		HostCgroups:   true,
+		DesktopInfo:   "{{.OperatingSystem}}",
+		desktopMarker: "Rancher Desktop",
+	},
+}
+
+// EngineOf returns the Engine of an engine name; cli, api and unknown names are docker.
+func EngineOf(name string) Engine {
+	if engine, ok := engines[name]; ok {
+		return engine
+	}
+	return engines[EngineDocker]
+}
+
+// Image returns the image reference to use with the engine (e.g. "docker:dind" is "docker.io/library/docker:dind" for podman).
+func (engine Engine) Image(image string) string {
+	if !engine.QualifyImages {
+		return image
+	}
+	first, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !found {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + image
+}
+
+// IsDesktop tells if the engine runs inside a desktop VM (Docker Desktop, podman machine, Rancher Desktop)
+// where the host cgroups cannot be shared with the DinD sidecar.
+func (engine Engine) IsDesktop(client DockerClient, flags DockerFlags) bool {
+	if flags.Dryrun {
+		return false
+	}
+	flags.Silent = true
+	output, err := client.Output(flags, "info", ilist.NewList(ilist.NewList("--format", engine.DesktopInfo)))
+	return err == nil && strings.Contains(output, engine.desktopMarker)
+}
+
+// NewDockerClient returns the DockerClient for an engine name ("" means EngineCli).
+func NewDockerClient(engine string) (DockerClient, error) {
+	switch engine {
+	case "", EngineCli, EngineDocker, EnginePodman, EngineNerdctl:
+		return CliDockerClient{Executable: EngineOf(engine).Executable}, nil
+	case EngineApi:
+		client, err := NewApiDockerClient()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	return nil, fmt.Errorf("unknown engine '%s' (engines: %s, %s, %s, %s, %s)",
+		engine, EngineCli, EngineApi, EngineDocker, EnginePodman, EngineNerdctl)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"testing"
+)
+
+func TestNewDockerClient_Engines(t *testing.T) {
+	tests := []struct {
+		engine     string
+		executable string
+	}{
+		{"", "docker"},
+		{EngineCli, "docker"},
+		{EngineDocker, "docker"},
+		{EnginePodman, "podman"},
+		{EngineNerdctl, "nerdctl"},
+	}
+	for _, tt := range tests {
+		client, err := NewDockerClient(tt.engine)
+		if err != nil {
+			t.Fatalf("NewDockerClient(%q) returned error: %v", tt.engine, err)
+		}
+		cli, ok := client.(CliDockerClient)
+		if !ok {
+			t.Fatalf("NewDockerClient(%q) = %T, want CliDockerClient", tt.engine, client)
+		}
+		if cli.executable() != tt.executable {
+			t.Errorf("NewDockerClient(%q): executable() = %q, want %q", tt.engine, cli.executable(), tt.executable)
+		}
+	}
+
+	// Clients of different engines do not share their CLI
+	podman, _ := NewDockerClient(EnginePodman)
+	_, _ = NewDockerClient(EngineDocker)
+	if executable := podman.(CliDockerClient).executable(); executable != "podman" {
+		t.Errorf("the podman client runs %q after creating a docker client", executable)
+	}
+
+	if _, err := NewDockerClient("lxc"); err == nil {
+		t.Error("expected an error for an unknown engine")
+	}
+}
+
+func TestEngine_Image(t *testing.T) {
+	podman := EngineOf(EnginePodman)
+	tests := map[string]string{
+		"docker:dind":                   "docker.io/library/docker:dind",
+		"nawaman/codingbooth:base":      "docker.io/nawaman/codingbooth:base",
+		"ghcr.io/nawaman/booth:1":       "ghcr.io/nawaman/booth:1",
+		"localhost:5000/booth":          "localhost:5000/booth",
+		"localhost/codingbooth-local:1": "localhost/codingbooth-local:1",
+	}
+	for image, want := range tests {
+		if got := podman.Image(image); got != want {
+			t.Errorf("podman Image(%q) = %q, want %q", image, got, want)
+		}
+	}
+	if got := EngineOf(EngineDocker).Image("docker:dind"); got != "docker:dind" {
+		t.Errorf("docker Image() = %q", got)
+	}
+}
+
+func TestEngine_IsDesktop(t *testing.T) {
+	client := NewRecordingDockerClient()
+	client.Respond = func(call DockerCall) (string, error) {
+		return "Docker Desktop\n", nil
+	}
+
+	if !EngineOf(EngineDocker).IsDesktop(client, DockerFlags{}) {
+		t.Error("expected Docker Desktop to be detected")
+	}
+	if EngineOf(EnginePodman).IsDesktop(client, DockerFlags{}) {
+		t.Error("podman checks ServiceIsRemote, not the OS name")
+	}
+	if EngineOf(EngineDocker).IsDesktop(client, DockerFlags{Dryrun: true}) {
+		t.Error("dryrun should not detect anything")
+	}
+
+	want := []string{
+		"docker info --format {{.OperatingSystem}}",
+		"docker info --format {{.Host.ServiceIsRemote}}",
+	}
+	got := client.Commands()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Commands() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// DockerCall is one docker command recorded by RecordingDockerClient.
+type DockerCall struct {
+	Subcommand string
+	Args       []string
+	Flags      DockerFlags
+}
+
+// String returns the call as a command line (e.g. "docker run --rm alpine").
+func (call DockerCall) String() string {
+	return strings.Join(append([]string{"docker", call.Subcommand}, call.Args...), " ")
+}
+
+// RecordingDockerClient is an in-memory DockerClient that records the commands instead of running them.
+// Respond (optional) gives the output and error of each call; by default calls succeed with no output.
+type RecordingDockerClient struct {
+	Respond func(call DockerCall) (string, error)
+
+	lock  sync.Mutex
+	calls []DockerCall
+}
+
+// NewRecordingDockerClient creates a RecordingDockerClient where every call succeeds with no output.
+func NewRecordingDockerClient() *RecordingDockerClient {
+	return &RecordingDockerClient{}
+}
+
+// Calls returns the recorded calls in order.
+func (client *RecordingDockerClient) Calls() []DockerCall {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	return append([]DockerCall{}, client.calls...)
+}
+
+// Commands returns the recorded calls as command lines.
+func (client *RecordingDockerClient) Commands() []string {
+	commands := []string{}
+	for _, call := range client.Calls() {
+		commands = append(commands, call.String())
+	}
+	return commands
+}
+
+// Reset forgets the recorded calls.
+func (client *RecordingDockerClient) Reset() {
+	client.lock.Lock()
+	defer client.lock.Unlock()
+	client.calls = nil
+}
+
+func (client *RecordingDockerClient) record(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error) {
+	call := DockerCall{Subcommand: subcommand, Flags: flags}
+	args.Range(func(_ int, group ilist.List[string]) bool {
+		call.Args = append(call.Args, group.Slice()...)
+		return true
+	})
+
+	client.lock.Lock()
+	client.calls = append(client.calls, call)
+	respond := client.Respond
+	client.lock.Unlock()
+
+	if respond == nil {
+		return "", nil
+	}
+	return respond(call)
+}
+
+func (client *RecordingDockerClient) Run(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	_, err := client.record(flags, "run", args)
+	return err
+}
+
+func (client *RecordingDockerClient) Build(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	_, err := client.record(flags, "build", args)
+	return err
+}
+
+func (client *RecordingDockerClient) Pull(flags DockerFlags, image string) error {
+	_, err := client.record(flags, "pull", ilist.NewList(ilist.NewList(image)))
+	return err
+}
+
+func (client *RecordingDockerClient) Inspect(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.record(flags, "inspect", args)
+}
+
+func (client *RecordingDockerClient) Network(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.record(flags, "network", args)
+}
+
+func (client *RecordingDockerClient) Ps(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.record(flags, "ps", args)
+}
+
+func (client *RecordingDockerClient) Stop(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	_, err := client.record(flags, "stop", args)
+	return err
+}
+
+func (client *RecordingDockerClient) Command(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) error {
+	_, err := client.record(flags, subcommand, args)
+	return err
+}
+
+func (client *RecordingDockerClient) Output(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.record(flags, subcommand, args)
+}
+
+func (client *RecordingDockerClient) Stream(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]], stdout io.Writer, stderr io.Writer) error {
+	output, err := client.record(flags, subcommand, args)
+	if output != "" {
+		io.WriteString(stdout, output)
+	}
+	return err
+}
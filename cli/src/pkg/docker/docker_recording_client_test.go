@@ -0,0 +1,57 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/docker"
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+func TestRecordingDockerClient(t *testing.T) {
+	recorder := docker.NewRecordingDockerClient()
+	recorder.Respond = func(call docker.DockerCall) (string, error) {
+		switch call.Subcommand {
+		case "ps":
+			return "booth-1\n", nil
+		case "pull":
+			return "", errors.New("no network")
+		}
+		return "", nil
+	}
+
+	var client docker.DockerClient = recorder
+	flags := docker.DockerFlags{Silent: true}
+
+	output, err := client.Ps(flags, ilist.NewList(ilist.NewList("--format", "{{.Names}}")))
+	if err != nil || output != "booth-1\n" {
+		t.Errorf("Ps() = %q, %v", output, err)
+	}
+	if err := client.Pull(flags, "alpine"); err == nil {
+		t.Error("expected Pull() to fail")
+	}
+	var stdout bytes.Buffer
+	if err := client.Stream(flags, "ps", ilist.NewList[ilist.List[string]](), &stdout, &stdout); err != nil || stdout.String() != "booth-1\n" {
+		t.Errorf("Stream() wrote %q, %v", stdout.String(), err)
+	}
+
+	want := []string{
+		"docker ps --format {{.Names}}",
+		"docker pull alpine",
+		"docker ps",
+	}
+	if got := recorder.Commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands() = %q, want %q", got, want)
+	}
+
+	recorder.Reset()
+	if len(recorder.Calls()) != 0 {
+		t.Error("expected no calls after Reset()")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// DockerStream executes a docker command, writing its stdout and stderr to the given writers as they come.
+// This is useful for commands like "docker logs --follow" whose output is processed line by line.
+// The function respects Dryrun and Verbose flags for printing (TTY flags are not added).
+func DockerStream(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]], stdout io.Writer, stderr io.Writer) error {
+	return runDockerStream("docker", flags, subcommand, args, stdout, stderr)
+}
+
+// runDockerStream is DockerStream running the given CLI (docker, podman or nerdctl).
+func runDockerStream(executable string, flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]], stdout io.Writer, stderr io.Writer) error {
+	if flags.Dryrun || flags.Verbose {
+		var printingArgs [][]string
+		printingArgs = append(printingArgs, []string{subcommand})
+		args.Range(func(_ int, group ilist.List[string]) bool {
+			filtered := filterTTYFlags(group.Slice())
+			if len(filtered) > 0 {
+				printingArgs = append(printingArgs, filtered)
+			}
+			return true
+		})
+		printCmd(executable, printingArgs...)
+	}
+
+	if flags.Dryrun {
+		return nil
+	}
+
+	cmdArgs := make([]string, 0, 64)
+	cmdArgs = append(cmdArgs, subcommand)
+	args.Range(func(_ int, group ilist.List[string]) bool {
+		cmdArgs = append(cmdArgs, filterTTYFlags(group.Slice())...)
+		return true
+	})
+
+	cmd := exec.Command(executable, cmdArgs...)
+	cmd.Env = append(os.Environ(), "MSYS_NO_PATHCONV=1")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// Run and propagate exit status
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return &DockerExitError{Subcommand: subcommand, ExitCode: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("docker %s failed: %w", subcommand, err)
+	}
+
+	return nil
+}
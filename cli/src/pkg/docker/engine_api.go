@@ -0,0 +1,223 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// defaultDockerSocket is the Engine API socket used when DOCKER_HOST is not set.
+const defaultDockerSocket = "/var/run/docker.sock"
+
+// EngineAPIError is an error response of the Docker Engine API.
+type EngineAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *EngineAPIError) Error() string {
+	return fmt.Sprintf("docker engine API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether the error is an Engine API "not found" (404) response.
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*EngineAPIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isMissingImage reports whether the error is the "No such image" 404 of a container create
+// (the other 404s, e.g. a missing network, are not fixed by pulling).
+func isMissingImage(err error) bool {
+	return IsNotFound(err) && strings.Contains(strings.ToLower(err.(*EngineAPIError).Message), "no such image")
+}
+
+// engineAPI sends requests to the Docker Engine API over a unix socket or TCP.
+type engineAPI struct {
+	network string
+	address string
+	client  *http.Client
+}
+
+// newEngineAPI creates an engineAPI for the given DOCKER_HOST value (empty: the default unix socket).
+func newEngineAPI(host string) (*engineAPI, error) {
+	network, address := "unix", defaultDockerSocket
+	switch {
+	case host == "":
+	case strings.HasPrefix(host, "unix://"):
+		address = strings.TrimPrefix(host, "unix://")
+	case strings.HasPrefix(host, "tcp://"):
+		network, address = "tcp", strings.TrimPrefix(host, "tcp://")
+	default:
+		return nil, fmt.Errorf("the api engine does not support DOCKER_HOST=%s (use unix:// or tcp://)", host)
+	}
+
+	api := &engineAPI{network: network, address: address}
+	api.client = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return api.dial(ctx)
+			},
+		},
+	}
+	return api, nil
+}
+
+func (api *engineAPI) dial(ctx context.Context) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, api.network, api.address)
+}
+
+// newRequest creates a request for an API path (e.g. "/containers/json").
+func (api *engineAPI) newRequest(method string, path string, query url.Values, body any) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := "http://docker" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	request, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return request, nil
+}
+
+// do sends a request and returns the response; non-2xx responses are returned as EngineAPIError.
+func (api *engineAPI) do(method string, path string, query url.Values, body any) (*http.Response, error) {
+	request, err := api.newRequest(method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	response, err := api.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to the docker engine at %s: %w", api.address, err)
+	}
+	if response.StatusCode >= 300 && response.StatusCode != http.StatusNotModified {
+		defer response.Body.Close()
+		return nil, readAPIError(method, path, response)
+	}
+	return response, nil
+}
+
+// call sends a request and decodes the JSON response into out (if not nil).
+func (api *engineAPI) call(method string, path string, query url.Values, body any, out any) error {
+	response, err := api.do(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if out == nil || response.StatusCode == http.StatusNoContent || response.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, response.Body)
+		return nil
+	}
+	decoder := json.NewDecoder(response.Body)
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+// hijack sends a request that upgrades the connection to a raw stream (used by attach).
+// The returned reader has the stream output and the connection takes the input.
+func (api *engineAPI) hijack(path string, query url.Values) (net.Conn, *bufio.Reader, error) {
+	request, err := api.newRequest(http.MethodPost, path, query, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", "tcp")
+
+	conn, err := api.dial(context.Background())
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to the docker engine at %s: %w", api.address, err)
+	}
+	if err := request.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols && response.StatusCode != http.StatusOK {
+		defer conn.Close()
+		return nil, nil, readAPIError(http.MethodPost, path, response)
+	}
+	return conn, reader, nil
+}
+
+func readAPIError(method string, path string, response *http.Response) error {
+	data, _ := io.ReadAll(response.Body)
+	message := strings.TrimSpace(string(data))
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Message != "" {
+		message = body.Message
+	}
+	return &EngineAPIError{Method: method, Path: path, StatusCode: response.StatusCode, Message: message}
+}
+
+// closeWrite closes the input side of a hijacked connection (so the container sees EOF on stdin).
+func closeWrite(conn net.Conn) {
+	if closer, ok := conn.(interface{ CloseWrite() error }); ok {
+		_ = closer.CloseWrite()
+	}
+}
+
+// copyMultiplexed splits a non-TTY attach stream (8-byte frame headers) into stdout and stderr.
+func copyMultiplexed(stdout io.Writer, stderr io.Writer, reader io.Reader) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+
+		writer := stdout
+		if header[0] == 2 {
+			writer = stderr
+		}
+		size := int64(header[4])<<24 | int64(header[5])<<16 | int64(header[6])<<8 | int64(header[7])
+		if _, err := io.CopyN(writer, reader, size); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// dockerHost returns the DOCKER_HOST environment variable.
+func dockerHost() string {
+	return os.Getenv("DOCKER_HOST")
+}
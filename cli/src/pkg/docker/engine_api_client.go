@@ -0,0 +1,614 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// ApiDockerClient is the DockerClient that talks to the Docker Engine API (over the unix socket or DOCKER_HOST).
+// run, pull, inspect, image inspect, ps, network, stop, rm and start are done with the API;
+// anything else (build, logs, unsupported flags) falls back to the docker CLI.
+// Dryrun prints the same commands as the CLI client.
+type ApiDockerClient struct {
+	api *engineAPI
+	cli CliDockerClient
+}
+
+// NewApiDockerClient creates an ApiDockerClient for the current DOCKER_HOST.
+func NewApiDockerClient() (*ApiDockerClient, error) {
+	return NewApiDockerClientFor(dockerHost())
+}
+
+// NewApiDockerClientFor creates an ApiDockerClient for the given host (e.g. "unix:///var/run/docker.sock").
+func NewApiDockerClientFor(host string) (*ApiDockerClient, error) {
+	api, err := newEngineAPI(host)
+	if err != nil {
+		return nil, err
+	}
+	return &ApiDockerClient{api: api}, nil
+}
+
+// errNotHandled tells that a command is left to the docker CLI.
+var errNotHandled = errors.New("not handled by the engine API")
+
+func (client *ApiDockerClient) Run(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return client.Command(flags, "run", args)
+}
+
+func (client *ApiDockerClient) Build(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return client.cli.Build(flags, args)
+}
+
+func (client *ApiDockerClient) Pull(flags DockerFlags, image string) error {
+	return client.Command(flags, "pull", ilist.NewList(ilist.NewList(image)))
+}
+
+func (client *ApiDockerClient) Inspect(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.Output(flags, "inspect", args)
+}
+
+func (client *ApiDockerClient) Network(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.Output(flags, "network", args)
+}
+
+func (client *ApiDockerClient) Ps(flags DockerFlags, args ilist.List[ilist.List[string]]) (string, error) {
+	return client.Output(flags, "ps", args)
+}
+
+func (client *ApiDockerClient) Stop(flags DockerFlags, args ilist.List[ilist.List[string]]) error {
+	return client.Command(flags, "stop", args)
+}
+
+func (client *ApiDockerClient) Command(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) error {
+	if flags.Dryrun {
+		return client.cli.Command(flags, subcommand, args)
+	}
+
+	stdout, stderr := io.Writer(os.Stdout), io.Writer(os.Stderr)
+	if flags.Silent {
+		stdout, stderr = io.Discard, io.Discard
+	}
+	streams := runStreams{stdin: os.Stdin, stdout: stdout, stderr: stderr, tty: HasInteractiveTTY()}
+
+	err := client.dispatch(flags, subcommand, flattenGroups(args), streams)
+	if err == errNotHandled {
+		return client.cli.Command(flags, subcommand, args)
+	}
+	return err
+}
+
+func (client *ApiDockerClient) Output(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]]) (string, error) {
+	if flags.Dryrun {
+		return client.cli.Output(flags, subcommand, args)
+	}
+
+	var stdout bytes.Buffer
+	stderr := io.Writer(os.Stderr)
+	if flags.Silent {
+		stderr = io.Discard
+	}
+
+	err := client.dispatch(flags, subcommand, flattenGroups(args), runStreams{stdout: &stdout, stderr: stderr})
+	if err == errNotHandled {
+		return client.cli.Output(flags, subcommand, args)
+	}
+	return stdout.String(), err
+}
+
+func (client *ApiDockerClient) Stream(flags DockerFlags, subcommand string, args ilist.List[ilist.List[string]], stdout io.Writer, stderr io.Writer) error {
+	if flags.Dryrun {
+		return client.cli.Stream(flags, subcommand, args, stdout, stderr)
+	}
+
+	err := client.dispatch(flags, subcommand, flattenGroups(args), runStreams{stdout: stdout, stderr: stderr})
+	if err == errNotHandled {
+		return client.cli.Stream(flags, subcommand, args, stdout, stderr)
+	}
+	return err
+}
+
+// dispatch runs a docker command with the API, or returns errNotHandled.
+func (client *ApiDockerClient) dispatch(flags DockerFlags, subcommand string, args []string, streams runStreams) error {
+	var run func() error
+	switch subcommand {
+	case "run":
+		spec, err := parseRunSpec(args)
+		if err != nil {
+			var unsupported *errUnsupportedRunArgs
+			if errors.As(err, &unsupported) {
+				return errNotHandled
+			}
+			return err
+		}
+		run = func() error { return client.api.runContainer(spec, streams) }
+	case "pull":
+		if len(args) != 1 {
+			return errNotHandled
+		}
+		run = func() error { return client.api.pullImage(args[0], streams.stdout) }
+	case "inspect":
+		run = func() error { return client.inspect(args, streams.stdout, false) }
+	case "image":
+		if len(args) == 0 || args[0] != "inspect" {
+			return errNotHandled
+		}
+		run = func() error { return client.inspect(args[1:], streams.stdout, true) }
+	case "ps":
+		run = func() error { return client.ps(args, streams.stdout) }
+	case "network":
+		run = func() error { return client.network(args, streams.stdout) }
+	case "stop":
+		run = func() error { return client.stop(args, streams.stdout) }
+	case "rm":
+		run = func() error { return client.remove(args, streams.stdout) }
+	case "start":
+		run = func() error { return client.start(args, streams.stdout) }
+	default:
+		return errNotHandled
+	}
+
+	if flags.Verbose {
+		printCmd("docker", []string{subcommand}, args)
+	}
+	return run()
+}
+
+// options is a parsed docker command line: boolean flags, flag values and positional arguments.
+type options struct {
+	bools  map[string]bool
+	values map[string][]string
+	names  []string
+}
+
+// parseOptions parses args; valueFlags and boolFlags map every accepted spelling to its canonical name.
+// It returns errNotHandled for anything else.
+func parseOptions(args []string, valueFlags map[string]string, boolFlags map[string]string) (options, error) {
+	parsed := options{bools: map[string]bool{}, values: map[string][]string{}}
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			parsed.names = append(parsed.names, arg)
+			continue
+		}
+
+		flag, value, hasValue := strings.Cut(arg, "=")
+		if name, ok := valueFlags[flag]; ok {
+			if !hasValue {
+				if index+1 >= len(args) {
+					return parsed, fmt.Errorf("%s requires a value", flag)
+				}
+				index++
+				value = args[index]
+			}
+			parsed.values[name] = append(parsed.values[name], value)
+			continue
+		}
+		if name, ok := boolFlags[arg]; ok {
+			parsed.bools[name] = true
+			continue
+		}
+
+		// Combined short flags (e.g. -aq)
+		if !strings.HasPrefix(arg, "--") && len(arg) > 2 {
+			for _, letter := range arg[1:] {
+				name, ok := boolFlags["-"+string(letter)]
+				if !ok {
+					return parsed, errNotHandled
+				}
+				parsed.bools[name] = true
+			}
+			continue
+		}
+		return parsed, errNotHandled
+	}
+	return parsed, nil
+}
+
+func (parsed options) value(name string) string {
+	values := parsed.values[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// inspect runs `docker inspect` (containers first, then images) or `docker image inspect`.
+func (client *ApiDockerClient) inspect(args []string, output io.Writer, imagesOnly bool) error {
+	parsed, err := parseOptions(args, map[string]string{"-f": "format", "--format": "format", "--type": "type"}, nil)
+	if err != nil {
+		return err
+	}
+	if len(parsed.names) == 0 {
+		return fmt.Errorf("inspect requires at least one name")
+	}
+	kind := parsed.value("type")
+	if imagesOnly {
+		kind = "image"
+	}
+	if kind != "" && kind != "container" && kind != "image" {
+		return errNotHandled
+	}
+
+	var objects []any
+	for _, name := range parsed.names {
+		var object any
+		err := errNotHandled
+		if kind != "image" {
+			err = client.api.call(http.MethodGet, "/containers/"+url.PathEscape(name)+"/json", nil, nil, &object)
+		}
+		if kind == "image" || (kind == "" && IsNotFound(err)) {
+			err = client.api.call(http.MethodGet, "/images/"+url.PathEscape(name)+"/json", nil, nil, &object)
+		}
+		if err != nil {
+			return err
+		}
+		objects = append(objects, object)
+	}
+
+	format := parsed.value("format")
+	if format == "" {
+		data, err := json.MarshalIndent(objects, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(output, "%s\n", data)
+		return err
+	}
+	return writeFormatted(output, format, objects)
+}
+
+// psEntry is a container as shown by `docker ps` (the fields of its --format templates).
+type psEntry struct {
+	ID        string
+	Image     string
+	Command   string
+	CreatedAt string
+	Ports     string
+	State     string
+	Status    string
+	Names     string
+	Labels    string
+	Networks  string
+	labels    map[string]string
+}
+
+// Label returns the value of a label (used as `{{.Label "name"}}`).
+func (entry psEntry) Label(name string) string {
+	return entry.labels[name]
+}
+
+type apiContainer struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	Command string            `json:"Command"`
+	Created int64             `json:"Created"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+	NetworkSettings struct {
+		Networks map[string]any `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+func (container apiContainer) entry(noTrunc bool) psEntry {
+	entry := psEntry{
+		ID:        container.ID,
+		Image:     container.Image,
+		Command:   fmt.Sprintf("%q", container.Command),
+		CreatedAt: time.Unix(container.Created, 0).Format("2006-01-02 15:04:05 -0700 MST"),
+		State:     container.State,
+		Status:    container.Status,
+		labels:    container.Labels,
+	}
+	if !noTrunc && len(entry.ID) > 12 {
+		entry.ID = entry.ID[:12]
+	}
+
+	names := []string{}
+	for _, name := range container.Names {
+		names = append(names, strings.TrimPrefix(name, "/"))
+	}
+	entry.Names = strings.Join(names, ",")
+
+	ports := []string{}
+	for _, port := range container.Ports {
+		if port.PublicPort == 0 {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
+			continue
+		}
+		ip := port.IP
+		if strings.Contains(ip, ":") {
+			ip = "[" + ip + "]"
+		}
+		ports = append(ports, fmt.Sprintf("%s:%d->%d/%s", ip, port.PublicPort, port.PrivatePort, port.Type))
+	}
+	entry.Ports = strings.Join(ports, ", ")
+
+	labels := []string{}
+	for key, value := range container.Labels {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	entry.Labels = strings.Join(labels, ",")
+
+	networks := []string{}
+	for name := range container.NetworkSettings.Networks {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	entry.Networks = strings.Join(networks, ",")
+	return entry
+}
+
+// ps runs `docker ps` with -a, -q, --no-trunc, --filter and --format.
+func (client *ApiDockerClient) ps(args []string, output io.Writer) error {
+	parsed, err := parseOptions(args,
+		map[string]string{"-f": "filter", "--filter": "filter", "--format": "format"},
+		map[string]string{"-a": "all", "--all": "all", "-q": "quiet", "--quiet": "quiet", "--no-trunc": "no-trunc"})
+	if err != nil {
+		return err
+	}
+	if len(parsed.names) > 0 || strings.HasPrefix(parsed.value("format"), "table") {
+		return errNotHandled
+	}
+
+	query := url.Values{}
+	if parsed.bools["all"] {
+		query.Set("all", "1")
+	}
+	if filters := filterParam(parsed.values["filter"]); filters != "" {
+		query.Set("filters", filters)
+	}
+
+	var containers []apiContainer
+	if err := client.api.call(http.MethodGet, "/containers/json", query, nil, &containers); err != nil {
+		return err
+	}
+
+	entries := []any{}
+	for _, container := range containers {
+		entries = append(entries, container.entry(parsed.bools["no-trunc"]))
+	}
+
+	format := parsed.value("format")
+	switch {
+	case parsed.bools["quiet"]:
+		format = "{{.ID}}"
+	case format == "":
+		format = "{{.ID}}\t{{.Image}}\t{{.Command}}\t{{.CreatedAt}}\t{{.Status}}\t{{.Ports}}\t{{.Names}}"
+	}
+	return writeFormatted(output, format, entries)
+}
+
+// networkEntry is a network as shown by `docker network ls`.
+type networkEntry struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Driver string `json:"Driver"`
+	Scope  string `json:"Scope"`
+}
+
+// network runs `docker network inspect|create|rm|ls`.
+func (client *ApiDockerClient) network(args []string, output io.Writer) error {
+	if len(args) == 0 {
+		return errNotHandled
+	}
+
+	switch args[0] {
+	case "inspect":
+		parsed, err := parseOptions(args[1:], map[string]string{"-f": "format", "--format": "format"}, nil)
+		if err != nil {
+			return err
+		}
+		var objects []any
+		for _, name := range parsed.names {
+			var object any
+			if err := client.api.call(http.MethodGet, "/networks/"+url.PathEscape(name), nil, nil, &object); err != nil {
+				return err
+			}
+			objects = append(objects, object)
+		}
+		if parsed.value("format") != "" {
+			return writeFormatted(output, parsed.value("format"), objects)
+		}
+		data, err := json.MarshalIndent(objects, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(output, "%s\n", data)
+		return err
+
+	case "create":
+		parsed, err := parseOptions(args[1:], map[string]string{"-d": "driver", "--driver": "driver"}, nil)
+		if err != nil {
+			return err
+		}
+		if len(parsed.names) != 1 {
+			return fmt.Errorf("network create requires exactly one name")
+		}
+		body := map[string]any{"Name": parsed.names[0], "CheckDuplicate": true}
+		if driver := parsed.value("driver"); driver != "" {
+			body["Driver"] = driver
+		}
+		var created networkEntry
+		if err := client.api.call(http.MethodPost, "/networks/create", nil, body, &created); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(output, created.ID)
+		return err
+
+	case "rm", "remove":
+		for _, name := range args[1:] {
+			if strings.HasPrefix(name, "-") {
+				return errNotHandled
+			}
+			if err := client.api.call(http.MethodDelete, "/networks/"+url.PathEscape(name), nil, nil, nil); err != nil {
+				return err
+			}
+			fmt.Fprintln(output, name)
+		}
+		return nil
+
+	case "ls", "list":
+		parsed, err := parseOptions(args[1:],
+			map[string]string{"-f": "filter", "--filter": "filter", "--format": "format"},
+			map[string]string{"-q": "quiet", "--quiet": "quiet"})
+		if err != nil {
+			return err
+		}
+		query := url.Values{}
+		if filters := filterParam(parsed.values["filter"]); filters != "" {
+			query.Set("filters", filters)
+		}
+		var networks []networkEntry
+		if err := client.api.call(http.MethodGet, "/networks", query, nil, &networks); err != nil {
+			return err
+		}
+		entries := []any{}
+		for _, network := range networks {
+			entries = append(entries, network)
+		}
+		format := parsed.value("format")
+		switch {
+		case parsed.bools["quiet"]:
+			format = "{{.ID}}"
+		case format == "":
+			format = "{{.ID}}\t{{.Name}}\t{{.Driver}}\t{{.Scope}}"
+		}
+		return writeFormatted(output, format, entries)
+	}
+	return errNotHandled
+}
+
+// stop runs `docker stop [-t seconds] <names>`.
+func (client *ApiDockerClient) stop(args []string, output io.Writer) error {
+	parsed, err := parseOptions(args, map[string]string{"-t": "time", "--time": "time", "--timeout": "time"}, nil)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	if timeout := parsed.value("time"); timeout != "" {
+		query.Set("t", timeout)
+	}
+	for _, name := range parsed.names {
+		if err := client.api.call(http.MethodPost, "/containers/"+url.PathEscape(name)+"/stop", query, nil, nil); err != nil {
+			return err
+		}
+		fmt.Fprintln(output, name)
+	}
+	return nil
+}
+
+// remove runs `docker rm [-f] [-v] <names>`.
+func (client *ApiDockerClient) remove(args []string, output io.Writer) error {
+	parsed, err := parseOptions(args, nil, map[string]string{"-f": "force", "--force": "force", "-v": "volumes", "--volumes": "volumes"})
+	if err != nil {
+		return err
+	}
+	query := url.Values{"force": {boolParam(parsed.bools["force"])}, "v": {boolParam(parsed.bools["volumes"])}}
+	for _, name := range parsed.names {
+		if err := client.api.call(http.MethodDelete, "/containers/"+url.PathEscape(name), query, nil, nil); err != nil {
+			return err
+		}
+		fmt.Fprintln(output, name)
+	}
+	return nil
+}
+
+// start runs `docker start <names>` (attached starts are left to the CLI).
+func (client *ApiDockerClient) start(args []string, output io.Writer) error {
+	parsed, err := parseOptions(args, nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, name := range parsed.names {
+		if err := client.api.call(http.MethodPost, "/containers/"+url.PathEscape(name)+"/start", nil, nil, nil); err != nil {
+			return err
+		}
+		fmt.Fprintln(output, name)
+	}
+	return nil
+}
+
+// filterParam converts `--filter key=value` options to the API filters JSON.
+func filterParam(filters []string) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	grouped := map[string][]string{}
+	for _, filter := range filters {
+		key, value, _ := strings.Cut(filter, "=")
+		grouped[key] = append(grouped[key], value)
+	}
+	data, _ := json.Marshal(grouped)
+	return string(data)
+}
+
+// formatFuncs are the template functions of docker --format templates.
+var formatFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"split": strings.Split,
+	"title": func(value string) string {
+		if value == "" {
+			return value
+		}
+		return strings.ToUpper(value[:1]) + value[1:]
+	},
+}
+
+// writeFormatted writes each object with a docker --format template, one per line.
+func writeFormatted(output io.Writer, format string, objects []any) error {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	parsed, err := template.New("format").Funcs(formatFuncs).Option("missingkey=zero").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+	for _, object := range objects {
+		var line bytes.Buffer
+		if err := parsed.Execute(&line, object); err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+		if _, err := fmt.Fprintln(output, line.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func flattenGroups(args ilist.List[ilist.List[string]]) []string {
+	flattened := []string{}
+	args.Range(func(_ int, group ilist.List[string]) bool {
+		flattened = append(flattened, group.Slice()...)
+		return true
+	})
+	return flattened
+}
@@ -0,0 +1,325 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nawaman/codingbooth/src/pkg/ilist"
+)
+
+// engineStub is a fake Docker Engine API served on a unix socket.
+type engineStub struct {
+	lock     sync.Mutex
+	requests []string
+	bodies   map[string]string
+}
+
+func (stub *engineStub) record(request *http.Request) {
+	body, _ := io.ReadAll(request.Body)
+	stub.lock.Lock()
+	defer stub.lock.Unlock()
+	line := request.Method + " " + request.URL.Path
+	if request.URL.RawQuery != "" {
+		line += "?" + request.URL.RawQuery
+	}
+	stub.requests = append(stub.requests, line)
+	if len(body) > 0 {
+		stub.bodies[request.Method+" "+request.URL.Path] = string(body)
+	}
+}
+
+func (stub *engineStub) Requests() []string {
+	stub.lock.Lock()
+	defer stub.lock.Unlock()
+	return append([]string{}, stub.requests...)
+}
+
+// startEngineStub serves the handler on a unix socket and returns an ApiDockerClient for it.
+func startEngineStub(t *testing.T, handle func(stub *engineStub, writer http.ResponseWriter, request *http.Request)) (*ApiDockerClient, *engineStub) {
+	t.Helper()
+
+	// Unix socket paths are limited in length, so t.TempDir() may be too long.
+	dir, err := os.MkdirTemp("", "cb-engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "docker.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets are not available: %v", err)
+	}
+
+	stub := &engineStub{bodies: map[string]string{}}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		stub.record(request)
+		handle(stub, writer, request)
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	client, err := NewApiDockerClientFor("unix://" + socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, stub
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(value)
+}
+
+// frame is one multiplexed attach frame (stream 1: stdout, 2: stderr).
+func frame(stream byte, text string) []byte {
+	size := len(text)
+	header := []byte{stream, 0, 0, 0, byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size)}
+	return append(header, text...)
+}
+
+func TestApiDockerClient_PsAndInspect(t *testing.T) {
+	client, stub := startEngineStub(t, func(_ *engineStub, writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/containers/json":
+			writeJSON(writer, http.StatusOK, []map[string]any{{
+				"Id":     "0123456789abcdef0123",
+				"Names":  []string{"/my-booth"},
+				"Image":  "nawaman/codingbooth:base",
+				"State":  "running",
+				"Labels": map[string]string{"cb.port": "10000"},
+				"Ports":  []map[string]any{{"IP": "0.0.0.0", "PrivatePort": 10000, "PublicPort": 10000, "Type": "tcp"}},
+			}})
+		case "/containers/my-booth/json":
+			writeJSON(writer, http.StatusOK, map[string]any{"Id": "0123", "State": map[string]any{"Running": true}})
+		case "/containers/alpine/json":
+			writeJSON(writer, http.StatusNotFound, map[string]string{"message": "No such container: alpine"})
+		case "/images/alpine/json":
+			writeJSON(writer, http.StatusOK, map[string]any{"Id": "sha256:abc", "Size": 7797760})
+		default:
+			writeJSON(writer, http.StatusNotFound, map[string]string{"message": "page not found"})
+		}
+	})
+	flags := DockerFlags{Silent: true}
+
+	output, err := client.Ps(flags, ilist.NewList(
+		ilist.NewList("-aq", "--filter", "label=cb.port", "--filter", "name=my-booth")))
+	if err != nil || output != "0123456789ab\n" {
+		t.Errorf("ps -q = %q, %v", output, err)
+	}
+	output, err = client.Ps(flags, ilist.NewList(ilist.NewList("--format", `{{.Names}}\t{{.Ports}}\t{{.Label "cb.port"}}`)))
+	if err != nil || output != "my-booth\t0.0.0.0:10000->10000/tcp\t10000\n" {
+		t.Errorf("ps --format = %q, %v", output, err)
+	}
+
+	output, err = client.Inspect(flags, ilist.NewList(ilist.NewList("-f", "{{.State.Running}}", "my-booth")))
+	if err != nil || output != "true\n" {
+		t.Errorf("inspect container = %q, %v", output, err)
+	}
+	output, err = client.Inspect(flags, ilist.NewList(ilist.NewList("--format", "{{.Size}}", "alpine")))
+	if err != nil || output != "7797760\n" {
+		t.Errorf("inspect image = %q, %v", output, err)
+	}
+
+	requests := stub.Requests()
+	wantFilters := `filters=%7B%22label%22%3A%5B%22cb.port%22%5D%2C%22name%22%3A%5B%22my-booth%22%5D%7D`
+	if !strings.Contains(requests[0], "all=1") || !strings.Contains(requests[0], wantFilters) {
+		t.Errorf("ps request = %q", requests[0])
+	}
+}
+
+func TestApiDockerClient_Errors(t *testing.T) {
+	client, _ := startEngineStub(t, func(_ *engineStub, writer http.ResponseWriter, request *http.Request) {
+		writeJSON(writer, http.StatusConflict, map[string]string{"message": "network with name booth already exists"})
+	})
+
+	_, err := client.Network(DockerFlags{Silent: true}, ilist.NewList(ilist.NewList("create", "booth")))
+
+	var apiErr *EngineAPIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected EngineAPIError, got %v", err)
+	}
+	want := EngineAPIError{Method: "POST", Path: "/networks/create", StatusCode: 409, Message: "network with name booth already exists"}
+	if *apiErr != want {
+		t.Errorf("error = %+v, want %+v", *apiErr, want)
+	}
+	if IsNotFound(err) {
+		t.Error("a conflict is not a not-found")
+	}
+}
+
+func TestApiDockerClient_Run(t *testing.T) {
+	client, stub := startEngineStub(t, func(stub *engineStub, writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/containers/create":
+			// The image is missing until it is pulled
+			if !strings.Contains(strings.Join(stub.Requests(), "\n"), "/images/create") {
+				writeJSON(writer, http.StatusNotFound, map[string]string{"message": "No such image: alpine:3"})
+				return
+			}
+			writeJSON(writer, http.StatusCreated, map[string]string{"Id": "c1"})
+		case "/images/create":
+			writeJSON(writer, http.StatusOK, map[string]string{"status": "Pulled"})
+		case "/containers/c1/attach":
+			conn, buffer, err := writer.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			buffer.WriteString("HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.raw-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+			buffer.Write(frame(1, "hello\n"))
+			buffer.Write(frame(2, "oops\n"))
+			buffer.Flush()
+		case "/containers/c1/wait":
+			writeJSON(writer, http.StatusOK, map[string]any{"StatusCode": 3})
+		case "/containers/c1/start":
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(writer, http.StatusNotFound, map[string]string{"message": "page not found"})
+		}
+	})
+
+	var stdout, stderr bytes.Buffer
+	err := client.Stream(DockerFlags{}, "run", ilist.NewList(
+		ilist.NewList("--rm", "--name", "my-booth", "-e", "CB_PORT=10000", "-p", "10000:10000"),
+		ilist.NewList("alpine:3", "sh", "-c", "exit 3")), &stdout, &stderr)
+
+	var exitErr *DockerExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 3 {
+		t.Fatalf("expected exit code 3, got %v", err)
+	}
+	if stdout.String() != "hello\n" || stderr.String() != "oops\n" {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+
+	var created struct {
+		Image      string
+		Cmd        []string
+		Env        []string
+		HostConfig struct {
+			AutoRemove   bool
+			PortBindings map[string][]map[string]string
+		}
+	}
+	if err := json.Unmarshal([]byte(stub.bodies["POST /containers/create"]), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Image != "alpine:3" || !reflect.DeepEqual(created.Cmd, []string{"sh", "-c", "exit 3"}) ||
+		!reflect.DeepEqual(created.Env, []string{"CB_PORT=10000"}) || !created.HostConfig.AutoRemove {
+		t.Errorf("create body = %+v", created)
+	}
+	if binding := created.HostConfig.PortBindings["10000/tcp"]; len(binding) != 1 || binding[0]["HostPort"] != "10000" {
+		t.Errorf("port bindings = %v", created.HostConfig.PortBindings)
+	}
+
+	requests := stub.Requests()
+	if requests[0] != "POST /containers/create?name=my-booth" || requests[1] != "POST /images/create?fromImage=alpine&tag=3" {
+		t.Errorf("requests = %q", requests)
+	}
+}
+
+func TestApiDockerClient_RunOtherNotFound(t *testing.T) {
+	client, stub := startEngineStub(t, func(_ *engineStub, writer http.ResponseWriter, request *http.Request) {
+		writeJSON(writer, http.StatusNotFound, map[string]string{"message": "network my-booth-net not found"})
+	})
+
+	err := client.Run(DockerFlags{}, ilist.NewList(ilist.NewList("--network", "my-booth-net", "alpine:3")))
+
+	if !IsNotFound(err) || !strings.Contains(err.Error(), "network my-booth-net not found") {
+		t.Fatalf("expected the create error, got %v", err)
+	}
+	for _, request := range stub.Requests() {
+		if strings.Contains(request, "/images/create") {
+			t.Errorf("expected no pull for a missing network, got %q", stub.Requests())
+		}
+	}
+}
+
+func TestApiDockerClient_InspectEscapesImage(t *testing.T) {
+	client, _ := startEngineStub(t, func(_ *engineStub, writer http.ResponseWriter, request *http.Request) {
+		if request.URL.EscapedPath() == "/images/ghcr.io%2Fnawaman%2Fbooth:1/json" {
+			writeJSON(writer, http.StatusOK, map[string]any{"Id": "sha256:abc"})
+			return
+		}
+		writeJSON(writer, http.StatusNotFound, map[string]string{"message": "page not found"})
+	})
+
+	output, err := client.Inspect(DockerFlags{Silent: true}, ilist.NewList(
+		ilist.NewList("--type", "image", "--format", "{{.Id}}", "ghcr.io/nawaman/booth:1")))
+	if err != nil || output != "sha256:abc\n" {
+		t.Errorf("inspect image = %q, %v", output, err)
+	}
+}
+
+func TestApiDockerClient_RunRemovesUnstartedContainer(t *testing.T) {
+	client, stub := startEngineStub(t, func(_ *engineStub, writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/containers/create":
+			writeJSON(writer, http.StatusCreated, map[string]string{"Id": "c1"})
+		case "/containers/c1/start":
+			writeJSON(writer, http.StatusInternalServerError, map[string]string{"message": "port is already allocated"})
+		case "/containers/c1":
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(writer, http.StatusNotFound, map[string]string{"message": "page not found"})
+		}
+	})
+
+	err := client.Run(DockerFlags{}, ilist.NewList(ilist.NewList("-d", "-i", "--name", "my-booth", "alpine:3")))
+
+	if err == nil || !strings.Contains(err.Error(), "port is already allocated") {
+		t.Fatalf("expected the start error, got %v", err)
+	}
+	var created struct {
+		OpenStdin   bool
+		StdinOnce   bool
+		AttachStdin bool
+	}
+	if err := json.Unmarshal([]byte(stub.bodies["POST /containers/create"]), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.OpenStdin || created.StdinOnce || created.AttachStdin {
+		t.Errorf("expected no stdin for a detached run, got %+v", created)
+	}
+	requests := stub.Requests()
+	if last := requests[len(requests)-1]; last != "DELETE /containers/c1?force=1" {
+		t.Errorf("expected the created container to be removed, got %q", requests)
+	}
+}
+
+func TestApiDockerClient_FallsBackToCli(t *testing.T) {
+	client, stub := startEngineStub(t, func(_ *engineStub, writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	})
+
+	// Dryrun prints the CLI command and sends nothing to the engine.
+	if err := client.Run(DockerFlags{Dryrun: true, Silent: true}, ilist.NewList(ilist.NewList("--rm", "alpine"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.dispatch(DockerFlags{}, "run", []string{"--cap-add", "SYS_ADMIN", "alpine"}, runStreams{}); err != errNotHandled {
+		t.Errorf("unsupported run flags: %v", err)
+	}
+	if err := client.dispatch(DockerFlags{}, "logs", []string{"my-booth"}, runStreams{}); err != errNotHandled {
+		t.Errorf("logs: %v", err)
+	}
+	if requests := stub.Requests(); len(requests) != 0 {
+		t.Errorf("unexpected requests %q", requests)
+	}
+}
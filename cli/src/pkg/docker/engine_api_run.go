@@ -0,0 +1,452 @@
+// Copyright 2025-2026 : Nawa Manusitthipol
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+
+package docker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// runSpec is a `docker run` command line translated to an Engine API container.
+type runSpec struct {
+	name   string
+	detach bool
+	pull   string
+	config containerConfig
+}
+
+type containerConfig struct {
+	Image        string              `json:"Image"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+	User         string              `json:"User,omitempty"`
+	Tty          bool                `json:"Tty"`
+	OpenStdin    bool                `json:"OpenStdin"`
+	StdinOnce    bool                `json:"StdinOnce"`
+	AttachStdin  bool                `json:"AttachStdin"`
+	AttachStdout bool                `json:"AttachStdout"`
+	AttachStderr bool                `json:"AttachStderr"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	HostConfig   hostConfig          `json:"HostConfig"`
+}
+
+type hostConfig struct {
+	Binds        []string                 `json:"Binds,omitempty"`
+	PortBindings map[string][]portBinding `json:"PortBindings,omitempty"`
+	AutoRemove   bool                     `json:"AutoRemove"`
+	NetworkMode  string                   `json:"NetworkMode,omitempty"`
+	Privileged   bool                     `json:"Privileged"`
+	CgroupnsMode string                   `json:"CgroupnsMode,omitempty"`
+}
+
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// errUnsupportedRunArgs is returned by parseRunSpec for a command line the api engine cannot translate.
+type errUnsupportedRunArgs struct {
+	arg string
+}
+
+func (e *errUnsupportedRunArgs) Error() string {
+	return fmt.Sprintf("unsupported docker run argument: %s", e.arg)
+}
+
+// runValueFlags are the `docker run` flags (with a value) that parseRunSpec understands.
+var runValueFlags = map[string]string{
+	"--name": "--name", "-e": "--env", "--env": "--env", "--env-file": "--env-file",
+	"-v": "--volume", "--volume": "--volume", "-w": "--workdir", "--workdir": "--workdir",
+	"-p": "--publish", "--publish": "--publish", "-l": "--label", "--label": "--label",
+	"--pull": "--pull", "--network": "--network", "--net": "--network", "--cgroupns": "--cgroupns",
+	"-u": "--user", "--user": "--user",
+}
+
+// parseRunSpec translates `docker run` arguments; TTY flags are ignored (the caller decides).
+func parseRunSpec(args []string) (runSpec, error) {
+	spec := runSpec{pull: "missing"}
+	config := &spec.config
+	config.HostConfig.NetworkMode = "default"
+
+	index := 0
+	for ; index < len(args); index++ {
+		arg := args[index]
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if arg == "--" {
+			index++
+			break
+		}
+
+		switch arg {
+		case "-i", "-t", "-it", "-ti", "--interactive", "--tty":
+			continue
+		case "-d", "--detach":
+			spec.detach = true
+			continue
+		case "--rm":
+			config.HostConfig.AutoRemove = true
+			continue
+		case "--privileged":
+			config.HostConfig.Privileged = true
+			continue
+		}
+
+		flag, value, hasValue := strings.Cut(arg, "=")
+		name, known := runValueFlags[flag]
+		if !known {
+			return spec, &errUnsupportedRunArgs{arg: arg}
+		}
+		if !hasValue {
+			if index+1 >= len(args) {
+				return spec, fmt.Errorf("%s requires a value", flag)
+			}
+			index++
+			value = args[index]
+		}
+		if err := spec.apply(name, value); err != nil {
+			return spec, err
+		}
+	}
+
+	if index >= len(args) {
+		return spec, fmt.Errorf("docker run requires an image")
+	}
+	config.Image = args[index]
+	config.Cmd = append([]string{}, args[index+1:]...)
+	return spec, nil
+}
+
+func (spec *runSpec) apply(flag string, value string) error {
+	config := &spec.config
+	switch flag {
+	case "--name":
+		spec.name = value
+	case "--env":
+		if !strings.Contains(value, "=") {
+			// `-e NAME` takes the value from the host environment
+			hostValue, found := os.LookupEnv(value)
+			if !found {
+				return nil
+			}
+			value = value + "=" + hostValue
+		}
+		config.Env = append(config.Env, value)
+	case "--env-file":
+		entries, err := readEnvFile(value)
+		if err != nil {
+			return err
+		}
+		config.Env = append(config.Env, entries...)
+	case "--volume":
+		config.HostConfig.Binds = append(config.HostConfig.Binds, value)
+	case "--workdir":
+		config.WorkingDir = value
+	case "--publish":
+		return spec.publish(value)
+	case "--label":
+		key, labelValue, _ := strings.Cut(value, "=")
+		if config.Labels == nil {
+			config.Labels = map[string]string{}
+		}
+		config.Labels[key] = labelValue
+	case "--pull":
+		spec.pull = value
+	case "--network":
+		config.HostConfig.NetworkMode = value
+	case "--cgroupns":
+		config.HostConfig.CgroupnsMode = value
+	case "--user":
+		config.User = value
+	}
+	return nil
+}
+
+// publish adds a `-p [ip:]host:container[/protocol]` port mapping.
+func (spec *runSpec) publish(mapping string) error {
+	protocol := "tcp"
+	if base, proto, found := strings.Cut(mapping, "/"); found {
+		mapping, protocol = base, proto
+	}
+
+	hostIP := ""
+	if strings.HasPrefix(mapping, "[") {
+		closing := strings.Index(mapping, "]:")
+		if closing < 0 {
+			return &errUnsupportedRunArgs{arg: "-p " + mapping}
+		}
+		hostIP, mapping = mapping[1:closing], mapping[closing+2:]
+	}
+
+	parts := strings.Split(mapping, ":")
+	hostPort, containerPort := "", ""
+	switch len(parts) {
+	case 1:
+		containerPort = parts[0]
+	case 2:
+		hostPort, containerPort = parts[0], parts[1]
+	case 3:
+		hostIP, hostPort, containerPort = parts[0], parts[1], parts[2]
+	default:
+		return &errUnsupportedRunArgs{arg: "-p " + mapping}
+	}
+	if strings.Contains(hostPort, "-") || strings.Contains(containerPort, "-") {
+		// Port ranges are left to the CLI
+		return &errUnsupportedRunArgs{arg: "-p " + mapping}
+	}
+
+	config := &spec.config
+	key := containerPort + "/" + protocol
+	if config.ExposedPorts == nil {
+		config.ExposedPorts = map[string]struct{}{}
+		config.HostConfig.PortBindings = map[string][]portBinding{}
+	}
+	config.ExposedPorts[key] = struct{}{}
+	config.HostConfig.PortBindings[key] = append(config.HostConfig.PortBindings[key], portBinding{HostIP: hostIP, HostPort: hostPort})
+	return nil
+}
+
+// readEnvFile reads the KEY=VALUE lines of a docker env file.
+func readEnvFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.Contains(line, "=") {
+			value, found := os.LookupEnv(line)
+			if !found {
+				continue
+			}
+			line = line + "=" + value
+		}
+		entries = append(entries, line)
+	}
+	return entries, scanner.Err()
+}
+
+// runStreams are the streams a container is attached to (stdin is nil when not interactive).
+type runStreams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	tty    bool
+}
+
+// runContainer creates and starts the container of the spec.
+// Detached containers print their ID (like `docker run -d`); others are attached and their exit code is returned as DockerExitError.
+// The container is removed when it cannot be attached or started.
+func (api *engineAPI) runContainer(spec runSpec, streams runStreams) error {
+	config := spec.config
+	config.Tty = streams.tty
+	config.OpenStdin = streams.stdin != nil && !spec.detach
+	config.StdinOnce = config.OpenStdin
+	config.AttachStdin = config.OpenStdin
+	config.AttachStdout = !spec.detach
+	config.AttachStderr = !spec.detach
+
+	id, err := api.createContainer(spec, config)
+	if err != nil {
+		return err
+	}
+
+	if spec.detach {
+		if err := api.call(http.MethodPost, "/containers/"+id+"/start", nil, nil, nil); err != nil {
+			api.removeContainer(id)
+			return err
+		}
+		fmt.Fprintln(streams.stdout, id)
+		return nil
+	}
+
+	conn, reader, err := api.hijack("/containers/"+id+"/attach", url.Values{
+		"stream": {"1"}, "stdin": {boolParam(config.AttachStdin)}, "stdout": {"1"}, "stderr": {"1"},
+	})
+	if err != nil {
+		api.removeContainer(id)
+		return err
+	}
+	defer conn.Close()
+
+	// Wait must be registered before start, or an auto-removed container may be gone already
+	condition := "next-exit"
+	if config.HostConfig.AutoRemove {
+		condition = "removed"
+	}
+	exitCodes := make(chan containerExit, 1)
+	go func() {
+		exitCodes <- api.waitContainer(id, condition)
+	}()
+
+	if err := api.call(http.MethodPost, "/containers/"+id+"/start", nil, nil, nil); err != nil {
+		api.removeContainer(id)
+		return err
+	}
+
+	if streams.tty && IsStdinTTY() {
+		if state, err := term.MakeRaw(int(os.Stdin.Fd())); err == nil {
+			defer term.Restore(int(os.Stdin.Fd()), state)
+		}
+		if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			_ = api.call(http.MethodPost, "/containers/"+id+"/resize", url.Values{
+				"h": {fmt.Sprint(height)}, "w": {fmt.Sprint(width)},
+			}, nil, nil)
+		}
+	}
+
+	if config.AttachStdin {
+		go func() {
+			_, _ = io.Copy(conn, streams.stdin)
+			closeWrite(conn)
+		}()
+	}
+
+	if streams.tty {
+		_, err = io.Copy(streams.stdout, reader)
+	} else {
+		err = copyMultiplexed(streams.stdout, streams.stderr, reader)
+	}
+	if err != nil {
+		return err
+	}
+
+	exit := <-exitCodes
+	if exit.err != nil {
+		return exit.err
+	}
+	if exit.code != 0 {
+		return &DockerExitError{Subcommand: "run", ExitCode: exit.code}
+	}
+	return nil
+}
+
+// createContainer creates the container, pulling the image first when missing (unless --pull=never).
+func (api *engineAPI) createContainer(spec runSpec, config containerConfig) (string, error) {
+	query := url.Values{}
+	if spec.name != "" {
+		query.Set("name", spec.name)
+	}
+
+	if spec.pull == "always" {
+		if err := api.pullImage(config.Image, io.Discard); err != nil {
+			return "", err
+		}
+	}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	err := api.call(http.MethodPost, "/containers/create", query, config, &created)
+	if isMissingImage(err) && spec.pull == "missing" {
+		if err := api.pullImage(config.Image, io.Discard); err != nil {
+			return "", err
+		}
+		err = api.call(http.MethodPost, "/containers/create", query, config, &created)
+	}
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// removeContainer removes a container of a failed run (created but not started), so the run can be retried with the same name.
+func (api *engineAPI) removeContainer(id string) {
+	_ = api.call(http.MethodDelete, "/containers/"+id, url.Values{"force": {"1"}}, nil, nil)
+}
+
+type containerExit struct {
+	code int
+	err  error
+}
+
+func (api *engineAPI) waitContainer(id string, condition string) containerExit {
+	var result struct {
+		StatusCode int `json:"StatusCode"`
+		Error      *struct {
+			Message string `json:"Message"`
+		} `json:"Error"`
+	}
+	response, err := api.do(http.MethodPost, "/containers/"+id+"/wait", url.Values{"condition": {condition}}, nil)
+	if err != nil {
+		return containerExit{err: err}
+	}
+	defer response.Body.Close()
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return containerExit{err: err}
+	}
+	if result.Error != nil && result.Error.Message != "" {
+		return containerExit{err: fmt.Errorf("waiting for container %s: %s", id, result.Error.Message)}
+	}
+	return containerExit{code: result.StatusCode}
+}
+
+// pullImage pulls an image, writing the progress status lines to output.
+func (api *engineAPI) pullImage(image string, output io.Writer) error {
+	query := url.Values{"fromImage": {image}}
+	if !strings.Contains(image, "@") {
+		slash := strings.LastIndex(image, "/")
+		if colon := strings.LastIndex(image, ":"); colon > slash {
+			query.Set("fromImage", image[:colon])
+			query.Set("tag", image[colon+1:])
+		} else {
+			query.Set("tag", "latest")
+		}
+	}
+
+	response, err := api.do(http.MethodPost, "/images/create", query, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var message struct {
+			Status string `json:"status"`
+			ID     string `json:"id"`
+			Error  string `json:"error"`
+		}
+		if err := decoder.Decode(&message); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if message.Error != "" {
+			return &EngineAPIError{Method: http.MethodPost, Path: "/images/create", StatusCode: response.StatusCode, Message: message.Error}
+		}
+		if message.ID != "" {
+			fmt.Fprintf(output, "%s: %s\n", message.ID, message.Status)
+		} else if message.Status != "" {
+			fmt.Fprintln(output, message.Status)
+		}
+	}
+}
+
+func boolParam(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
@@ -5,6 +5,8 @@
 package ilist
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strings"
 )
@@ -13,23 +15,87 @@ type SemicolonStringList struct {
 	List[string]
 }
 
-// expandEnv expands environment variables and tilde in a string.
-// - ~ at the start of a string is expanded to $HOME
-// - $VAR and ${VAR} are expanded to their environment values
-func expandEnv(s string) string {
-	// Expand ~ at the beginning of the string to $HOME
+// ExpandEnv expands environment variables and tilde in a string (see Interpolate);
+// a missing required variable expands to an empty string.
+func ExpandEnv(s string) string {
+	expanded, _ := Interpolate(s)
+	return expanded
+}
+
+// Interpolate expands the tilde at the start of a string to $HOME, then its environment variables (see ExpandVars).
+func Interpolate(s string) (string, error) {
 	if strings.HasPrefix(s, "~/") {
 		s = "$HOME" + s[1:]
 	} else if s == "~" {
 		s = "$HOME"
 	}
-	// Expand environment variables
-	return os.ExpandEnv(s)
+	return ExpandVars(s)
+}
+
+// ExpandVars expands the environment variables of a string:
+//   - $VAR and ${VAR} are expanded to their environment values (empty if not set)
+//   - ${VAR:-default} is the default when VAR is not set or empty (${VAR-default}: only when not set)
+//   - ${VAR:?message} is an error with the message when VAR is not set or empty (${VAR?message}: only when not set)
+//
+// The default can refer to other variables (e.g. ${AWS_PROFILE:-$USER}).
+// The error lists all the missing required variables.
+func ExpandVars(s string) (string, error) {
+	var errs []error
+	expanded := os.Expand(s, func(reference string) string {
+		value, err := lookupVar(reference)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return value
+	})
+	return expanded, errors.Join(errs...)
+}
+
+// lookupVar returns the value of a variable reference: "VAR" or, in braces, "VAR:-default", "VAR-default",
+// "VAR:?message" or "VAR?message".
+func lookupVar(reference string) (string, error) {
+	index := strings.IndexAny(reference, ":-?")
+	if index < 0 {
+		return os.Getenv(reference), nil
+	}
+	name, operator := reference[:index], reference[index:]
+	value, set := os.LookupEnv(name)
+	missing := !set
+	if rest, found := strings.CutPrefix(operator, ":"); found {
+		missing = missing || value == ""
+		operator = rest
+	}
+
+	switch {
+	case strings.HasPrefix(operator, "-"):
+		if missing {
+			return ExpandVars(operator[1:])
+		}
+	case strings.HasPrefix(operator, "?"):
+		if missing {
+			if message := strings.TrimSpace(operator[1:]); message != "" {
+				return "", fmt.Errorf("required variable %s is not set: %s", name, message)
+			}
+			return "", fmt.Errorf("required variable %s is not set", name)
+		}
+	default:
+		return "", fmt.Errorf("bad variable reference '${%s}' (use ${%s:-default} or ${%s:?message})", reference, name, name)
+	}
+	return value, nil
 }
 
+// Decode implements envconfig.Decoder: the semicolon-separated values are expanded (see ExpandEnv).
 func (s *SemicolonStringList) Decode(value string) error {
+	s.elements = splitSemicolons(value)
+	for i, element := range s.elements {
+		s.elements[i] = ExpandEnv(element)
+	}
+	return nil
+}
+
+// splitSemicolons returns the trimmed, non-empty semicolon-separated values (nil for a blank string).
+func splitSemicolons(value string) []string {
 	if strings.TrimSpace(value) == "" {
-		s.elements = nil
 		return nil
 	}
 
@@ -40,11 +106,9 @@ func (s *SemicolonStringList) Decode(value string) error {
 		if p == "" {
 			continue
 		}
-		out = append(out, expandEnv(p))
+		out = append(out, p)
 	}
-
-	s.elements = out
-	return nil
+	return out
 }
 
 func (s *SemicolonStringList) Clone() SemicolonStringList {
@@ -53,22 +117,26 @@ func (s *SemicolonStringList) Clone() SemicolonStringList {
 
 // UnmarshalTOML implements the toml.Unmarshaler interface.
 // This allows TOML to decode both string values (semicolon-separated) and arrays into a SemicolonStringList.
-// Environment variables ($VAR, ${VAR}) and tilde (~) are automatically expanded.
+// The values are taken as is: the config loader interpolates the config files before decoding them.
+// Other values (and arrays with non-string items) are an error.
 func (s *SemicolonStringList) UnmarshalTOML(data interface{}) error {
 	switch v := data.(type) {
 	case string:
-		return s.Decode(v)
+		s.elements = splitSemicolons(v)
+		return nil
 	case []interface{}:
 		// Handle TOML array
 		out := make([]string, 0, len(v))
-		for _, item := range v {
-			if str, ok := item.(string); ok {
-				out = append(out, expandEnv(str))
+		for i, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("item %d of the array is a %T (%v), not a string", i, item, item)
 			}
+			out = append(out, str)
 		}
 		s.elements = out
 		return nil
 	default:
-		return nil // Let TOML handle type errors
+		return fmt.Errorf("expected a string or an array of strings, got a %T (%v)", data, data)
 	}
 }
@@ -80,11 +80,11 @@ func TestSemicolonStringList_UnmarshalTOML(t *testing.T) {
 		{"SingleValue", "value", []string{"value"}, false},
 		{"MultipleValues", "a;b;c", []string{"a", "b", "c"}, false},
 		{"WithSpaces", " a ; b ; c ", []string{"a", "b", "c"}, false},
-		{"NonString", 123, nil, false}, // Should return nil (let TOML handle type errors)
+		{"NonString", 123, nil, true},
 		// TOML array tests
 		{"Array", []interface{}{"-v", "/host:/container"}, []string{"-v", "/host:/container"}, false},
 		{"EmptyArray", []interface{}{}, []string{}, false},
-		{"ArrayWithMixedTypes", []interface{}{"-e", 123, "VAR=value"}, []string{"-e", "VAR=value"}, false},
+		{"ArrayWithMixedTypes", []interface{}{"-e", 123, "VAR=value"}, nil, true},
 	}
 
 	for _, tt := range tests {
@@ -142,9 +142,46 @@ func TestExpandEnv(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := expandEnv(tt.input)
+			got := ExpandEnv(tt.input)
 			if got != tt.expected {
-				t.Errorf("expandEnv(%q) = %q, want %q", tt.input, got, tt.expected)
+				t.Errorf("ExpandEnv(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandVars(t *testing.T) {
+	t.Setenv("TEST_VAR", "test_value")
+	t.Setenv("EMPTY_VAR", "")
+	os.Unsetenv("UNSET_VAR_XYZ")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      string
+	}{
+		{"Default", "${UNSET_VAR_XYZ:-dev}", "dev", ""},
+		{"DefaultNotUsed", "${TEST_VAR:-dev}", "test_value", ""},
+		{"DefaultWhenEmpty", "${EMPTY_VAR:-dev}", "dev", ""},
+		{"DefaultOnlyWhenUnset", "${EMPTY_VAR-dev}", "", ""},
+		{"DefaultWithVar", "${UNSET_VAR_XYZ:-$TEST_VAR}/x", "test_value/x", ""},
+		{"Required", "${TEST_VAR:?set it}", "test_value", ""},
+		{"RequiredMissing", "a/${UNSET_VAR_XYZ:?set it to the profile}", "a/", "required variable UNSET_VAR_XYZ is not set: set it to the profile"},
+		{"RequiredEmpty", "${EMPTY_VAR:?}", "", "required variable EMPTY_VAR is not set"},
+		{"RequiredOnlyWhenUnset", "${EMPTY_VAR?}", "", ""},
+		{"BadReference", "${TEST_VAR:x}", "", "bad variable reference '${TEST_VAR:x}' (use ${TEST_VAR:-default} or ${TEST_VAR:?message})"},
+		{"NoBraces", "$TEST_VAR:-x", "test_value:-x", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandVars(tt.input)
+			if got != tt.expected {
+				t.Errorf("ExpandVars(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+			if (err == nil && tt.err != "") || (err != nil && err.Error() != tt.err) {
+				t.Errorf("ExpandVars(%q) error = %v, want %q", tt.input, err, tt.err)
 			}
 		})
 	}
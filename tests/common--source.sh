@@ -60,6 +60,7 @@ run_coding_booth() {
 # - Removes single quotes around Windows paths in -v mounts
 # - Normalizes MSYS paths (/c/Users → C:/Users)
 # - Masks UID/GID values to XXXXX for environment independence
+# - Masks the cb.created-at label timestamp to XXXXX
 normalize_output() {
     sed -E \
         -e 's/workspace\.exe/workspace/g' \
@@ -70,7 +71,8 @@ normalize_output() {
         -e "s/HOST_UID=[0-9]+/HOST_UID=XXXXX/g" \
         -e "s/HOST_GID=[0-9]+/HOST_GID=XXXXX/g" \
         -e "s/HOST_UID:[[:space:]]+[0-9]+/HOST_UID:       XXXXX/g" \
-        -e "s/HOST_GID:[[:space:]]+[0-9]+/HOST_GID:       XXXXX/g"
+        -e "s/HOST_GID:[[:space:]]+[0-9]+/HOST_GID:       XXXXX/g" \
+        -e "s/cb\.created-at=[^' ]+/cb.created-at=XXXXX/g"
 }
 
 script_relative_path() {
@@ -15,11 +15,11 @@ strip_ansi() { sed -r 's/\x1B\[[0-9;]*[A-Za-z]//g'; }
 ACTUAL=$(run_coding_booth --config test--config.toml --dryrun 2>&1 | strip_ansi)
 
 # Test 1: Check that all ports are in the DinD sidecar command (docker:dind line)
-if echo "$ACTUAL" | grep "docker:dind" | grep -q "\-p 10000:10000 -p 8080:8080 -p 3000:3000"; then
+if echo "$ACTUAL" | grep "docker:dind" | grep -q "\-p 127.0.0.1:10000:10000 -p 127.0.0.1:8080:8080 -p 127.0.0.1:3000:3000"; then
     print_test_result "true" "$0" "1" "All ports (10000, 8080, 3000) passed to DinD sidecar"
 else
     print_test_result "false" "$0" "1" "All ports (10000, 8080, 3000) passed to DinD sidecar"
-    echo "Expected to find: -p 10000:10000 -p 8080:8080 -p 3000:3000"
+    echo "Expected to find: -p 127.0.0.1:10000:10000 -p 127.0.0.1:8080:8080 -p 127.0.0.1:3000:3000"
     echo "Actual DinD sidecar command:"
     echo "$ACTUAL" | grep "docker:dind" || echo "(docker:dind line not found)"
     exit 1
@@ -51,7 +51,14 @@ docker \\
     -e 'HOST_GID=${HOST_GID}' \\
     -v ${HERE}:/home/coder/code \\
     -w /home/coder/code \\
-    -p 10000:10000 \\
+    -p 127.0.0.1:10000:10000 \\
+    --label 'cb.managed=true' \\
+    --label 'cb.project=dryrun' \\
+    --label 'cb.variant=base' \\
+    --label 'cb.code-path=${HERE}' \\
+    --label 'cb.port=10000' \\
+    --label 'cb.created-at=XXXXX' \\
+    --label 'cb.version=${VERSION}' \\
     -e 'CB_SETUPS=/opt/codingbooth/setups' \\
     -e 'CB_CONTAINER_NAME=dryrun' \\
     -e 'CB_DAEMON=false' \\
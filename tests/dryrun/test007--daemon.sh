@@ -31,6 +31,7 @@ EXPECT="\
 📦 Running booth in daemon mode.
 👉 Stop with Ctrl+C. The container will be removed (--rm) when stop.
 👉 Visit 'http://localhost:10000'
+👉 To open a shell in this booth: coding-booth exec dryrun
 👉 To open an interactive shell instead: coding-booth -- bash
 👉 To stop the running container:
 
@@ -49,7 +50,14 @@ docker \\
     -e 'HOST_GID=${HOST_GID}' \\
     -v ${HERE}:/home/coder/code \\
     -w /home/coder/code \\
-    -p 10000:10000 \\
+    -p 127.0.0.1:10000:10000 \\
+    --label 'cb.managed=true' \\
+    --label 'cb.project=dryrun' \\
+    --label 'cb.variant=base' \\
+    --label 'cb.code-path=${HERE}' \\
+    --label 'cb.port=10000' \\
+    --label 'cb.created-at=XXXXX' \\
+    --label 'cb.version=${VERSION}' \\
     -e 'CB_SETUPS=/opt/codingbooth/setups' \\
     -e 'CB_CONTAINER_NAME=dryrun' \\
     -e 'CB_DAEMON=true' \\
@@ -29,6 +29,7 @@ ACTUAL=$(run_coding_booth --dryrun --code ${WORKSPACE} --variant base -- sleep 1
 ACTUAL=$(printf "%s\n" "$ACTUAL")
 
 VERSION="$(cat ../../version.txt)"
+WORKSPACE_PATH="$(cd ${WORKSPACE} && pwd)"
 
 # Notice that there is not `-rm`
 EXPECT="\
@@ -41,7 +42,14 @@ docker \\
     -e 'HOST_GID=${HOST_GID}' \\
     -v ${WORKSPACE}:/home/coder/code \\
     -w /home/coder/code \\
-    -p 10000:10000 \\
+    -p 127.0.0.1:10000:10000 \\
+    --label 'cb.managed=true' \\
+    --label 'cb.project=tests' \\
+    --label 'cb.variant=base' \\
+    --label 'cb.code-path=${WORKSPACE_PATH}' \\
+    --label 'cb.port=10000' \\
+    --label 'cb.created-at=XXXXX' \\
+    --label 'cb.version=${VERSION}' \\
     -e 'CB_SETUPS=/opt/codingbooth/setups' \\
     -e 'CB_CONTAINER_NAME=tests' \\
     -e 'CB_DAEMON=false' \\